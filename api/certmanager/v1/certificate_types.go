@@ -0,0 +1,62 @@
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CertificateKind is the kind of the cert-manager Certificates
+const CertificateKind = "Certificate"
+
+// +kubebuilder:object:root=true
+
+// Certificate requests a certificate from an issuer, which cert-manager stores in a secret and renews before it expires
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateSpec   `json:"spec,omitempty"`
+	Status CertificateStatus `json:"status,omitempty"`
+}
+
+// CertificateSpec defines the certificate requested from the issuer
+type CertificateSpec struct {
+	CommonName  string           `json:"commonName,omitempty"`
+	Duration    *metav1.Duration `json:"duration,omitempty"`
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	DNSNames    []string         `json:"dnsNames,omitempty"`
+	// Name of the secret the certificate, its private key and the CA are stored in, as tls.crt, tls.key and ca.crt
+	SecretName string                 `json:"secretName"`
+	IssuerRef  ObjectReference        `json:"issuerRef"`
+	PrivateKey *CertificatePrivateKey `json:"privateKey,omitempty"`
+}
+
+// CertificatePrivateKey defines the private key generated for the certificate
+type CertificatePrivateKey struct {
+	// RSA, ECDSA or Ed25519
+	Algorithm string `json:"algorithm,omitempty"`
+	Size      int    `json:"size,omitempty"`
+}
+
+// ObjectReference refers to the Issuer or ClusterIssuer of a certificate
+type ObjectReference struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+// CertificateStatus reports the certificate stored in the secret
+type CertificateStatus struct {
+	NotAfter    *metav1.Time `json:"notAfter,omitempty"`
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CertificateList contains a list of Certificate
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Certificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Certificate{}, &CertificateList{})
+}
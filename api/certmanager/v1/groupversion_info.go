@@ -0,0 +1,21 @@
+// Package v1 contains the subset of the cert-manager.io/v1 API used to request the certificates of the components, so
+// that the operator does not depend on cert-manager and its dependencies
+//+kubebuilder:object:generate=true
+//+kubebuilder:skip
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is group version used to register these objects
+	GroupVersion = schema.GroupVersion{Group: "cert-manager.io", Version: "v1"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
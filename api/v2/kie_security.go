@@ -1,5 +1,9 @@
 package v2
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // KieAppAuthObject Authentication specification to be used by the KieApp
 type KieAppAuthObject struct {
 	SSO  *SSOAuthConfig  `json:"sso,omitempty"`
@@ -32,6 +36,8 @@ type SSOAuthClient struct {
 	// +kubebuilder:validation:Format:=password
 	// Client secret
 	Secret string `json:"secret,omitempty"`
+	// Secret key holding the client secret. Takes precedence over secret.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 	// Client name
 	Name string `json:"name,omitempty"`
 	// Hostname to set as redirect URL
@@ -45,6 +51,8 @@ type LDAPAuthConfig struct {
 	// +kubebuilder:validation:Format:=password
 	// LDAP Credentials used for authentication
 	BindCredential string `json:"bindCredential,omitempty"`
+	// Secret key holding the LDAP Credentials used for authentication. Takes precedence over bindCredential.
+	BindCredentialSecretKeyRef *corev1.SecretKeySelector `json:"bindCredentialSecretKeyRef,omitempty"`
 	// +kubebuilder:validation:Required
 	// LDAP endpoint to connect for authentication. For failover set two or more LDAP endpoints separated by space
 	URL string `json:"url"`
@@ -142,4 +150,17 @@ type ObjRef struct {
 type KieAppTruststore struct {
 	// Set true to use Openshift's CA Bundle as a truststore, instead of java's cacert.
 	OpenshiftCaBundle bool `json:"openshiftCaBundle,omitempty"`
+	// ConfigMap or Secret keys holding PEM encoded CA bundles to add to the truststore, along with Openshift's CA Bundle
+	// when enabled. The truststore is regenerated whenever one of them changes. The components are deployed without
+	// a truststore while none of the CA bundles is available.
+	From []TruststoreSource `json:"from,omitempty"`
+}
+
+// TruststoreSource selects the key of a ConfigMap or Secret, in the namespace of the KieApp, holding a PEM encoded CA bundle.
+// Exactly one of its fields must be set.
+type TruststoreSource struct {
+	// Selects a key of a ConfigMap.
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Selects a key of a Secret.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
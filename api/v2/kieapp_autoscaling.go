@@ -0,0 +1,28 @@
+package v2
+
+import autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+
+// KieServerAutoscaling defines the HorizontalPodAutoscaler generated for each deployment of a kieserver set. The
+// autoscalers are autoscaling/v2beta2 ones, as the k8s.io/api v0.21 the operator builds with predates autoscaling/v2.
+// Kubernetes 1.26 and later no longer serve v2beta2, autoscaling is not available there and KieApps enabling it fail
+// with MissingDependencies.
+type KieServerAutoscaling struct {
+	// Set true to scale the kieserver set with a HorizontalPodAutoscaler, the replicas of the set are then only used
+	// for its initial deployment. Not available for environments that deny scaling the kieservers.
+	Enabled bool `json:"enabled,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// Lower limit for the number of replicas. Defaults to 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// Upper limit for the number of replicas, cannot be lower than minReplicas
+	MaxReplicas int32 `json:"maxReplicas"`
+	// +kubebuilder:validation:Minimum:=1
+	// Average CPU utilization of the pods, as a percentage of their requested CPU, the autoscaler aims for
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// Average memory utilization of the pods, as a percentage of their requested memory, the autoscaler aims for
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+	// Additional metrics, e.g. pods or external metrics served by a custom metrics adapter, the autoscaler aims for.
+	// Defaults to an 80% CPU utilization target when no target is set.
+	Metrics []autoscalingv2beta2.MetricSpec `json:"metrics,omitempty"`
+}
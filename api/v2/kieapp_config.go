@@ -1,5 +1,9 @@
 package v2
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // CommonConfig variables used in the templates
 type CommonConfig struct {
 	// The name of the application deployment.
@@ -7,20 +11,30 @@ type CommonConfig struct {
 	// +kubebuilder:validation:Format:=password
 	// The password to use for keystore generation.
 	KeyStorePassword string `json:"keyStorePassword,omitempty"`
+	// Secret key holding the password to use for keystore generation. Takes precedence over keyStorePassword.
+	KeyStorePasswordSecretKeyRef *corev1.SecretKeySelector `json:"keyStorePasswordSecretKeyRef,omitempty"`
 	// The user to use for the admin.
 	AdminUser string `json:"adminUser,omitempty"`
 	// +kubebuilder:validation:Format:=password
 	// The password to use for the adminUser.
 	AdminPassword string `json:"adminPassword,omitempty"`
+	// Secret key holding the password to use for the adminUser. Takes precedence over adminPassword.
+	AdminPasswordSecretKeyRef *corev1.SecretKeySelector `json:"adminPasswordSecretKeyRef,omitempty"`
 	// +kubebuilder:validation:Format:=password
 	// The password to use for databases.
 	DBPassword string `json:"dbPassword,omitempty"`
+	// Secret key holding the password to use for databases. Takes precedence over dbPassword.
+	DBPasswordSecretKeyRef *corev1.SecretKeySelector `json:"dbPasswordSecretKeyRef,omitempty"`
 	// +kubebuilder:validation:Format:=password
 	// The password to use for amq user.
 	AMQPassword string `json:"amqPassword,omitempty"`
+	// Secret key holding the password to use for amq user. Takes precedence over amqPassword.
+	AMQPasswordSecretKeyRef *corev1.SecretKeySelector `json:"amqPasswordSecretKeyRef,omitempty"`
 	// +kubebuilder:validation:Format:=password
 	// The password to use for amq cluster user.
 	AMQClusterPassword string `json:"amqClusterPassword,omitempty"`
+	// Secret key holding the password to use for amq cluster user. Takes precedence over amqClusterPassword.
+	AMQClusterPasswordSecretKeyRef *corev1.SecretKeySelector `json:"amqClusterPasswordSecretKeyRef,omitempty"`
 	// If set to true, plain text routes will be configured instead using SSL
 	DisableSsl bool `json:"disableSsl,omitempty"`
 	// Startup strategy for Console and Kieserver
@@ -23,9 +23,32 @@ const (
 	RhdmProductionImmutable EnvironmentType = "rhdm-production-immutable"
 )
 
+// SkipSpecDefaultsAnnotation set to "true" on a KieApp prevents the defaulting webhook from writing defaults into its spec.
+// Defaults written into the spec are kept on operator upgrades, so KieApps relying on the defaults of the running
+// operator version should set it.
+const SkipSpecDefaultsAnnotation = "app.kiegroup.org/skip-spec-defaults"
+
 // EnvironmentType describes a possible application environment
 type EnvironmentType string
 
+// PlatformType describes the kind of cluster the application is deployed on
+type PlatformType string
+
+const (
+	// OpenShiftPlatform uses DeploymentConfigs, Routes, ImageStreams and BuildConfigs
+	OpenShiftPlatform PlatformType = "openshift"
+	// KubernetesPlatform uses plain Kubernetes Deployments
+	KubernetesPlatform PlatformType = "kubernetes"
+)
+
+// SecurityContextPreset describes the security context applied to all the pods and containers of the application
+type SecurityContextPreset string
+
+const (
+	// RestrictedSecurityContextPreset complies with the restricted Pod Security standard
+	RestrictedSecurityContextPreset SecurityContextPreset = "restricted"
+)
+
 // EnvironmentConstants stores both the App and Replica Constants for a given environment
 type EnvironmentConstants struct {
 	App      AppConstants     `json:"app,omitempty"`
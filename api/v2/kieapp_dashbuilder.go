@@ -1,5 +1,9 @@
 package v2
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // DashbuilderObject configuration of the RHPAM Dashbuilder
 type DashbuilderObject struct {
 	KieAppObject `json:",inline"`
@@ -95,4 +99,6 @@ type KieServerDataSetOrTemplate struct {
 	Password     string `json:"password,omitempty"`
 	Token        string `json:"token,omitempty"`
 	ReplaceQuery string `json:"replaceQuery,omitempty"`
+	// Secret key holding the password. Takes precedence over password.
+	PasswordSecretKeyRef *corev1.SecretKeySelector `json:"passwordSecretKeyRef,omitempty"`
 }
@@ -1,5 +1,9 @@
 package v2
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // DatabaseType to define what kind of database will be used for the Kie Servers
 type DatabaseType string
 
@@ -31,6 +35,12 @@ type InternalDatabaseObject struct {
 	Size string `json:"size,omitempty"`
 	// The storageClassName to use for database pvc's.
 	StorageClassName string `json:"storageClassName,omitempty"`
+	// PodSecurityContext whose fields override the ones of the database pods
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// SecurityContext whose fields override the ones of the database containers
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// Probes overriding the ones of the database container
+	Probes *KieAppProbes `json:"probes,omitempty"`
 }
 
 // CommonExtDBObjectRequiredURL common configuration definition of an external database
@@ -56,10 +66,11 @@ type CommonExternalDatabaseObject struct {
 	// +kubebuilder:validation:Required
 	// External database username
 	Username string `json:"username"`
-	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Format:=password
-	// External database password
-	Password string `json:"password"`
+	// External database password. Either password or passwordSecretKeyRef must be set.
+	Password string `json:"password,omitempty"`
+	// Secret key holding the external database password. Takes precedence over password.
+	PasswordSecretKeyRef *corev1.SecretKeySelector `json:"passwordSecretKeyRef,omitempty"`
 	// Sets xa-pool/min-pool-size for the configured datasource.
 	MinPoolSize string `json:"minPoolSize,omitempty"`
 	// Sets xa-pool/max-pool-size for the configured datasource.
@@ -0,0 +1,21 @@
+package v2
+
+// DeletionPolicyType describes what happens to a kind of resource when the KieApp is deleted
+type DeletionPolicyType string
+
+const (
+	// DeleteDeletionPolicy removes the resources along with the KieApp
+	DeleteDeletionPolicy DeletionPolicyType = "Delete"
+	// RetainDeletionPolicy releases the resources from the KieApp so they are kept once it is deleted
+	RetainDeletionPolicy DeletionPolicyType = "Retain"
+)
+
+// KieAppDeletionPolicy defines which resources are kept when the KieApp is deleted
+type KieAppDeletionPolicy struct {
+	// +kubebuilder:validation:Enum:=Delete;Retain
+	// What happens to the PersistentVolumeClaims of the application, e.g. the console and kieserver repositories. Defaults to Delete.
+	PersistentVolumeClaims DeletionPolicyType `json:"persistentVolumeClaims,omitempty"`
+	// +kubebuilder:validation:Enum:=Delete;Retain
+	// What happens to the databases deployed for the kieservers and process migration, including their services, claims and the credentials Secret holding their passwords. Defaults to Delete.
+	Databases DeletionPolicyType `json:"databases,omitempty"`
+}
@@ -0,0 +1,16 @@
+package v2
+
+import "k8s.io/apimachinery/pkg/util/intstr"
+
+// KieAppPodDisruptionBudget defines the PodDisruptionBudgets generated for the components running, or autoscaled to,
+// more than one replica
+type KieAppPodDisruptionBudget struct {
+	// Set true to not generate PodDisruptionBudgets
+	Disabled bool `json:"disabled,omitempty"`
+	// Number or percentage of the pods of each component that must stay available during voluntary disruptions,
+	// e.g. node drains. Cannot be set along with maxUnavailable.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Number or percentage of the pods of each component that can be unavailable during voluntary disruptions.
+	// Defaults to 1 when minAvailable is not set.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
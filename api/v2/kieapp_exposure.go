@@ -0,0 +1,58 @@
+package v2
+
+// ExposureType describes the kind of object used to expose the application outside the cluster
+type ExposureType string
+
+const (
+	// RouteExposure exposes the application through OpenShift Routes
+	RouteExposure ExposureType = "Route"
+	// IngressExposure exposes the application through networking.k8s.io/v1 Ingresses
+	IngressExposure ExposureType = "Ingress"
+	// HTTPRouteExposure exposes the application through Gateway API HTTPRoutes
+	HTTPRouteExposure ExposureType = "HTTPRoute"
+)
+
+// KieAppExposure defines how the console, kieservers, smartrouter, process migration and dashbuilder are exposed
+type KieAppExposure struct {
+	// +kubebuilder:validation:Enum:=Route;Ingress;HTTPRoute
+	// The kind of object used to expose the application. Defaults to Route on OpenShift and to Ingress on Kubernetes.
+	Type ExposureType `json:"type,omitempty"`
+	// Domain used to generate the hostname, as <name>-<namespace>.<domain>, of components without a routeHostname.
+	// Required for Ingresses and HTTPRoutes unless every exposed component has a routeHostname. Ignored for Routes,
+	// whose hostnames are generated by the OpenShift router.
+	Domain string `json:"domain,omitempty"`
+	// Configuration of the generated Ingresses
+	Ingress *KieAppIngress `json:"ingress,omitempty"`
+	// Configuration of the generated HTTPRoutes
+	HTTPRoute *KieAppHTTPRoute `json:"httpRoute,omitempty"`
+}
+
+// KieAppIngress configuration applied to every generated Ingress
+type KieAppIngress struct {
+	// The IngressClass that implements the generated Ingresses
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Name of the kubernetes.io/tls Secret used by the ingress controller to terminate TLS
+	TLSSecret string `json:"tlsSecret,omitempty"`
+	// Annotations added to the generated Ingresses, e.g. to configure the backend protocol of the ingress controller
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// KieAppHTTPRoute configuration applied to every generated HTTPRoute
+type KieAppHTTPRoute struct {
+	// Labels added to the generated HTTPRoutes, used by the Gateway listeners to select them
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to the generated HTTPRoutes
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Gateways allowed to use the generated HTTPRoutes. When empty, only Gateways in the same namespace are allowed.
+	Gateways []GatewayRef `json:"gateways,omitempty"`
+	// Name of the kubernetes.io/tls Secret holding the certificate for the HTTPRoute hostnames
+	TLSSecret string `json:"tlsSecret,omitempty"`
+}
+
+// GatewayRef identifies a Gateway
+type GatewayRef struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+}
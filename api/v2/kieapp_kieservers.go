@@ -30,6 +30,8 @@ type KieServerSet struct {
 	Cors                   *CORSFiltersObject            `json:"cors,omitempty"`
 	// MDBMaxSession number of KIE Executor sessions
 	MDBMaxSession *int `json:"MDBMaxSession,omitempty"`
+	// Autoscaling scales the deployments of the set with HorizontalPodAutoscalers
+	Autoscaling *KieServerAutoscaling `json:"autoscaling,omitempty"`
 }
 
 // ServerTemplate contains all the variables used in the yaml templates
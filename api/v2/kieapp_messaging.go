@@ -1,5 +1,7 @@
 package v2
 
+import corev1 "k8s.io/api/core/v1"
+
 // KieAppJmsObject messaging specification to be used by the KieApp
 type KieAppJmsObject struct {
 	// +kubebuilder:validation:Required
@@ -46,4 +48,8 @@ type KieAppJmsObject struct {
 	AMQKeystorePassword string `json:"amqKeystorePassword,omitempty"`
 	// Not intended to be set by the user, if will be set to true if all required SSL parameters are set.
 	AMQEnableSSL bool `json:"amqEnableSSL,omitempty"` // flag will be set to true if all AMQ SSL parameters are correctly set.
+	// PodSecurityContext whose fields override the ones of the AMQ broker pods
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// SecurityContext whose fields override the ones of the AMQ broker containers
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
 }
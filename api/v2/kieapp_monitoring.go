@@ -0,0 +1,19 @@
+package v2
+
+// KieAppMonitoring defines the prometheus-operator objects generated to monitor the application
+type KieAppMonitoring struct {
+	// Set true to generate a ServiceMonitor for the console, smartrouter and each kieserver set, along with a
+	// PrometheusRule alerting on their availability and heap usage. Requires the prometheus-operator CRDs.
+	Enabled bool `json:"enabled,omitempty"`
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(ms|s|m|h))+$`
+	// Interval at which the endpoints are scraped, e.g. 30s. Defaults to the interval of the Prometheus instance.
+	Interval string `json:"interval,omitempty"`
+	// Labels added to the generated ServiceMonitors and PrometheusRule, used by the Prometheus instance to select them
+	Labels map[string]string `json:"labels,omitempty"`
+	// Set true to skip the generation of the PrometheusRule
+	DisableRules bool `json:"disableRules,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// Percentage of the maximum heap above which the high heap usage alert fires. Defaults to 90.
+	HeapUsageThreshold *int32 `json:"heapUsageThreshold,omitempty"`
+}
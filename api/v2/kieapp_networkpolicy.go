@@ -0,0 +1,13 @@
+package v2
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// KieAppNetworkPolicy defines the NetworkPolicies generated to isolate the pods of the application
+type KieAppNetworkPolicy struct {
+	// Set true to generate a NetworkPolicy for each component, only allowing the traffic from the components that use
+	// its services, e.g. from the kieservers to their database, and from the router to the exposed components
+	Enabled bool `json:"enabled,omitempty"`
+	// Selects the namespaces of the router or ingress controller allowed to reach the exposed components.
+	// Defaults to the namespaces of the OpenShift ingress policy group, required on Kubernetes.
+	RouterNamespaceSelector *metav1.LabelSelector `json:"routerNamespaceSelector,omitempty"`
+}
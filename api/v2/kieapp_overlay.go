@@ -0,0 +1,32 @@
+package v2
+
+// KieAppOverlay patches the resources generated for the application, e.g. to set a lifecycle hook or annotation the
+// KieApp does not define
+type KieAppOverlay struct {
+	// +kubebuilder:validation:Required
+	// Selects the resources patched by the overlay
+	Target KieAppOverlayTarget `json:"target"`
+	// Strategic merge patch applied to the selected resources, in YAML or JSON. Cannot be set along with jsonPatch.
+	StrategicMergePatch string `json:"strategicMergePatch,omitempty"`
+	// RFC 6902 JSON patch applied to the selected resources, in YAML or JSON. Cannot be set along with strategicMergePatch.
+	JSONPatch string `json:"jsonPatch,omitempty"`
+}
+
+// KieAppOverlayTarget selects resources generated for the application, all of them when no field is set
+type KieAppOverlayTarget struct {
+	// Kind of the resources, e.g. DeploymentConfig, Deployment, Service or Route
+	Kind string `json:"kind,omitempty"`
+	// Name of the resources, where * matches any sequence of characters, e.g. *-kieserver*
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Enum:=console;smartRouter;servers;processMigration;dashbuilder;databases;others
+	// Component the resources are generated for
+	Component string `json:"component,omitempty"`
+}
+
+// AppliedOverlay - An overlay of the spec applied to a resource of the application
+type AppliedOverlay struct {
+	// Index of the overlay in spec.overlays
+	Index int    `json:"index"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+}
@@ -0,0 +1,33 @@
+package v2
+
+// ApprovedPlanAnnotation set on a KieApp to the hash of its pending plan allows the operator to apply that plan
+const ApprovedPlanAnnotation = "app.kiegroup.org/approved-plan"
+
+// PlannedActionType describes what the operator is about to do with a resource
+type PlannedActionType string
+
+const (
+	// CreatePlannedAction - the resource is missing and will be created
+	CreatePlannedAction PlannedActionType = "Create"
+	// UpdatePlannedAction - the resource differs from the requested one and will be updated
+	UpdatePlannedAction PlannedActionType = "Update"
+	// DeletePlannedAction - the resource is no longer requested and will be deleted
+	DeletePlannedAction PlannedActionType = "Delete"
+)
+
+// KieAppPlan - The changes to the resources of the application awaiting approval
+type KieAppPlan struct {
+	// Hash identifying the plan, to be set in the app.kiegroup.org/approved-plan annotation to apply it
+	Hash string `json:"hash"`
+	// The changes the plan applies
+	Changes []PlannedChange `json:"changes,omitempty"`
+}
+
+// PlannedChange - A change to a single resource of the application
+type PlannedChange struct {
+	Action PlannedActionType `json:"action"`
+	Kind   string            `json:"kind"`
+	Name   string            `json:"name"`
+	// The fields of the resource being updated
+	Fields []string `json:"fields,omitempty"`
+}
@@ -0,0 +1,34 @@
+package v2
+
+import corev1 "k8s.io/api/core/v1"
+
+// KieAppProbes overrides the probes of the main container of the pods
+type KieAppProbes struct {
+	// Overrides the liveness probe, restarting the container when it fails
+	Liveness *KieAppProbe `json:"liveness,omitempty"`
+	// Overrides the readiness probe, removing the pod from the endpoints of its services when it fails
+	Readiness *KieAppProbe `json:"readiness,omitempty"`
+	// Overrides the startup probe, delaying the other probes until it succeeds, e.g. while large KJARs are deployed
+	Startup *KieAppProbe `json:"startup,omitempty"`
+}
+
+// KieAppProbe overrides the timings of a probe, or replaces it
+type KieAppProbe struct {
+	// +kubebuilder:validation:Minimum:=0
+	// Seconds after the container has started before the probe is initiated
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// How often, in seconds, to perform the probe
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// Seconds after which the probe times out
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// +kubebuilder:validation:Minimum:=1
+	// Consecutive failures for the probe to be considered failed
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Probe replacing the one of the template, or added when the template has none. The timings above are applied on
+	// top of it.
+	Custom *corev1.Probe `json:"custom,omitempty"`
+}
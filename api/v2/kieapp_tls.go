@@ -0,0 +1,76 @@
+package v2
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// KieAppTLS defines how the certificates of the console, kieservers, smartrouter and dashbuilder are managed
+type KieAppTLS struct {
+	// How long before their expiry the CA and the keystores generated by the operator are regenerated, e.g. 360h.
+	// Defaults to 30 days. The pods mounting a regenerated keystore are rolled out.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	// Request the certificates of the console, kieservers, smartrouter and dashbuilder from cert-manager instead of
+	// signing them with the CA of the KieApp. Their routes then re-encrypt the traffic with the issued certificates.
+	// The KieApp fails with the MissingDependencies reason when the cert-manager CRDs are not installed.
+	CertManager *KieAppCertManager `json:"certManager,omitempty"`
+	// +kubebuilder:validation:Enum:=jks;pkcs12
+	// Format of the keystores and truststore mounted by the components. Defaults to jks. The existing keystores and
+	// truststore are regenerated in the new format when it changes.
+	KeystoreFormat KeystoreFormat `json:"keystoreFormat,omitempty"`
+	// +kubebuilder:validation:Enum:=RSA;ECDSA
+	// Algorithm of the private keys of the keystores and of the CA of the KieApp. Defaults to RSA. The CA and the
+	// keystores it signs are regenerated when the algorithm or size changes.
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
+	// Size in bits of the private keys, at least 2048 for RSA, 256 (P-256) or 384 (P-384) for ECDSA. Defaults to 2048
+	// for RSA and 256 for ECDSA.
+	KeySize int `json:"keySize,omitempty"`
+}
+
+// KeystoreFormat is the format of a Java keystore
+type KeystoreFormat string
+
+const (
+	// KeystoreFormatJKS is the proprietary Java KeyStore format
+	KeystoreFormatJKS KeystoreFormat = "jks"
+	// KeystoreFormatPKCS12 is the PKCS #12 format
+	KeystoreFormatPKCS12 KeystoreFormat = "pkcs12"
+)
+
+// KeyAlgorithm is the algorithm of a private key
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmRSA is the RSA algorithm
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSA is the elliptic curve algorithm
+	KeyAlgorithmECDSA KeyAlgorithm = "ECDSA"
+)
+
+// KieAppCertManager defines the cert-manager Certificates requested for the components
+type KieAppCertManager struct {
+	// +kubebuilder:validation:Required
+	// The Issuer or ClusterIssuer issuing the certificates
+	IssuerRef CertManagerIssuerRef `json:"issuerRef"`
+	// Validity of the certificates, e.g. 2160h. Defaults to 90 days.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// How long before their expiry cert-manager renews the certificates. Defaults to a third of their validity.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerRef refers to a cert-manager issuer
+type CertManagerIssuerRef struct {
+	// +kubebuilder:validation:Required
+	// Name of the issuer
+	Name string `json:"name"`
+	// Kind of the issuer, Issuer or ClusterIssuer. Defaults to Issuer, which must be in the namespace of the KieApp.
+	Kind string `json:"kind,omitempty"`
+	// API group of the issuer. Defaults to cert-manager.io, set it for external issuers.
+	Group string `json:"group,omitempty"`
+}
+
+// CertificateStatus - The certificate held by a CA or keystore secret generated by the operator
+type CertificateStatus struct {
+	Secret string `json:"secret"`
+	// Expiry of the certificate, or of the earliest expiring certificate of the keystore chain
+	NotAfter metav1.Time `json:"notAfter"`
+	// Time from which the certificate is regenerated
+	RenewalTime metav1.Time `json:"renewalTime"`
+}
@@ -5,12 +5,18 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	oimagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 	//	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -48,8 +54,50 @@ type KieAppSpec struct {
 	Upgrades KieAppUpgrades `json:"upgrades,omitempty"`
 	// Set true to enable image tags, disabled by default. This will leverage image tags instead of the image digests.
 	UseImageTags bool `json:"useImageTags,omitempty"`
-	// Defines which truststore is used by the console, kieservers, smartrouter, and dashbuilder
+	// Defines which truststore is used by the console, kieservers, smartrouter, and dashbuilder. The CA signing their
+	// keystores is added to it when SSL is enabled.
 	Truststore *KieAppTruststore `json:"truststore,omitempty"`
+	// Defines how the certificates of the console, kieservers, smartrouter and dashbuilder are managed
+	TLS *KieAppTLS `json:"tls,omitempty"`
+	// Defines how the console, kieservers, smartrouter, process migration and dashbuilder are exposed outside the cluster
+	Exposure *KieAppExposure `json:"exposure,omitempty"`
+	// Defines which resources are kept when the KieApp is deleted
+	DeletionPolicy *KieAppDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Defines the ServiceMonitors and PrometheusRule generated for the application
+	Monitoring *KieAppMonitoring `json:"monitoring,omitempty"`
+	// Defines the PodDisruptionBudgets generated for the console, kieservers, smartrouter, dashbuilder, datagrid and AMQ
+	// when they run, or are autoscaled to, more than one replica
+	PodDisruptionBudget *KieAppPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+	// Defines the NetworkPolicies generated to isolate the pods of the application
+	NetworkPolicy *KieAppNetworkPolicy `json:"networkPolicy,omitempty"`
+	// Set true to stop the operator from creating, updating or deleting the resources of the application, e.g. while
+	// they are modified by hand. The status keeps being updated.
+	Paused bool `json:"paused,omitempty"`
+	// Set true to scale the console, kieservers, smartrouter, process migration, dashbuilder and databases to zero,
+	// keeping their claims, secrets and routes. The replicas they were running with, recorded in the status, are
+	// restored when unset.
+	Suspended bool `json:"suspended,omitempty"`
+	// Set true to only plan the changes to the resources of the application. The plan is reported in the status and
+	// applied once the app.kiegroup.org/approved-plan annotation is set to its hash. Until then, the operator writes
+	// nothing but the status, generating no route, secret or image stream tag.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// +kubebuilder:validation:Enum:=openshift;kubernetes
+	// The platform the application is deployed on. When not set, it is detected from the APIs served by the cluster.
+	// On kubernetes, Deployments are created instead of DeploymentConfigs and OpenShift-only objects are skipped.
+	Platform PlatformType `json:"platform,omitempty"`
+	// +kubebuilder:validation:Enum:=restricted
+	// Security context applied to the pods and containers of all the components before the overrides of each component.
+	// The restricted preset runs them as non-root, with all capabilities dropped and the RuntimeDefault seccomp profile,
+	// and the resources are checked against the restricted Pod Security standard before they are applied.
+	SecurityContextPreset SecurityContextPreset `json:"securityContextPreset,omitempty"`
+	// Patches applied, in order, to the resources generated for the application before they are created or updated
+	Overlays []KieAppOverlay `json:"overlays,omitempty"`
+	// Labels added to all the resources generated for the application and to their pods, e.g. to track costs. Labels
+	// set by the operator are not overridden.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+	// Annotations added to all the resources generated for the application and to their pods. Annotations set by the
+	// operator are not overridden, and the ones added by other controllers to the deployed resources are kept.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
 	// The version of the application deployment.
 	Version      string            `json:"version,omitempty"`
 	CommonConfig CommonConfig      `json:"commonConfig,omitempty"`
@@ -102,6 +150,46 @@ type KieAppObject struct {
 	StorageClassName string `json:"storageClassName,omitempty"`
 	// RouteHostname will define the route.spec.host value
 	RouteHostname string `json:"routeHostname,omitempty"`
+	// NodeSelector added to the pods, which are only scheduled on the nodes with these labels
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations added to the pods, allowing them to be scheduled on tainted nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity replacing the one of the pods, e.g. to keep the replicas on different nodes
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// TopologySpreadConstraints added to the pods, e.g. to spread the replicas across zones
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// PodLabels added to the pods and to the other resources generated for the component, replacing the common labels
+	// with the same keys. Labels used by the selector of the DeploymentConfig or StatefulSet are not overridden.
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations added to the pods
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// NetworkPolicyPeers also allowed to reach the pods when spec.networkPolicy is enabled
+	NetworkPolicyPeers []networkingv1.NetworkPolicyPeer `json:"networkPolicyPeers,omitempty"`
+	// PodSecurityContext whose fields override the ones of the pods
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// SecurityContext whose fields override the ones of the containers and init containers
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Sidecars added to the pods, e.g. to ship the logs. A sidecar replaces the container of the pods with the same name.
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// InitContainers run before the containers of the pods, e.g. to fetch the jars added to their classpath
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Volumes added to the pods. A volume replaces the volume of the pods with the same name.
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts added to the containers of the pods, sidecars excluded
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Probes overriding the ones of the main container of the pods
+	Probes *KieAppProbes `json:"probes,omitempty"`
+	// Annotations added to the resources generated for the component and to its pods, replacing the common annotations
+	// with the same keys
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type Environment struct {
@@ -115,19 +203,28 @@ type Environment struct {
 }
 
 type CustomObject struct {
-	Omit                   bool                           `json:"omit,omitempty"`
-	PersistentVolumeClaims []corev1.PersistentVolumeClaim `json:"persistentVolumeClaims,omitempty"`
-	ServiceAccounts        []corev1.ServiceAccount        `json:"serviceAccounts,omitempty"`
-	Secrets                []corev1.Secret                `json:"secrets,omitempty"`
-	Roles                  []rbacv1.Role                  `json:"roles,omitempty"`
-	RoleBindings           []rbacv1.RoleBinding           `json:"roleBindings,omitempty"`
-	DeploymentConfigs      []oappsv1.DeploymentConfig     `json:"deploymentConfigs,omitempty"`
-	StatefulSets           []appsv1.StatefulSet           `json:"statefulSets,omitempty"`
-	BuildConfigs           []buildv1.BuildConfig          `json:"buildConfigs,omitempty"`
-	ImageStreams           []oimagev1.ImageStream         `json:"imageStreams,omitempty"`
-	Services               []corev1.Service               `json:"services,omitempty"`
-	Routes                 []routev1.Route                `json:"routes,omitempty"`
-	ConfigMaps             []corev1.ConfigMap             `json:"configMaps,omitempty"`
+	Omit                     bool                                         `json:"omit,omitempty"`
+	PersistentVolumeClaims   []corev1.PersistentVolumeClaim               `json:"persistentVolumeClaims,omitempty"`
+	ServiceAccounts          []corev1.ServiceAccount                      `json:"serviceAccounts,omitempty"`
+	Secrets                  []corev1.Secret                              `json:"secrets,omitempty"`
+	Roles                    []rbacv1.Role                                `json:"roles,omitempty"`
+	RoleBindings             []rbacv1.RoleBinding                         `json:"roleBindings,omitempty"`
+	DeploymentConfigs        []oappsv1.DeploymentConfig                   `json:"deploymentConfigs,omitempty"`
+	Deployments              []appsv1.Deployment                          `json:"deployments,omitempty"`
+	StatefulSets             []appsv1.StatefulSet                         `json:"statefulSets,omitempty"`
+	BuildConfigs             []buildv1.BuildConfig                        `json:"buildConfigs,omitempty"`
+	ImageStreams             []oimagev1.ImageStream                       `json:"imageStreams,omitempty"`
+	Services                 []corev1.Service                             `json:"services,omitempty"`
+	Routes                   []routev1.Route                              `json:"routes,omitempty"`
+	Ingresses                []networkingv1.Ingress                       `json:"ingresses,omitempty"`
+	HTTPRoutes               []gatewayv1alpha1.HTTPRoute                  `json:"httpRoutes,omitempty"`
+	ConfigMaps               []corev1.ConfigMap                           `json:"configMaps,omitempty"`
+	ServiceMonitors          []monv1.ServiceMonitor                       `json:"serviceMonitors,omitempty"`
+	PrometheusRules          []monv1.PrometheusRule                       `json:"prometheusRules,omitempty"`
+	PodDisruptionBudgets     []policyv1.PodDisruptionBudget               `json:"podDisruptionBudgets,omitempty"`
+	HorizontalPodAutoscalers []autoscalingv2beta2.HorizontalPodAutoscaler `json:"horizontalPodAutoscalers,omitempty"`
+	NetworkPolicies          []networkingv1.NetworkPolicy                 `json:"networkPolicies,omitempty"`
+	Certificates             []certmanagerv1.Certificate                  `json:"certificates,omitempty"`
 }
 
 type EnvTemplate struct {
@@ -142,6 +239,12 @@ type EnvTemplate struct {
 	Constants         TemplateConstants        `json:"constants,omitempty"`
 	OpenshiftCaBundle bool                     `json:"openshiftCaBundle,omitempty"`
 	RouteProtocol     string                   `json:"routeProtocol,omitempty"`
+	// Whether the components mount the truststore generated from the CA signing their keystores, the Openshift CA bundle
+	// and the truststore sources
+	Truststore bool `json:"truststore,omitempty"`
+	// File name and Java KeyStore type of the keystores mounted by the components
+	KeystoreName string `json:"keystoreName,omitempty"`
+	KeystoreType string `json:"keystoreType,omitempty"`
 }
 
 // ImageObjRef contains enough information to let you inspect or modify the referred object.
@@ -16,6 +16,14 @@ const (
 	ProvisioningConditionType ConditionType = "Provisioning"
 	// FailedConditionType - the kieapp is in a failed state
 	FailedConditionType ConditionType = "Failed"
+	// DeletingConditionType - the resources of the kieapp are being cleaned up before its deletion
+	DeletingConditionType ConditionType = "Deleting"
+	// PausedConditionType - the operator does not modify the resources of the kieapp
+	PausedConditionType ConditionType = "Paused"
+	// SuspendedConditionType - the workloads of the kieapp are scaled to zero
+	SuspendedConditionType ConditionType = "Suspended"
+	// PendingApprovalConditionType - the changes to the resources of the kieapp wait for the approval of their plan
+	PendingApprovalConditionType ConditionType = "PendingApproval"
 )
 
 // ReasonType - type of reason
@@ -28,6 +36,8 @@ const (
 	ConfigurationErrorReason ReasonType = "ConfigurationError"
 	// MissingDependenciesReason - Dependencies does not exist or cannot be found
 	MissingDependenciesReason ReasonType = "MissingDependencies"
+	// DeletionFailedReason - Unable to clean up the resources of the application
+	DeletionFailedReason ReasonType = "DeletionFailed"
 	// UnknownReason - Unable to determine the error
 	UnknownReason ReasonType = "Unknown"
 )
@@ -51,4 +61,14 @@ type KieAppStatus struct {
 	Phase       ConditionType        `json:"phase,omitempty"`
 	Applied     KieAppSpec           `json:"applied,omitempty"`
 	Version     string               `json:"version,omitempty"`
+	// ImageStreamTags created by the operator in the namespace of the KieApp, removed when it is deleted
+	ImageStreamTags []string `json:"imageStreamTags,omitempty"`
+	// Replicas of the DeploymentConfigs, Deployments and StatefulSets before the KieApp was suspended, by name
+	SuspendedReplicas map[string]int32 `json:"suspendedReplicas,omitempty"`
+	// Changes to the resources awaiting approval, when the KieApp requires it
+	Plan *KieAppPlan `json:"plan,omitempty"`
+	// Resources patched by the overlays of the spec
+	Overlays []AppliedOverlay `json:"overlays,omitempty"`
+	// Certificates of the CA and keystores generated by the operator, with the time they are regenerated at
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 }
@@ -23,10 +23,18 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
 	apiappsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -44,11 +52,26 @@ func (in *AppConstants) DeepCopy() *AppConstants {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedOverlay) DeepCopyInto(out *AppliedOverlay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedOverlay.
+func (in *AppliedOverlay) DeepCopy() *AppliedOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthTemplate) DeepCopyInto(out *AuthTemplate) {
 	*out = *in
 	out.SSO = in.SSO
-	out.LDAP = in.LDAP
+	in.LDAP.DeepCopyInto(&out.LDAP)
 	in.RoleMapper.DeepCopyInto(&out.RoleMapper)
 }
 
@@ -104,9 +127,66 @@ func (in *CORSFiltersObject) DeepCopy() *CORSFiltersObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	in.RenewalTime.DeepCopyInto(&out.RenewalTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonConfig) DeepCopyInto(out *CommonConfig) {
 	*out = *in
+	if in.KeyStorePasswordSecretKeyRef != nil {
+		in, out := &in.KeyStorePasswordSecretKeyRef, &out.KeyStorePasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminPasswordSecretKeyRef != nil {
+		in, out := &in.AdminPasswordSecretKeyRef, &out.AdminPasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DBPasswordSecretKeyRef != nil {
+		in, out := &in.DBPasswordSecretKeyRef, &out.DBPasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMQPasswordSecretKeyRef != nil {
+		in, out := &in.AMQPasswordSecretKeyRef, &out.AMQPasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMQClusterPasswordSecretKeyRef != nil {
+		in, out := &in.AMQClusterPasswordSecretKeyRef, &out.AMQClusterPasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StartupStrategy != nil {
 		in, out := &in.StartupStrategy, &out.StartupStrategy
 		*out = new(StartupStrategy)
@@ -127,7 +207,7 @@ func (in *CommonConfig) DeepCopy() *CommonConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonExtDBObjectRequiredURL) DeepCopyInto(out *CommonExtDBObjectRequiredURL) {
 	*out = *in
-	out.CommonExternalDatabaseObject = in.CommonExternalDatabaseObject
+	in.CommonExternalDatabaseObject.DeepCopyInto(&out.CommonExternalDatabaseObject)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonExtDBObjectRequiredURL.
@@ -143,7 +223,7 @@ func (in *CommonExtDBObjectRequiredURL) DeepCopy() *CommonExtDBObjectRequiredURL
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonExtDBObjectURL) DeepCopyInto(out *CommonExtDBObjectURL) {
 	*out = *in
-	out.CommonExternalDatabaseObject = in.CommonExternalDatabaseObject
+	in.CommonExternalDatabaseObject.DeepCopyInto(&out.CommonExternalDatabaseObject)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonExtDBObjectURL.
@@ -159,6 +239,11 @@ func (in *CommonExtDBObjectURL) DeepCopy() *CommonExtDBObjectURL {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonExternalDatabaseObject) DeepCopyInto(out *CommonExternalDatabaseObject) {
 	*out = *in
+	if in.PasswordSecretKeyRef != nil {
+		in, out := &in.PasswordSecretKeyRef, &out.PasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonExternalDatabaseObject.
@@ -194,7 +279,7 @@ func (in *ConsoleObject) DeepCopyInto(out *ConsoleObject) {
 	if in.SSOClient != nil {
 		in, out := &in.SSOClient, &out.SSOClient
 		*out = new(SSOAuthClient)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.GitHooks != nil {
 		in, out := &in.GitHooks, &out.GitHooks
@@ -231,7 +316,7 @@ func (in *ConsoleObject) DeepCopy() *ConsoleObject {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConsoleTemplate) DeepCopyInto(out *ConsoleTemplate) {
 	*out = *in
-	out.SSOAuthClient = in.SSOAuthClient
+	in.SSOAuthClient.DeepCopyInto(&out.SSOAuthClient)
 	in.GitHooks.DeepCopyInto(&out.GitHooks)
 	in.Jvm.DeepCopyInto(&out.Jvm)
 	in.Cors.DeepCopyInto(&out.Cors)
@@ -294,6 +379,13 @@ func (in *CustomObject) DeepCopyInto(out *CustomObject) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]apiappsv1.Deployment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.StatefulSets != nil {
 		in, out := &in.StatefulSets, &out.StatefulSets
 		*out = make([]apiappsv1.StatefulSet, len(*in))
@@ -329,6 +421,20 @@ func (in *CustomObject) DeepCopyInto(out *CustomObject) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Ingresses != nil {
+		in, out := &in.Ingresses, &out.Ingresses
+		*out = make([]networkingv1.Ingress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPRoutes != nil {
+		in, out := &in.HTTPRoutes, &out.HTTPRoutes
+		*out = make([]v1alpha1.HTTPRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ConfigMaps != nil {
 		in, out := &in.ConfigMaps, &out.ConfigMaps
 		*out = make([]v1.ConfigMap, len(*in))
@@ -336,6 +442,48 @@ func (in *CustomObject) DeepCopyInto(out *CustomObject) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceMonitors != nil {
+		in, out := &in.ServiceMonitors, &out.ServiceMonitors
+		*out = make([]monitoringv1.ServiceMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrometheusRules != nil {
+		in, out := &in.PrometheusRules, &out.PrometheusRules
+		*out = make([]monitoringv1.PrometheusRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudgets != nil {
+		in, out := &in.PodDisruptionBudgets, &out.PodDisruptionBudgets
+		*out = make([]policyv1.PodDisruptionBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HorizontalPodAutoscalers != nil {
+		in, out := &in.HorizontalPodAutoscalers, &out.HorizontalPodAutoscalers
+		*out = make([]v2beta2.HorizontalPodAutoscaler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]networkingv1.NetworkPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]certmanagerv1.Certificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomObject.
@@ -399,12 +547,16 @@ func (in *DashbuilderConfig) DeepCopyInto(out *DashbuilderConfig) {
 	if in.KieServerDataSets != nil {
 		in, out := &in.KieServerDataSets, &out.KieServerDataSets
 		*out = make([]KieServerDataSetOrTemplate, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.KieServerTemplates != nil {
 		in, out := &in.KieServerTemplates, &out.KieServerTemplates
 		*out = make([]KieServerDataSetOrTemplate, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -425,7 +577,7 @@ func (in *DashbuilderObject) DeepCopyInto(out *DashbuilderObject) {
 	if in.SSOClient != nil {
 		in, out := &in.SSOClient, &out.SSOClient
 		*out = new(SSOAuthClient)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Jvm != nil {
 		in, out := &in.Jvm, &out.Jvm
@@ -457,7 +609,7 @@ func (in *DashbuilderObject) DeepCopy() *DashbuilderObject {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DashbuilderTemplate) DeepCopyInto(out *DashbuilderTemplate) {
 	*out = *in
-	out.SSOAuthClient = in.SSOAuthClient
+	in.SSOAuthClient.DeepCopyInto(&out.SSOAuthClient)
 	in.Database.DeepCopyInto(&out.Database)
 	in.Jvm.DeepCopyInto(&out.Jvm)
 	in.Config.DeepCopyInto(&out.Config)
@@ -492,11 +644,11 @@ func (in *DataGridAuth) DeepCopy() *DataGridAuth {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseObject) DeepCopyInto(out *DatabaseObject) {
 	*out = *in
-	out.InternalDatabaseObject = in.InternalDatabaseObject
+	in.InternalDatabaseObject.DeepCopyInto(&out.InternalDatabaseObject)
 	if in.ExternalConfig != nil {
 		in, out := &in.ExternalConfig, &out.ExternalConfig
 		*out = new(ExternalDatabaseObject)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -513,7 +665,7 @@ func (in *DatabaseObject) DeepCopy() *DatabaseObject {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseTemplate) DeepCopyInto(out *DatabaseTemplate) {
 	*out = *in
-	out.InternalDatabaseObject = in.InternalDatabaseObject
+	in.InternalDatabaseObject.DeepCopyInto(&out.InternalDatabaseObject)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseTemplate.
@@ -549,7 +701,9 @@ func (in *EnvTemplate) DeepCopyInto(out *EnvTemplate) {
 	if in.Databases != nil {
 		in, out := &in.Databases, &out.Databases
 		*out = make([]DatabaseTemplate, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	out.Constants = in.Constants
 }
@@ -634,7 +788,7 @@ func (in *EnvironmentConstants) DeepCopy() *EnvironmentConstants {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDatabaseObject) DeepCopyInto(out *ExternalDatabaseObject) {
 	*out = *in
-	out.CommonExtDBObjectURL = in.CommonExtDBObjectURL
+	in.CommonExtDBObjectURL.DeepCopyInto(&out.CommonExtDBObjectURL)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDatabaseObject.
@@ -647,6 +801,21 @@ func (in *ExternalDatabaseObject) DeepCopy() *ExternalDatabaseObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRef) DeepCopyInto(out *GatewayRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRef.
+func (in *GatewayRef) DeepCopy() *GatewayRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitHooksVolume) DeepCopyInto(out *GitHooksVolume) {
 	*out = *in
@@ -701,6 +870,21 @@ func (in *ImageObjRef) DeepCopy() *ImageObjRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternalDatabaseObject) DeepCopyInto(out *InternalDatabaseObject) {
 	*out = *in
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(KieAppProbes)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalDatabaseObject.
@@ -881,7 +1065,7 @@ func (in *KieAppAuthObject) DeepCopyInto(out *KieAppAuthObject) {
 	if in.LDAP != nil {
 		in, out := &in.LDAP, &out.LDAP
 		*out = new(LDAPAuthConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.RoleMapper != nil {
 		in, out := &in.RoleMapper, &out.RoleMapper
@@ -933,6 +1117,133 @@ func (in *KieAppBuildObject) DeepCopy() *KieAppBuildObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppCertManager) DeepCopyInto(out *KieAppCertManager) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppCertManager.
+func (in *KieAppCertManager) DeepCopy() *KieAppCertManager {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppCertManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppDeletionPolicy) DeepCopyInto(out *KieAppDeletionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppDeletionPolicy.
+func (in *KieAppDeletionPolicy) DeepCopy() *KieAppDeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppDeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppExposure) DeepCopyInto(out *KieAppExposure) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(KieAppIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPRoute != nil {
+		in, out := &in.HTTPRoute, &out.HTTPRoute
+		*out = new(KieAppHTTPRoute)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppExposure.
+func (in *KieAppExposure) DeepCopy() *KieAppExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppHTTPRoute) DeepCopyInto(out *KieAppHTTPRoute) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]GatewayRef, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppHTTPRoute.
+func (in *KieAppHTTPRoute) DeepCopy() *KieAppHTTPRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppHTTPRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppIngress) DeepCopyInto(out *KieAppIngress) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppIngress.
+func (in *KieAppIngress) DeepCopy() *KieAppIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KieAppJmsObject) DeepCopyInto(out *KieAppJmsObject) {
 	*out = *in
@@ -946,6 +1257,16 @@ func (in *KieAppJmsObject) DeepCopyInto(out *KieAppJmsObject) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppJmsObject.
@@ -990,6 +1311,53 @@ func (in *KieAppList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppMonitoring) DeepCopyInto(out *KieAppMonitoring) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HeapUsageThreshold != nil {
+		in, out := &in.HeapUsageThreshold, &out.HeapUsageThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppMonitoring.
+func (in *KieAppMonitoring) DeepCopy() *KieAppMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppNetworkPolicy) DeepCopyInto(out *KieAppNetworkPolicy) {
+	*out = *in
+	if in.RouterNamespaceSelector != nil {
+		in, out := &in.RouterNamespaceSelector, &out.RouterNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppNetworkPolicy.
+func (in *KieAppNetworkPolicy) DeepCopy() *KieAppNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KieAppObject) DeepCopyInto(out *KieAppObject) {
 	*out = *in
@@ -1010,6 +1378,103 @@ func (in *KieAppObject) DeepCopyInto(out *KieAppObject) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NetworkPolicyPeers != nil {
+		in, out := &in.NetworkPolicyPeers, &out.NetworkPolicyPeers
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(KieAppProbes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppObject.
@@ -1064,6 +1529,154 @@ func (in *KieAppObjects) DeepCopy() *KieAppObjects {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppOverlay) DeepCopyInto(out *KieAppOverlay) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppOverlay.
+func (in *KieAppOverlay) DeepCopy() *KieAppOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppOverlayTarget) DeepCopyInto(out *KieAppOverlayTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppOverlayTarget.
+func (in *KieAppOverlayTarget) DeepCopy() *KieAppOverlayTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppOverlayTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppPlan) DeepCopyInto(out *KieAppPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]PlannedChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppPlan.
+func (in *KieAppPlan) DeepCopy() *KieAppPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppPodDisruptionBudget) DeepCopyInto(out *KieAppPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppPodDisruptionBudget.
+func (in *KieAppPodDisruptionBudget) DeepCopy() *KieAppPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppProbe) DeepCopyInto(out *KieAppProbe) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppProbe.
+func (in *KieAppProbe) DeepCopy() *KieAppProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppProbes) DeepCopyInto(out *KieAppProbes) {
+	*out = *in
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(KieAppProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(KieAppProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(KieAppProbe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppProbes.
+func (in *KieAppProbes) DeepCopy() *KieAppProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KieAppRegistry) DeepCopyInto(out *KieAppRegistry) {
 	*out = *in
@@ -1092,8 +1705,57 @@ func (in *KieAppSpec) DeepCopyInto(out *KieAppSpec) {
 	if in.Truststore != nil {
 		in, out := &in.Truststore, &out.Truststore
 		*out = new(KieAppTruststore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(KieAppTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(KieAppExposure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(KieAppDeletionPolicy)
 		**out = **in
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(KieAppMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(KieAppPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(KieAppNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = make([]KieAppOverlay, len(*in))
+		copy(*out, *in)
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
 	if in.Auth != nil {
 		in, out := &in.Auth, &out.Auth
@@ -1124,6 +1786,35 @@ func (in *KieAppStatus) DeepCopyInto(out *KieAppStatus) {
 	}
 	in.Deployments.DeepCopyInto(&out.Deployments)
 	in.Applied.DeepCopyInto(&out.Applied)
+	if in.ImageStreamTags != nil {
+		in, out := &in.ImageStreamTags, &out.ImageStreamTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SuspendedReplicas != nil {
+		in, out := &in.SuspendedReplicas, &out.SuspendedReplicas
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(KieAppPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = make([]AppliedOverlay, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppStatus.
@@ -1136,9 +1827,41 @@ func (in *KieAppStatus) DeepCopy() *KieAppStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieAppTLS) DeepCopyInto(out *KieAppTLS) {
+	*out = *in
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(KieAppCertManager)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppTLS.
+func (in *KieAppTLS) DeepCopy() *KieAppTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(KieAppTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KieAppTruststore) DeepCopyInto(out *KieAppTruststore) {
 	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]TruststoreSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieAppTruststore.
@@ -1166,6 +1889,43 @@ func (in *KieAppUpgrades) DeepCopy() *KieAppUpgrades {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KieServerAutoscaling) DeepCopyInto(out *KieServerAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2beta2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieServerAutoscaling.
+func (in *KieServerAutoscaling) DeepCopy() *KieServerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(KieServerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KieServerClient) DeepCopyInto(out *KieServerClient) {
 	*out = *in
@@ -1184,6 +1944,11 @@ func (in *KieServerClient) DeepCopy() *KieServerClient {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KieServerDataSetOrTemplate) DeepCopyInto(out *KieServerDataSetOrTemplate) {
 	*out = *in
+	if in.PasswordSecretKeyRef != nil {
+		in, out := &in.PasswordSecretKeyRef, &out.PasswordSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieServerDataSetOrTemplate.
@@ -1217,7 +1982,7 @@ func (in *KieServerSet) DeepCopyInto(out *KieServerSet) {
 	if in.SSOClient != nil {
 		in, out := &in.SSOClient, &out.SSOClient
 		*out = new(SSOAuthClient)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.KieAppObject.DeepCopyInto(&out.KieAppObject)
 	if in.Database != nil {
@@ -1255,6 +2020,11 @@ func (in *KieServerSet) DeepCopyInto(out *KieServerSet) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(KieServerAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KieServerSet.
@@ -1270,6 +2040,11 @@ func (in *KieServerSet) DeepCopy() *KieServerSet {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LDAPAuthConfig) DeepCopyInto(out *LDAPAuthConfig) {
 	*out = *in
+	if in.BindCredentialSecretKeyRef != nil {
+		in, out := &in.BindCredentialSecretKeyRef, &out.BindCredentialSecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPAuthConfig.
@@ -1327,14 +2102,34 @@ func (in *OpenShiftObject) DeepCopy() *OpenShiftObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProcessMigrationDatabaseObject) DeepCopyInto(out *ProcessMigrationDatabaseObject) {
 	*out = *in
-	out.InternalDatabaseObject = in.InternalDatabaseObject
+	in.InternalDatabaseObject.DeepCopyInto(&out.InternalDatabaseObject)
 	if in.ExternalConfig != nil {
 		in, out := &in.ExternalConfig, &out.ExternalConfig
 		*out = new(CommonExtDBObjectRequiredURL)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -1466,6 +2261,11 @@ func (in *RoleMapperTemplate) DeepCopy() *RoleMapperTemplate {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSOAuthClient) DeepCopyInto(out *SSOAuthClient) {
 	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSOAuthClient.
@@ -1496,7 +2296,7 @@ func (in *SSOAuthConfig) DeepCopy() *SSOAuthConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerTemplate) DeepCopyInto(out *ServerTemplate) {
 	*out = *in
-	out.SSOAuthClient = in.SSOAuthClient
+	in.SSOAuthClient.DeepCopyInto(&out.SSOAuthClient)
 	out.From = in.From
 	out.Build = in.Build
 	in.Database.DeepCopyInto(&out.Database)
@@ -1612,6 +2412,31 @@ func (in *TemplateConstants) DeepCopy() *TemplateConstants {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TruststoreSource) DeepCopyInto(out *TruststoreSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TruststoreSource.
+func (in *TruststoreSource) DeepCopy() *TruststoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TruststoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionConfigs) DeepCopyInto(out *VersionConfigs) {
 	*out = *in
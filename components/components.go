@@ -16,7 +16,10 @@ import (
 	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"golang.org/x/mod/semver"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -271,17 +274,42 @@ func GetRole(operatorName string) *rbacv1.Role {
 				},
 				Verbs: Verbs,
 			},
+			{
+				APIGroups: []string{
+					networkingv1.SchemeGroupVersion.Group,
+				},
+				Resources: []string{
+					"networkpolicies",
+				},
+				Verbs: Verbs,
+			},
+			{
+				APIGroups: []string{
+					autoscalingv2beta2.SchemeGroupVersion.Group,
+				},
+				Resources: []string{
+					"horizontalpodautoscalers",
+				},
+				Verbs: Verbs,
+			},
+			{
+				APIGroups: []string{
+					policyv1.SchemeGroupVersion.Group,
+				},
+				Resources: []string{
+					"poddisruptionbudgets",
+				},
+				Verbs: Verbs,
+			},
 			{
 				APIGroups: []string{
 					monv1.SchemeGroupVersion.Group,
 				},
 				Resources: []string{
 					"servicemonitors",
+					"prometheusrules",
 				},
-				Verbs: []string{
-					"get",
-					"create",
-				},
+				Verbs: Verbs,
 			},
 			{
 				APIGroups: []string{
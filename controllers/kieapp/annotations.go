@@ -0,0 +1,149 @@
+package kieapp
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/RHsyseng/operator-utils/pkg/resource/write/hooks"
+	oappsv1 "github.com/openshift/api/apps/v1"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// externalAnnotationHooks keep the annotations added by other controllers, e.g. a service mesh or a backup tool, to
+// the resources and pod templates being updated
+type externalAnnotationHooks struct {
+	*hooks.UpdateHookMap
+}
+
+func newExternalAnnotationHooks() externalAnnotationHooks {
+	return externalAnnotationHooks{hooks.DefaultUpdateHooks()}
+}
+
+func (updateHooks externalAnnotationHooks) Trigger(existing client.Object, requested client.Object) error {
+	requested.SetAnnotations(addExternalAnnotations(existing.GetAnnotations(), requested.GetAnnotations()))
+	existingTemplate, requestedTemplate := getPodTemplate(existing), getPodTemplate(requested)
+	if existingTemplate != nil && requestedTemplate != nil {
+		requestedTemplate.Annotations = addExternalAnnotations(existingTemplate.Annotations, requestedTemplate.Annotations)
+	}
+	return updateHooks.UpdateHookMap.Trigger(existing, requested)
+}
+
+// setManagedAnnotations records the keys of the annotations requested on the resources and on their pod templates, so
+// the ones the operator set before and no longer requests are told apart from the ones added by other controllers
+func setManagedAnnotations(requested []client.Object) {
+	for _, object := range requested {
+		object.SetAnnotations(withManagedKeys(object.GetAnnotations()))
+		if template := getPodTemplate(object); template != nil {
+			template.Annotations = withManagedKeys(template.Annotations)
+		}
+	}
+}
+
+// withManagedKeys returns a copy of the annotations listing their keys, the annotations maps being shared by resources
+func withManagedKeys(annotations map[string]string) map[string]string {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		if key != constants.ManagedAnnotationsAnnotation {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	result := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result[constants.ManagedAnnotationsAnnotation] = strings.Join(keys, ",")
+	return result
+}
+
+// isExternalAnnotation tells whether the deployed annotation was added by another controller, as it is neither
+// requested nor listed among the managed ones the operator set
+func isExternalAnnotation(key, managed string, requested map[string]string) bool {
+	if _, found := requested[key]; found || key == constants.ManagedAnnotationsAnnotation {
+		return false
+	}
+	for _, managed := range strings.Split(managed, ",") {
+		if managed == key {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutExternalAnnotations returns copies of the deployed resources without the annotations added by other
+// controllers, on the resources or on their pod templates, so that they are not reverted
+func withoutExternalAnnotations(deployed map[reflect.Type][]client.Object, requested map[reflect.Type][]client.Object) map[reflect.Type][]client.Object {
+	result := make(map[reflect.Type][]client.Object, len(deployed))
+	for resourceType, objects := range deployed {
+		requestedObjects := map[string]client.Object{}
+		for _, object := range requested[resourceType] {
+			requestedObjects[object.GetName()] = object
+		}
+		for _, object := range objects {
+			counterpart := requestedObjects[object.GetName()]
+			if counterpart == nil {
+				result[resourceType] = append(result[resourceType], object)
+				continue
+			}
+			object = object.DeepCopyObject().(client.Object)
+			object.SetAnnotations(removeExternalAnnotations(object.GetAnnotations(), counterpart.GetAnnotations()))
+			template, requestedTemplate := getPodTemplate(object), getPodTemplate(counterpart)
+			if template != nil && requestedTemplate != nil {
+				template.Annotations = removeExternalAnnotations(template.Annotations, requestedTemplate.Annotations)
+			}
+			result[resourceType] = append(result[resourceType], object)
+		}
+	}
+	return result
+}
+
+func removeExternalAnnotations(deployed, requested map[string]string) map[string]string {
+	managed := deployed[constants.ManagedAnnotationsAnnotation]
+	for key := range deployed {
+		if isExternalAnnotation(key, managed, requested) {
+			delete(deployed, key)
+		}
+	}
+	if len(deployed) == 0 && requested == nil {
+		return nil
+	}
+	return deployed
+}
+
+func addExternalAnnotations(deployed, requested map[string]string) map[string]string {
+	var result map[string]string
+	for key, value := range deployed {
+		if isExternalAnnotation(key, deployed[constants.ManagedAnnotationsAnnotation], requested) {
+			if result == nil {
+				result = make(map[string]string, len(requested)+len(deployed))
+				for requestedKey, requestedValue := range requested {
+					result[requestedKey] = requestedValue
+				}
+			}
+			result[key] = value
+		}
+	}
+	if result == nil {
+		return requested
+	}
+	return result
+}
+
+func getPodTemplate(object client.Object) *corev1.PodTemplateSpec {
+	switch workload := object.(type) {
+	case *oappsv1.DeploymentConfig:
+		return workload.Spec.Template
+	case *appsv1.Deployment:
+		return &workload.Spec.Template
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Template
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileCommonMetadata(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:       api.RhpamTrial,
+			Platform:          api.KubernetesPlatform,
+			Exposure:          &api.KieAppExposure{Domain: "apps.example.com"},
+			CommonLabels:      map[string]string{"cost-center": "cc-1234"},
+			CommonAnnotations: map[string]string{"backup.velero.io/backup-volumes": "data"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	deploymentName := types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.Equal(t, "cc-1234", deployment.Labels["cost-center"])
+	assert.Equal(t, "cc-1234", deployment.Spec.Template.Labels["cost-center"])
+	assert.Equal(t, "data", deployment.Spec.Template.Annotations["backup.velero.io/backup-volumes"])
+	svc := &corev1.Service{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, svc))
+	assert.Equal(t, "cc-1234", svc.Labels["cost-center"])
+	assert.Equal(t, "data", svc.Annotations["backup.velero.io/backup-volumes"])
+
+	deployment.Annotations["example.com/managed-by"] = "other-controller"
+	deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = "2022-01-01T00:00:00Z"
+	assert.Nil(t, service.Update(context.TODO(), deployment))
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The annotations added by other controllers should not be reverted")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.CommonLabels["team"] = "bpm"
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.Equal(t, "bpm", deployment.Labels["team"])
+	assert.Equal(t, "other-controller", deployment.Annotations["example.com/managed-by"], "The annotations added by other controllers should be kept by updates")
+	assert.Equal(t, "2022-01-01T00:00:00Z", deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.CommonAnnotations = nil
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	deployment = &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.NotContains(t, deployment.Annotations, "backup.velero.io/backup-volumes", "The annotations no longer requested should be removed")
+	assert.NotContains(t, deployment.Spec.Template.Annotations, "backup.velero.io/backup-volumes")
+	svc = &corev1.Service{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, svc))
+	assert.NotContains(t, svc.Annotations, "backup.velero.io/backup-volumes")
+	assert.Equal(t, "other-controller", deployment.Annotations["example.com/managed-by"])
+	assert.Equal(t, "2022-01-01T00:00:00Z", deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+}
+
+func TestRemoveExternalAnnotations(t *testing.T) {
+	deployed := map[string]string{
+		"example.com/external":                 "true",
+		"example.com/removed":                  "true",
+		"example.com/kept":                     "true",
+		constants.ManagedAnnotationsAnnotation: "example.com/kept,example.com/removed",
+	}
+	requested := withManagedKeys(map[string]string{"example.com/kept": "false"})
+	assert.Equal(t, "example.com/kept", requested[constants.ManagedAnnotationsAnnotation])
+
+	assert.Equal(t, map[string]string{
+		"example.com/removed":                  "true",
+		"example.com/kept":                     "true",
+		constants.ManagedAnnotationsAnnotation: "example.com/kept,example.com/removed",
+	}, removeExternalAnnotations(deployed, requested))
+}
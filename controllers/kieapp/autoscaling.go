@@ -0,0 +1,62 @@
+package kieapp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isAutoscaled tells whether a HorizontalPodAutoscaler is generated for any kieserver set of the KieApp
+func isAutoscaled(cr *api.KieApp) bool {
+	for _, server := range cr.Status.Applied.Objects.Servers {
+		if defaults.IsAutoscaled(server) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyAutoscaling checks that the cluster serves the autoscaling/v2beta2 HorizontalPodAutoscalers the operator
+// generates, Kubernetes stopped serving them in 1.26
+func (reconciler *KieAppReconciler) verifyAutoscaling(namespace string) error {
+	err := reconciler.Service.List(context.TODO(), &autoscalingv2beta2.HorizontalPodAutoscalerList{}, client.InNamespace(namespace), client.Limit(1))
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("autoscaling is enabled for a kieserver set but the cluster does not serve autoscaling/v2beta2 HorizontalPodAutoscalers")
+	}
+	return err
+}
+
+// keepAutoscaledReplicas sets the requested replicas of the workloads targeted by a requested HorizontalPodAutoscaler
+// to their deployed replicas, so that updates of those workloads never revert the scaling of the autoscaler
+func keepAutoscaledReplicas(requestedResources []client.Object, deployed map[reflect.Type][]client.Object) {
+	targets := map[string]bool{}
+	for _, object := range requestedResources {
+		if hpa, ok := object.(*autoscalingv2beta2.HorizontalPodAutoscaler); ok {
+			targets[hpa.Spec.ScaleTargetRef.Kind+"/"+hpa.Spec.ScaleTargetRef.Name] = true
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	deployedReplicas := map[string]int32{}
+	for _, objects := range deployed {
+		for _, object := range objects {
+			if count, ok := getReplicas(object); ok {
+				deployedReplicas[reflect.TypeOf(object).Elem().Name()+"/"+object.GetName()] = count
+			}
+		}
+	}
+	for _, object := range requestedResources {
+		key := reflect.TypeOf(object).Elem().Name() + "/" + object.GetName()
+		// autoscalers never scale to zero, workloads resumed from a suspension start from the replicas of the spec
+		if count, ok := deployedReplicas[key]; ok && count > 0 && targets[key] {
+			setReplicas(object, count)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileAutoscaling(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{Autoscaling: &api.KieServerAutoscaling{Enabled: true, MaxReplicas: 5}}},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+	assert.Nil(t, service.List(context.TODO(), hpas, client.InNamespace(name.Namespace)))
+	assert.Len(t, hpas.Items, 1)
+	assert.Equal(t, "test-kieserver", hpas.Items[0].Name)
+	assert.Equal(t, "Deployment", hpas.Items[0].Spec.ScaleTargetRef.Kind)
+
+	// the autoscaler scales the kieserver up
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}, deployment))
+	deployment.Spec.Replicas = defaults.Pint32(4)
+	assert.Nil(t, service.Update(context.TODO(), deployment))
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The autoscaled Deployment should not be updated")
+	assert.Equal(t, int32(4), getDeploymentReplicas(t, service, name.Namespace)["test-kieserver"])
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Objects.Servers[0].Autoscaling.Enabled = false
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	hpas = &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+	assert.Nil(t, service.List(context.TODO(), hpas, client.InNamespace(name.Namespace)))
+	assert.Empty(t, hpas.Items)
+	assert.Equal(t, int32(1), getDeploymentReplicas(t, service, name.Namespace)["test-kieserver"])
+}
+
+func TestReconcileAutoscalingNotServed(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	// as on Kubernetes 1.26 and later
+	service.ListFunc = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		if _, ok := list.(*autoscalingv2beta2.HorizontalPodAutoscalerList); ok {
+			return &meta.NoKindMatchError{GroupKind: autoscalingv2beta2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler").GroupKind()}
+		}
+		return service.Client.List(ctx, list, opts...)
+	}
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: record.NewFakeRecorder(100)}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Contains(t, getDeploymentReplicas(t, service, name.Namespace), "test-kieserver", "KieApps without autoscaling should be deployed")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Objects.Servers = []api.KieServerSet{{Autoscaling: &api.KieServerAutoscaling{Enabled: true, MaxReplicas: 5}}}
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.NotNil(t, err)
+
+	cr = &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	condition := cr.Status.Conditions[len(cr.Status.Conditions)-1]
+	assert.Equal(t, api.FailedConditionType, condition.Type)
+	assert.Equal(t, api.MissingDependenciesReason, condition.Reason)
+	assert.Contains(t, condition.Message, "autoscaling/v2beta2")
+}
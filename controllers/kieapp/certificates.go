@@ -0,0 +1,322 @@
+package kieapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/shared"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isCertManaged tells whether the certificates of the components are issued by cert-manager
+func isCertManaged(cr *api.KieApp) bool {
+	return cr.Status.Applied.TLS != nil && cr.Status.Applied.TLS.CertManager != nil
+}
+
+// verifyCertManager checks that the cert-manager CRDs are installed, the certificates of the components can't be
+// issued otherwise
+func (reconciler *KieAppReconciler) verifyCertManager(namespace string) error {
+	err := reconciler.Service.List(context.TODO(), &certmanagerv1.CertificateList{}, client.InNamespace(namespace), client.Limit(1))
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("spec.tls.certManager is set but the cert-manager CRDs are not installed in the cluster")
+	}
+	return err
+}
+
+// isRenewalDue tells whether a certificate expiring at the given time is within the renewal window of the KieApp
+func isRenewalDue(cr *api.KieApp, expiry time.Time) bool {
+	return !time.Now().Before(getRenewalTime(cr, expiry))
+}
+
+// getRenewalTime returns when the operator renews a certificate it generated
+func getRenewalTime(cr *api.KieApp, expiry time.Time) time.Time {
+	return expiry.Add(-defaults.GetCertificateRenewBefore(cr))
+}
+
+// setCertificateStatus reports the expiry of the certificate held by a secret, and when it is renewed
+func setCertificateStatus(cr *api.KieApp, secretName string, expiry, renewal time.Time) {
+	// the times are kept in the local zone, as they are once read back from the cluster, not to update the status on
+	// every reconcile
+	cr.Status.Certificates = append(cr.Status.Certificates, api.CertificateStatus{
+		Secret:      secretName,
+		NotAfter:    metav1.NewTime(expiry.Local()),
+		RenewalTime: metav1.NewTime(renewal.Local()),
+	})
+}
+
+// getPlannedSecret returns the secret standing for one the operator would generate while the plan of the KieApp awaits
+// approval: the deployed secret with the generated keys cleared, or an empty one when it is missing. The secret is only
+// generated once the plan is approved, so the plan does not change between reconciles.
+func getPlannedSecret(existingSecret corev1.Secret, secretName string, secretType corev1.SecretType, cr *api.KieApp, keys ...string) corev1.Secret {
+	log.Infof("Secret %s is generated once the plan of KieApp %s/%s is approved", secretName, cr.Namespace, cr.Name)
+	secret := *existingSecret.DeepCopy()
+	if secret.Name == "" {
+		secret = corev1.Secret{
+			Type: secretType,
+			ObjectMeta: metav1.ObjectMeta{
+				Name: secretName,
+				Labels: map[string]string{
+					"app":         cr.Status.Applied.CommonConfig.ApplicationName,
+					"application": cr.Status.Applied.CommonConfig.ApplicationName,
+				},
+			},
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for _, key := range keys {
+		secret.Data[key] = []byte{}
+	}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return secret
+}
+
+// getKeystoreSecret returns the keystore secret of a component. With cert-manager, the keystore is converted from the
+// certificate it issued, and the Certificate is added to the component. The returned secret is then empty until the
+// certificate is issued. Otherwise, the keystore is signed by the CA of the KieApp.
+func (reconciler *KieAppReconciler) getKeystoreSecret(name, keystoreCN string, object *api.CustomObject, cr *api.KieApp, ca *shared.CertificateAuthority) (corev1.Secret, error) {
+	secretName := fmt.Sprintf(constants.KeystoreSecret, name)
+	dnsNames := getDNSNames(cr, *object, keystoreCN)
+	if !isCertManaged(cr) {
+		return reconciler.generateKeystoreSecret(secretName, keystoreCN, dnsNames, cr, ca)
+	}
+	certificate := getCertificate(cr, name, keystoreCN, dnsNames)
+	object.Certificates = append(object.Certificates, certificate)
+	issuedSecret := corev1.Secret{}
+	err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: certificate.Spec.SecretName, Namespace: cr.Namespace}, &issuedSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return corev1.Secret{}, err
+	}
+	if len(issuedSecret.Data[corev1.TLSCertKey]) == 0 || len(issuedSecret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		log.Infof("Waiting for cert-manager to issue the certificate of secret %s", certificate.Spec.SecretName)
+		return corev1.Secret{}, nil
+	}
+	setReencryptRoutes(object, issuedSecret)
+	return reconciler.convertIssuedSecret(secretName, certificate.Name, issuedSecret, cr)
+}
+
+// getCertificate returns the cert-manager Certificate of a component, issued to the secret named after it
+func getCertificate(cr *api.KieApp, name, commonName string, dnsNames []string) certmanagerv1.Certificate {
+	certManager := cr.Status.Applied.TLS.CertManager
+	certificate := certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app":         cr.Status.Applied.CommonConfig.ApplicationName,
+				"application": cr.Status.Applied.CommonConfig.ApplicationName,
+			},
+		},
+		Spec: certmanagerv1.CertificateSpec{
+			Duration:    certManager.Duration,
+			RenewBefore: certManager.RenewBefore,
+			DNSNames:    dnsNames,
+			SecretName:  fmt.Sprintf(constants.IssuedCertificateSecret, name),
+			IssuerRef: certmanagerv1.ObjectReference{
+				Name:  certManager.IssuerRef.Name,
+				Kind:  certManager.IssuerRef.Kind,
+				Group: certManager.IssuerRef.Group,
+			},
+		},
+	}
+	if tls := cr.Status.Applied.TLS; tls.KeyAlgorithm != "" || tls.KeySize != 0 {
+		opts := defaults.GetKeystoreOptions(cr)
+		certificate.Spec.PrivateKey = &certmanagerv1.CertificatePrivateKey{Algorithm: opts.KeyAlgorithm, Size: opts.KeySize}
+	}
+	// cert-manager rejects the common names longer than the 64 characters allowed by X.509
+	if len(commonName) <= 64 {
+		certificate.Spec.CommonName = commonName
+	}
+	return certificate
+}
+
+// setReencryptRoutes switches the TLS routes of a component to re-encrypt, presenting the certificate issued by
+// cert-manager to the clients and trusting its CA to reach the pods
+func setReencryptRoutes(object *api.CustomObject, issuedSecret corev1.Secret) {
+	for i := range object.Routes {
+		if tls := object.Routes[i].Spec.TLS; checkTLS(tls) {
+			tls.Termination = routev1.TLSTerminationReencrypt
+			tls.Certificate = string(issuedSecret.Data[corev1.TLSCertKey])
+			tls.Key = string(issuedSecret.Data[corev1.TLSPrivateKeyKey])
+			tls.CACertificate = string(issuedSecret.Data[constants.IssuedCAKey])
+			tls.DestinationCACertificate = string(issuedSecret.Data[constants.IssuedCAKey])
+		}
+	}
+}
+
+// convertIssuedSecret returns the secret holding the keystore converted from the certificate issued by cert-manager,
+// converting it again once cert-manager renews the certificate
+func (reconciler *KieAppReconciler) convertIssuedSecret(secretName, certificateName string, issuedSecret corev1.Secret, cr *api.KieApp) (secret corev1.Secret, err error) {
+	existingSecret := corev1.Secret{}
+	err = reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, &existingSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return secret, err
+	}
+	keyStorePassword := []byte(cr.Status.Applied.CommonConfig.KeyStorePassword)
+	format := defaults.GetKeystoreOptions(cr).Format
+	keystoreName := shared.KeystoreName(format)
+	issuedCertificate := issuedSecret.Data[corev1.TLSCertKey]
+	if ok, _ := shared.IsConvertedKeyStore(issuedCertificate, keyStorePassword, existingSecret.Data[keystoreName], format); ok {
+		secret = existingSecret
+	} else {
+		keystoreByte, err := shared.ConvertToKeystore(issuedCertificate, issuedSecret.Data[corev1.TLSPrivateKeyKey], keyStorePassword, format)
+		if err != nil {
+			reconciler.recordEvent(cr, corev1.EventTypeWarning, KeystoreGeneratedEventReason, "Failed to convert the certificate of secret %s to a keystore: %v", issuedSecret.Name, err)
+			return secret, err
+		}
+		if existingSecret.Name != "" {
+			reconciler.recordEvent(cr, corev1.EventTypeNormal, KeystoreGeneratedEventReason, "Regenerated the keystore of secret %s from the certificate of secret %s", secretName, issuedSecret.Name)
+		}
+		secret = corev1.Secret{
+			Type: corev1.SecretTypeOpaque,
+			ObjectMeta: metav1.ObjectMeta{
+				Name: secretName,
+				Labels: map[string]string{
+					"app":         cr.Status.Applied.CommonConfig.ApplicationName,
+					"application": cr.Status.Applied.CommonConfig.ApplicationName,
+				},
+			},
+			Data: map[string][]byte{
+				keystoreName: keystoreByte,
+			},
+		}
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	}
+	if expiry, err := shared.GetKeyStoreExpiry(keyStorePassword, secret.Data[keystoreName], format); err == nil {
+		trackedApps.trackKeystore(cr, secretName, expiry)
+		// cert-manager reports when it renews the certificate
+		renewal := expiry
+		certificate := &certmanagerv1.Certificate{}
+		if err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: certificateName, Namespace: cr.Namespace}, certificate); err == nil && certificate.Status.RenewalTime != nil {
+			renewal = certificate.Status.RenewalTime.Time
+		}
+		setCertificateStatus(cr, secretName, expiry, renewal)
+	}
+	return secret, nil
+}
+
+// getIssuerCertificates returns the PEM encoded CA certificates of the secrets issued by cert-manager to the components
+func (reconciler *KieAppReconciler) getIssuerCertificates(cr *api.KieApp, env api.Environment) ([]byte, error) {
+	var caCertificates []byte
+	for _, object := range getCustomObjects(env) {
+		for _, certificate := range object.Certificates {
+			issuedSecret := corev1.Secret{}
+			err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: certificate.Spec.SecretName, Namespace: cr.Namespace}, &issuedSecret)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			if caCertificate := issuedSecret.Data[constants.IssuedCAKey]; len(caCertificate) > 0 && !bytes.Contains(caCertificates, caCertificate) {
+				caCertificates = append(append(caCertificates, caCertificate...), '\n')
+			}
+		}
+	}
+	return caCertificates, nil
+}
+
+// getNextRenewal returns how long until the next certificate of the KieApp is due for renewal, or zero when it has no
+// generated certificate
+func getNextRenewal(cr *api.KieApp) time.Duration {
+	var next time.Duration
+	for _, certificate := range cr.Status.Certificates {
+		if until := time.Until(certificate.RenewalTime.Time); next == 0 || until < next {
+			next = until
+		}
+	}
+	if next < 0 {
+		return time.Second
+	}
+	return next
+}
+
+// setCertificatesHash annotates the pod templates mounting the generated keystores or truststore with a hash of their
+// content, so that the pods are rolled out with the renewed certificates
+func setCertificatesHash(env api.Environment, secrets []corev1.Secret) api.Environment {
+	if len(secrets) == 0 {
+		return env
+	}
+	setObjectCertificatesHash(&env.Console, secrets)
+	setObjectCertificatesHash(&env.Dashbuilder, secrets)
+	setObjectCertificatesHash(&env.SmartRouter, secrets)
+	setObjectCertificatesHash(&env.ProcessMigration, secrets)
+	for i := range env.Servers {
+		setObjectCertificatesHash(&env.Servers[i], secrets)
+	}
+	return env
+}
+
+func setObjectCertificatesHash(object *api.CustomObject, secrets []corev1.Secret) {
+	var templates []*corev1.PodTemplateSpec
+	for i := range object.DeploymentConfigs {
+		templates = append(templates, object.DeploymentConfigs[i].Spec.Template)
+	}
+	for i := range object.Deployments {
+		templates = append(templates, &object.Deployments[i].Spec.Template)
+	}
+	for i := range object.StatefulSets {
+		templates = append(templates, &object.StatefulSets[i].Spec.Template)
+	}
+	for _, template := range templates {
+		if template == nil {
+			continue
+		}
+		if hash := getMountedSecretsHash(template.Spec, secrets); hash != "" {
+			annotations := make(map[string]string, len(template.Annotations)+1)
+			for key, value := range template.Annotations {
+				annotations[key] = value
+			}
+			annotations[constants.CertificatesHashAnnotation] = hash
+			template.Annotations = annotations
+		}
+	}
+}
+
+// getMountedSecretsHash returns the hash of the secrets mounted by the pod, or an empty string when none is mounted
+func getMountedSecretsHash(pod corev1.PodSpec, secrets []corev1.Secret) string {
+	mounted := map[string]bool{}
+	for _, volume := range pod.Volumes {
+		if volume.Secret != nil {
+			mounted[volume.Secret.SecretName] = true
+		}
+	}
+	var names []string
+	data := map[string]map[string][]byte{}
+	for _, secret := range secrets {
+		if mounted[secret.Name] {
+			names = append(names, secret.Name)
+			data[secret.Name] = secret.Data
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		keys := make([]string, 0, len(data[name]))
+		for key := range data[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		hash.Write([]byte(name))
+		for _, key := range keys {
+			hash.Write([]byte(key))
+			hash.Write(data[name][key])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
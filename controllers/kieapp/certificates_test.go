@@ -0,0 +1,514 @@
+package kieapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/pavel-v-chernykh/keystore-go/v4"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/shared"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileCA(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	caSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-ca", Namespace: name.Namespace}, caSecret))
+	assert.Equal(t, corev1.SecretTypeTLS, caSecret.Type)
+	ca, err := shared.ParseCA(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+	assert.Nil(t, err)
+
+	credentials := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: defaults.GetCredentialsSecretName(cr), Namespace: name.Namespace}, credentials))
+	password := credentials.Data["keyStorePassword"]
+	keystoreSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver-app-secret", Namespace: name.Namespace}, keystoreSecret))
+	keyStore := keystore.New()
+	assert.Nil(t, keyStore.Load(bytes.NewReader(keystoreSecret.Data[constants.KeystoreName]), password))
+	entry, err := keyStore.GetPrivateKeyEntry(constants.KeystoreAlias, password)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(entry.CertificateChain[0].Content)
+	assert.Nil(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "test-kieserver-test-ns.apps.example.com", Roots: roots})
+	assert.Nil(t, err, "The kieserver certificate should be issued by the CA for its service name")
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The CA and keystores should be kept between reconciles")
+}
+
+func TestRenewCertificates(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	var keystoreStatus *api.CertificateStatus
+	for i := range cr.Status.Certificates {
+		if cr.Status.Certificates[i].Secret == "test-kieserver-app-secret" {
+			keystoreStatus = &cr.Status.Certificates[i]
+		}
+	}
+	if assert.NotNil(t, keystoreStatus, "The expiry of the kieserver keystore should be reported") {
+		assert.Equal(t, keystoreStatus.NotAfter.Add(-constants.KeystoreRenewBefore*24*time.Hour), keystoreStatus.RenewalTime.Time)
+	}
+	deploymentName := types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	hash := deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation]
+	assert.NotEmpty(t, hash)
+	keystoreSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver-app-secret", Namespace: name.Namespace}, keystoreSecret))
+
+	resourceVersion := cr.ResourceVersion
+	result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.True(t, result.RequeueAfter > (constants.KeystoreValidity-constants.KeystoreRenewBefore-1)*24*time.Hour, "The KieApp should be reconciled again when its certificates are due for renewal")
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Equal(t, resourceVersion, cr.ResourceVersion, "The status should not be updated while the certificates are valid")
+
+	// a window longer than the validity of the keystores makes them due for renewal
+	cr.Spec.TLS = &api.KieAppTLS{RenewBefore: &metav1.Duration{Duration: (constants.KeystoreValidity + 1) * 24 * time.Hour}}
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Contains(t, getEvents(recorder), "Normal KeystoreGenerated Regenerated the keystore of secret test-kieserver-app-secret for test-kieserver-test-ns.apps.example.com")
+	renewedSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver-app-secret", Namespace: name.Namespace}, renewedSecret))
+	assert.NotEqual(t, keystoreSecret.Data[constants.KeystoreName], renewedSecret.Data[constants.KeystoreName])
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation], "The kieserver should be rolled out with the renewed keystore")
+}
+
+func TestMigrateKeystoreFormat(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	deploymentName := types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	hash := deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation]
+	rsaCASecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-ca", Namespace: name.Namespace}, rsaCASecret))
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.TLS = &api.KieAppTLS{KeystoreFormat: api.KeystoreFormatPKCS12, KeyAlgorithm: api.KeyAlgorithmECDSA}
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Contains(t, getEvents(recorder), "Normal CAGenerated Regenerated the CA of secret test-ca")
+
+	caSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-ca", Namespace: name.Namespace}, caSecret))
+	ca, err := shared.ParseCA(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+	assert.Nil(t, err)
+	assert.NotEqual(t, rsaCASecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSCertKey])
+	assert.True(t, ca.HasKeyAlgorithm(shared.KeystoreOptions{KeyAlgorithm: constants.ECDSAKeyAlgorithm}), "The CA should be regenerated with an ECDSA key")
+	credentials := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: defaults.GetCredentialsSecretName(cr), Namespace: name.Namespace}, credentials))
+	keystoreSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver-app-secret", Namespace: name.Namespace}, keystoreSecret))
+	assert.NotContains(t, keystoreSecret.Data, constants.KeystoreName, "The JKS keystore should be replaced")
+	opts := shared.KeystoreOptions{Format: constants.PKCS12KeystoreFormat, KeyAlgorithm: constants.ECDSAKeyAlgorithm}
+	ok, err := shared.IsValidKeyStoreSecret(*keystoreSecret, "test-kieserver-test-ns.apps.example.com", nil, credentials.Data["keyStorePassword"], ca, opts)
+	assert.True(t, ok, "The keystore should be migrated to PKCS12 with an ECDSA key")
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation], "The kieserver should be rolled out with the migrated keystore")
+}
+
+func TestReconcileCATruststore(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: record.NewFakeRecorder(100)}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	caSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-ca", Namespace: name.Namespace}, caSecret))
+	truststoreSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test" + constants.TruststoreSecret, Namespace: name.Namespace}, truststoreSecret))
+	ok, err := shared.IsValidTruststoreSecret(*truststoreSecret, caSecret.Data[corev1.TLSCertKey], constants.JKSKeystoreFormat)
+	assert.True(t, ok, "The CA of the KieApp should be trusted without any truststore setting")
+	assert.Nil(t, err)
+	for _, deploymentName := range []string{"test-rhpamcentr", "test-kieserver"} {
+		deployment := &appsv1.Deployment{}
+		assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: deploymentName, Namespace: name.Namespace}, deployment))
+		assert.Contains(t, deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         "test-truststore",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "test-truststore"}},
+		}, deploymentName)
+		assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "JAVA_OPTS_APPEND",
+			Value: "-Djavax.net.ssl.trustStoreType=jks -Djavax.net.ssl.trustStore=/etc/openshift-truststore-volume/truststore.jks -Djavax.net.ssl.trustStorePassword=changeit",
+		}, deploymentName)
+	}
+}
+
+func TestReconcileTruststoreSources(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	caBundle, err := ioutil.ReadFile("shared/test-" + constants.CaBundleKey)
+	assert.Nil(t, err)
+	privateCA, err := shared.GenerateCA("private-ca", shared.KeystoreOptions{})
+	assert.Nil(t, err)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "public-cas", Namespace: name.Namespace},
+		Data:       map[string]string{"ca.crt": string(caBundle)},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "private-ca", Namespace: name.Namespace},
+		Data:       map[string][]byte{"ca.crt": privateCA.CertificatePEM()},
+	}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Truststore: &api.KieAppTruststore{
+				From: []api.TruststoreSource{
+					{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name}, Key: "ca.crt"}},
+					{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name}, Key: "ca.crt"}},
+					{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "ca.crt", Optional: defaults.Pbool(true)}},
+				},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), configMap))
+	assert.Nil(t, service.Create(context.TODO(), secret))
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	caSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-ca", Namespace: name.Namespace}, caSecret))
+	truststoreName := types.NamespacedName{Name: "test" + constants.TruststoreSecret, Namespace: name.Namespace}
+	truststoreSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), truststoreName, truststoreSecret))
+	trusted := bytes.Join([][]byte{caBundle, privateCA.CertificatePEM(), caSecret.Data[corev1.TLSCertKey]}, []byte{'\n'})
+	ok, err := shared.IsValidTruststoreSecret(*truststoreSecret, trusted, constants.JKSKeystoreFormat)
+	assert.True(t, ok, "The CA bundles of the sources should be trusted along with the CA of the KieApp")
+	assert.Nil(t, err)
+	deploymentName := types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "JAVA_OPTS_APPEND",
+		Value: "-Djavax.net.ssl.trustStoreType=jks -Djavax.net.ssl.trustStore=/etc/openshift-truststore-volume/truststore.jks -Djavax.net.ssl.trustStorePassword=changeit",
+	})
+	assert.Contains(t, deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         "test-truststore",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "test-truststore"}},
+	})
+	hash := deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation]
+
+	// rotating the private CA regenerates the truststore and rolls the components out
+	assert.Equal(t, []reconcile.Request{{NamespacedName: name}}, truststoreSourceRequests(service.Client)(secret))
+	assert.Empty(t, truststoreSourceRequests(service.Client)(caSecret))
+	rotatedCA, err := shared.GenerateCA("private-ca", shared.KeystoreOptions{})
+	assert.Nil(t, err)
+	secret.Data["ca.crt"] = rotatedCA.CertificatePEM()
+	assert.Nil(t, service.Update(context.TODO(), secret))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), truststoreName, truststoreSecret))
+	trusted = bytes.Join([][]byte{caBundle, rotatedCA.CertificatePEM(), caSecret.Data[corev1.TLSCertKey]}, []byte{'\n'})
+	ok, err = shared.IsValidTruststoreSecret(*truststoreSecret, trusted, constants.JKSKeystoreFormat)
+	assert.True(t, ok, "The truststore should trust the rotated CA")
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation])
+
+	assert.Nil(t, service.Delete(context.TODO(), configMap))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.NotNil(t, err, "The configmap source isn't optional")
+}
+
+func TestReconcileMissingTruststoreBundles(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	caBundle, err := ioutil.ReadFile("shared/test-" + constants.CaBundleKey)
+	assert.Nil(t, err)
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:  api.RhpamTrial,
+			Platform:     api.KubernetesPlatform,
+			Exposure:     &api.KieAppExposure{Domain: "apps.example.com"},
+			CommonConfig: api.CommonConfig{DisableSsl: true},
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{Jvm: &api.JvmObject{JavaOptsAppend: "-Dsome.property=foo"}}},
+			},
+			Truststore: &api.KieAppTruststore{
+				From: []api.TruststoreSource{
+					{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "public-cas"}, Key: "ca.crt", Optional: defaults.Pbool(true)}},
+				},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: record.NewFakeRecorder(100)}
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	truststoreName := types.NamespacedName{Name: "test" + constants.TruststoreSecret, Namespace: name.Namespace}
+	assert.True(t, errors.IsNotFound(service.Get(context.TODO(), truststoreName, &corev1.Secret{})))
+	deploymentName := types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		assert.NotEqual(t, "test-truststore", volume.Name, "The pods should not wait for the missing truststore")
+	}
+	for _, volumeMount := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+		assert.NotEqual(t, "test-truststore", volumeMount.Name)
+	}
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "JAVA_OPTS_APPEND", Value: "-Dsome.property=foo"})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "public-cas", Namespace: name.Namespace},
+		Data:       map[string][]byte{"ca.crt": caBundle},
+	}
+	assert.Nil(t, service.Create(context.TODO(), secret))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: name}}, truststoreSourceRequests(service.Client)(secret))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), truststoreName, &corev1.Secret{}))
+	deployment = &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.Contains(t, deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         "test-truststore",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "test-truststore"}},
+	})
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "JAVA_OPTS_APPEND",
+		Value: "-Dsome.property=foo -Djavax.net.ssl.trustStoreType=jks -Djavax.net.ssl.trustStore=/etc/openshift-truststore-volume/truststore.jks -Djavax.net.ssl.trustStorePassword=changeit",
+	})
+	assert.NotEmpty(t, deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation], "The pods should be rolled out with the truststore")
+}
+
+func TestReconcileCertManager(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			TLS: &api.KieAppTLS{CertManager: &api.KieAppCertManager{
+				IssuerRef: api.CertManagerIssuerRef{Name: "test-issuer", Kind: "ClusterIssuer"},
+				Duration:  &metav1.Duration{Duration: 2160 * time.Hour},
+			}},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	certificate := &certmanagerv1.Certificate{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}, certificate))
+	assert.Equal(t, "test-kieserver-tls", certificate.Spec.SecretName)
+	assert.Equal(t, certmanagerv1.ObjectReference{Name: "test-issuer", Kind: "ClusterIssuer"}, certificate.Spec.IssuerRef)
+	assert.Equal(t, 2160*time.Hour, certificate.Spec.Duration.Duration)
+	assert.Contains(t, certificate.Spec.DNSNames, "test-kieserver-test-ns.apps.example.com")
+	keystoreName := types.NamespacedName{Name: "test-kieserver-app-secret", Namespace: name.Namespace}
+	assert.True(t, errors.IsNotFound(service.Get(context.TODO(), keystoreName, &corev1.Secret{})), "The keystore should wait for the certificate to be issued")
+	assert.True(t, errors.IsNotFound(service.Get(context.TODO(), types.NamespacedName{Name: "test-ca", Namespace: name.Namespace}, &corev1.Secret{})), "No CA should be generated")
+
+	ca, err := shared.GenerateCA("test-issuer", shared.KeystoreOptions{})
+	assert.Nil(t, err)
+	issuedSecret := issueSecret(t, ca, certificate)
+	assert.Nil(t, service.Create(context.TODO(), issuedSecret))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	credentials := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: defaults.GetCredentialsSecretName(cr), Namespace: name.Namespace}, credentials))
+	password := credentials.Data["keyStorePassword"]
+	keystoreSecret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), keystoreName, keystoreSecret))
+	ok, err := shared.IsConvertedKeyStore(issuedSecret.Data[corev1.TLSCertKey], password, keystoreSecret.Data[constants.KeystoreName], constants.JKSKeystoreFormat)
+	assert.True(t, ok, "The keystore should hold the issued certificate")
+	assert.Nil(t, err)
+	deploymentName := types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	hash := deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation]
+	assert.NotEmpty(t, hash)
+
+	// cert-manager renews the certificate
+	assert.Nil(t, service.Update(context.TODO(), issueSecret(t, ca, certificate)))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), keystoreName, keystoreSecret))
+	ok, err = shared.IsConvertedKeyStore(issuedSecret.Data[corev1.TLSCertKey], password, keystoreSecret.Data[constants.KeystoreName], constants.JKSKeystoreFormat)
+	assert.False(t, ok, "The keystore should hold the renewed certificate")
+	assert.Nil(t, err)
+	assert.Nil(t, service.Get(context.TODO(), deploymentName, deployment))
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations[constants.CertificatesHashAnnotation], "The kieserver should be rolled out with the renewed certificate")
+}
+
+func TestReconcileCertManagerNotInstalled(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			TLS: &api.KieAppTLS{CertManager: &api.KieAppCertManager{
+				IssuerRef: api.CertManagerIssuerRef{Name: "test-issuer", Kind: "ClusterIssuer"},
+			}},
+		},
+	}
+	service := test.MockService()
+	service.ListFunc = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		if _, ok := list.(*certmanagerv1.CertificateList); ok {
+			return &meta.NoKindMatchError{GroupKind: certmanagerv1.GroupVersion.WithKind(certmanagerv1.CertificateKind).GroupKind()}
+		}
+		return service.Client.List(ctx, list, opts...)
+	}
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: record.NewFakeRecorder(100)}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.NotNil(t, err)
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.NotEmpty(t, cr.Status.Conditions)
+	condition := cr.Status.Conditions[len(cr.Status.Conditions)-1]
+	assert.Equal(t, api.FailedConditionType, condition.Type)
+	assert.Equal(t, api.MissingDependenciesReason, condition.Reason)
+	assert.Contains(t, condition.Message, "cert-manager")
+	assert.Empty(t, getDeploymentReplicas(t, service, name.Namespace), "Nothing should be deployed without cert-manager")
+}
+
+func TestSetReencryptRoutes(t *testing.T) {
+	object := api.CustomObject{Routes: []routev1.Route{
+		{Spec: routev1.RouteSpec{TLS: &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough}}},
+		{Spec: routev1.RouteSpec{}},
+	}}
+	issuedSecret := corev1.Secret{Data: map[string][]byte{
+		corev1.TLSCertKey:       []byte("certificate"),
+		corev1.TLSPrivateKeyKey: []byte("key"),
+		constants.IssuedCAKey:   []byte("ca"),
+	}}
+	setReencryptRoutes(&object, issuedSecret)
+	assert.Equal(t, &routev1.TLSConfig{
+		Termination:              routev1.TLSTerminationReencrypt,
+		Certificate:              "certificate",
+		Key:                      "key",
+		CACertificate:            "ca",
+		DestinationCACertificate: "ca",
+	}, object.Routes[0].Spec.TLS)
+	assert.Nil(t, object.Routes[1].Spec.TLS, "The insecure routes should be kept")
+}
+
+// issueSecret returns the secret cert-manager issues for the Certificate, signed by the CA
+func issueSecret(t *testing.T, ca *shared.CertificateAuthority, certificate *certmanagerv1.Certificate) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: certificate.Spec.CommonName},
+		DNSNames:     certificate.Spec.DNSNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(certificate.Spec.Duration.Duration),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, &key.PublicKey, ca.PrivateKey)
+	assert.Nil(t, err)
+	derKey, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: certificate.Spec.SecretName, Namespace: certificate.Namespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derKey}),
+			constants.IssuedCAKey:   ca.CertificatePEM(),
+		},
+	}
+}
@@ -93,6 +93,8 @@ const (
 	ConfigMapPrefix = "kieconfigs"
 	// KieServerCMLabel the label to modify when replicas is set to 0
 	KieServerCMLabel = "services.server.kie.org/kie-server-state"
+	// KieAppFinalizer holds the deletion of a KieApp until the resources created for it are cleaned up
+	KieAppFinalizer = "app.kiegroup.org/finalizer"
 	// DefaultAdminUser default admin user
 	DefaultAdminUser = "adminUser"
 	// DefaultPassword default password to use for test environments
@@ -108,28 +110,69 @@ const (
 	// OpUIEnv is an environment variable indicating whether the UI should be deployed
 	// Default behavior is to deploy the UI, unless this variable is provided with a false value
 	OpUIEnv = "OPERATOR_UI"
+	// EnableWebhooksEnv is an environment variable indicating whether the admission webhooks should be served
+	// Default behavior is to serve them, unless this variable is provided with a false value
+	EnableWebhooksEnv = "ENABLE_WEBHOOKS"
 	// TrialEnvSuffix is the suffix for trial environments
 	TrialEnvSuffix = "trial"
 	// DefaultKieDeployments default number of Kie Server deployments
 	DefaultKieDeployments = 1
 	// KeystoreSecret is the default format for keystore secret names
 	KeystoreSecret = "%s-app-secret"
+	// IssuedCertificateSecret is the default format for the names of the secrets the cert-manager Certificates are issued to
+	IssuedCertificateSecret = "%s-tls"
+	// IssuedCAKey is the key of the CA certificate in the secrets issued by cert-manager
+	IssuedCAKey = "ca.crt"
+	// CredentialsSecret is the default format for the names of the secrets holding the generated credentials
+	CredentialsSecret = "%s-credentials"
+	// MonitoringSecret is the default format for the names of the secrets holding the user the metrics are scraped with
+	MonitoringSecret = "%s-monitoring"
+	// DefaultHeapUsageThreshold percentage of the maximum heap above which the high heap usage alert fires
+	DefaultHeapUsageThreshold = 90
 	// KeystoreVolumeSuffix Suffix for the keystore volumes and volumeMounts name
 	KeystoreVolumeSuffix = "keystore-volume"
 	// KeystoreAlias used when creating entry in Keystore
 	KeystoreAlias = "jboss"
 	// KeystoreName used when creating Secret
 	KeystoreName = "keystore.jks"
+	// PKCS12KeystoreName used when creating Secret with a PKCS12 keystore
+	PKCS12KeystoreName = "keystore.p12"
 	// TruststoreSecret is the default format for truststore secret names
 	TruststoreSecret = "-truststore"
 	// TruststoreName used when creating Secret
 	TruststoreName = "truststore.jks"
+	// PKCS12TruststoreName used when creating Secret with a PKCS12 truststore
+	PKCS12TruststoreName = "truststore.p12"
 	// TruststorePath used when mounting Secret
 	TruststorePath = "/etc/openshift-truststore-volume"
 	// TruststorePwd used when creating Secret
 	TruststorePwd = "changeit"
 	// CaBundleKey ...
 	CaBundleKey = "ca-bundle.crt"
+	// CASecret is the default format for the names of the secrets holding the CA signing the keystore certificates
+	CASecret = "%s-ca"
+	// CAValidity is the number of years the generated CA is valid for
+	CAValidity = 10
+	// KeystoreValidity is the number of days the certificates of the generated keystores are valid for
+	KeystoreValidity = 730
+	// KeystoreRenewBefore is the default number of days before their expiry the generated CA and keystores are renewed
+	KeystoreRenewBefore = 30
+	// JKSKeystoreFormat is the default format of the generated keystores and truststore
+	JKSKeystoreFormat = "jks"
+	// PKCS12KeystoreFormat is the PKCS #12 format of the generated keystores and truststore
+	PKCS12KeystoreFormat = "pkcs12"
+	// RSAKeyAlgorithm is the default algorithm of the private keys of the generated keystores
+	RSAKeyAlgorithm = "RSA"
+	// ECDSAKeyAlgorithm is the elliptic curve algorithm of the private keys of the generated keystores
+	ECDSAKeyAlgorithm = "ECDSA"
+	// DefaultRSAKeySize is the default size in bits of the RSA private keys
+	DefaultRSAKeySize = 2048
+	// DefaultECDSAKeySize is the default size in bits of the ECDSA private keys, i.e. the P-256 curve
+	DefaultECDSAKeySize = 256
+	// CertificatesHashAnnotation holds the hash of the generated keystores and truststore mounted by a pod template
+	CertificatesHashAnnotation = "app.kiegroup.org/certificates-hash"
+	// ManagedAnnotationsAnnotation lists the keys of the annotations set by the operator on a resource or pod template
+	ManagedAnnotationsAnnotation = "app.kiegroup.org/managed-annotations"
 	// HttpProtocol ...
 	HttpProtocol = "http"
 	// HttpsProtocol ...
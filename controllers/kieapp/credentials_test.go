@@ -0,0 +1,56 @@
+package kieapp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestFailedStatusWithoutCredentials(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			CommonConfig: api.CommonConfig{
+				AdminPassword: "inline-admin-password",
+			},
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{GitHooks: &api.GitHooksVolume{
+					From: &api.ObjRef{Kind: "ConfigMap", ObjectReference: api.ObjectReference{Name: "missing-githooks"}},
+				}},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: record.NewFakeRecorder(100)}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.NotNil(t, err)
+
+	persisted := &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, persisted))
+	assert.NotEmpty(t, persisted.Status.Conditions)
+	condition := persisted.Status.Conditions[len(persisted.Status.Conditions)-1]
+	assert.Equal(t, api.FailedConditionType, condition.Type)
+	assert.Equal(t, api.MissingDependenciesReason, condition.Reason)
+	commonConfig := persisted.Status.Applied.CommonConfig
+	assert.Empty(t, commonConfig.AdminPassword)
+	assert.Empty(t, commonConfig.KeyStorePassword, "Generated passwords should not be persisted either")
+	assert.Empty(t, commonConfig.DBPassword)
+	assert.Empty(t, commonConfig.AMQPassword)
+	assert.Empty(t, commonConfig.AMQClusterPassword)
+	status, err := json.Marshal(persisted.Status)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(status), "inline-admin-password")
+}
@@ -0,0 +1,101 @@
+package defaults
+
+import (
+	oappsv1 "github.com/openshift/api/apps/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultTargetCPUUtilization is the CPU utilization aimed for when the autoscaling of a set configures no target
+const defaultTargetCPUUtilization = int32(80)
+
+// IsAutoscaled returns true when the kieserver set is scaled by HorizontalPodAutoscalers
+func IsAutoscaled(serverSet api.KieServerSet) bool {
+	return serverSet.Autoscaling != nil && serverSet.Autoscaling.Enabled
+}
+
+// IsScaleDenied returns true when the environment of the KieApp does not allow scaling the component, whose replica
+// constants are selected by the given function, as it does for the console of the trial environments
+func IsScaleDenied(environment api.EnvironmentType, component func(api.ReplicaConstants) api.Replicas) bool {
+	envConstants, hasEnv := constants.EnvironmentConstants[environment]
+	return hasEnv && component(envConstants.Replica).DenyScale
+}
+
+// serverReplicas selects the replica constants of the kieservers
+func serverReplicas(replicas api.ReplicaConstants) api.Replicas {
+	return replicas.Server
+}
+
+// AddAutoscaling adds a HorizontalPodAutoscaler for the DeploymentConfig of each autoscaled kieserver deployment
+func AddAutoscaling(env api.Environment, cr *api.KieApp) api.Environment {
+	for i := range env.Servers {
+		serverSet, _ := GetServerSet(cr, i)
+		if env.Servers[i].Omit || !IsAutoscaled(serverSet) {
+			continue
+		}
+		if IsScaleDenied(cr.Status.Applied.Environment, serverReplicas) {
+			log.Warnf("autoscaling not allowed for the %s environment, kieserver set %s will not be autoscaled", cr.Status.Applied.Environment, serverSet.Name)
+			continue
+		}
+		for _, dc := range env.Servers[i].DeploymentConfigs {
+			env.Servers[i].HorizontalPodAutoscalers = append(env.Servers[i].HorizontalPodAutoscalers, getHorizontalPodAutoscaler(dc.ObjectMeta, serverSet.Autoscaling, cr))
+		}
+	}
+	return env
+}
+
+func getHorizontalPodAutoscaler(workload metav1.ObjectMeta, autoscaling *api.KieServerAutoscaling, cr *api.KieApp) autoscalingv2beta2.HorizontalPodAutoscaler {
+	labels := map[string]string{}
+	for key, value := range workload.Labels {
+		labels[key] = value
+	}
+	hpa := autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workload.Name,
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: oappsv1.SchemeGroupVersion.String(),
+				Kind:       "DeploymentConfig",
+				Name:       workload.Name,
+			},
+			MinReplicas: Pint32(1),
+			MaxReplicas: autoscaling.MaxReplicas,
+		},
+	}
+	if autoscaling.MinReplicas != nil {
+		hpa.Spec.MinReplicas = Pint32(*autoscaling.MinReplicas)
+	}
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, getResourceMetric(corev1.ResourceCPU, *autoscaling.TargetCPUUtilizationPercentage))
+	}
+	if autoscaling.TargetMemoryUtilizationPercentage != nil {
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, getResourceMetric(corev1.ResourceMemory, *autoscaling.TargetMemoryUtilizationPercentage))
+	}
+	for _, metric := range autoscaling.Metrics {
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, *metric.DeepCopy())
+	}
+	if len(hpa.Spec.Metrics) == 0 {
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, getResourceMetric(corev1.ResourceCPU, defaultTargetCPUUtilization))
+	}
+	hpa.SetGroupVersionKind(autoscalingv2beta2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"))
+	return hpa
+}
+
+func getResourceMetric(resource corev1.ResourceName, utilization int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: resource,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: Pint32(utilization),
+			},
+		},
+	}
+}
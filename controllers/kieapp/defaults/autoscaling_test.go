@@ -0,0 +1,122 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getAutoscaledEnvironment(t *testing.T, cr *api.KieApp) api.Environment {
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	return AddAutoscaling(ConsolidateObjects(env, cr), cr)
+}
+
+func TestAddAutoscaling(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{
+					{
+						Name:        "decisions",
+						Deployments: Pint(2),
+						Autoscaling: &api.KieServerAutoscaling{
+							Enabled:                           true,
+							MinReplicas:                       Pint32(2),
+							MaxReplicas:                       10,
+							TargetMemoryUtilizationPercentage: Pint32(75),
+							Metrics: []autoscalingv2beta2.MetricSpec{{
+								Type: autoscalingv2beta2.PodsMetricSourceType,
+								Pods: &autoscalingv2beta2.PodsMetricSource{
+									Metric: autoscalingv2beta2.MetricIdentifier{Name: "kie_server_container_running_total"},
+								},
+							}},
+						},
+					},
+					{Name: "processes"},
+				},
+			},
+		},
+	}
+	env := getAutoscaledEnvironment(t, cr)
+	assert.Len(t, env.Servers, 3)
+	assert.Empty(t, env.Servers[2].HorizontalPodAutoscalers)
+
+	for i, name := range []string{"decisions", "decisions-2"} {
+		assert.Len(t, env.Servers[i].HorizontalPodAutoscalers, 1)
+		hpa := env.Servers[i].HorizontalPodAutoscalers[0]
+		assert.Equal(t, name, hpa.Name)
+		assert.Equal(t, "test-ns", hpa.Namespace)
+		assert.Equal(t, autoscalingv2beta2.CrossVersionObjectReference{APIVersion: "apps.openshift.io/v1", Kind: "DeploymentConfig", Name: name}, hpa.Spec.ScaleTargetRef)
+		assert.Equal(t, Pint32(2), hpa.Spec.MinReplicas)
+		assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+		assert.Len(t, hpa.Spec.Metrics, 2)
+		assert.Equal(t, corev1.ResourceMemory, hpa.Spec.Metrics[0].Resource.Name)
+		assert.Equal(t, Pint32(75), hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+		assert.Equal(t, autoscalingv2beta2.PodsMetricSourceType, hpa.Spec.Metrics[1].Type)
+	}
+
+	cr.Status.Applied.Platform = api.KubernetesPlatform
+	env = ConvertToKubernetes(env, cr)
+	assert.Equal(t, autoscalingv2beta2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "decisions"}, env.Servers[0].HorizontalPodAutoscalers[0].Spec.ScaleTargetRef)
+}
+
+func TestAddAutoscalingDefaultMetric(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{Autoscaling: &api.KieServerAutoscaling{Enabled: true, MaxReplicas: 3}}},
+			},
+		},
+	}
+	env := getAutoscaledEnvironment(t, cr)
+	assert.Len(t, env.Servers[0].HorizontalPodAutoscalers, 1)
+	hpa := env.Servers[0].HorizontalPodAutoscalers[0]
+	assert.Equal(t, Pint32(1), hpa.Spec.MinReplicas)
+	assert.Len(t, hpa.Spec.Metrics, 1)
+	assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, Pint32(defaultTargetCPUUtilization), hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+}
+
+// denyServerScale replaces the constants of the environment with a copy denying to scale the kieservers, until the end
+// of the test
+func denyServerScale(t *testing.T, environment api.EnvironmentType) {
+	original := constants.EnvironmentConstants[environment]
+	envConstants := *original
+	envConstants.Replica.Server.DenyScale = true
+	constants.EnvironmentConstants[environment] = &envConstants
+	t.Cleanup(func() { constants.EnvironmentConstants[environment] = original })
+}
+
+func TestIsScaleDenied(t *testing.T) {
+	consoleReplicas := func(replicas api.ReplicaConstants) api.Replicas { return replicas.Console }
+	assert.True(t, IsScaleDenied(api.RhpamTrial, consoleReplicas))
+	assert.False(t, IsScaleDenied(api.RhpamTrial, serverReplicas))
+	assert.False(t, IsScaleDenied(api.RhpamProduction, consoleReplicas))
+	assert.False(t, IsScaleDenied("unknown", consoleReplicas))
+}
+
+func TestAddAutoscalingScaleDenied(t *testing.T) {
+	denyServerScale(t, api.RhpamTrial)
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{Autoscaling: &api.KieServerAutoscaling{Enabled: true, MaxReplicas: 3}}},
+			},
+		},
+	}
+	env := getAutoscaledEnvironment(t, cr)
+	assert.Empty(t, env.Servers[0].HorizontalPodAutoscalers)
+}
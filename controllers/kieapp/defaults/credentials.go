@@ -0,0 +1,272 @@
+package defaults
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/RHsyseng/operator-utils/pkg/utils/kubernetes"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// credential is a password of the KieApp spec, along with the Secret key that can hold it instead
+type credential struct {
+	// key of the credential in the generated credentials Secret
+	key      string
+	value    *string
+	ref      **corev1.SecretKeySelector
+	generate bool
+	// component whose containers carry the credential, all of them when empty
+	component string
+	// names of the container env vars carrying the credential
+	envVars []string
+}
+
+const (
+	consoleComponent          = "console"
+	dashbuilderComponent      = "dashbuilder"
+	processMigrationComponent = "processMigration"
+)
+
+func serverComponent(serverSetName string) string {
+	return "servers." + serverSetName
+}
+
+// GetCredentialsSecretName returns the name of the Secret the operator creates to hold the KieApp credentials
+func GetCredentialsSecretName(cr *api.KieApp) string {
+	return fmt.Sprintf(constants.CredentialsSecret, cr.Name)
+}
+
+func getCredentials(spec *api.KieAppSpec) []credential {
+	credentials := []credential{
+		{key: "keyStorePassword", value: &spec.CommonConfig.KeyStorePassword, ref: &spec.CommonConfig.KeyStorePasswordSecretKeyRef, generate: true,
+			envVars: []string{"HTTPS_PASSWORD", "KIE_SERVER_ROUTER_TLS_KEYSTORE_PASSWORD"}},
+		{key: "adminPassword", value: &spec.CommonConfig.AdminPassword, ref: &spec.CommonConfig.AdminPasswordSecretKeyRef, generate: true,
+			envVars: []string{"KIE_ADMIN_PWD", "RHPAMCENTR_MAVEN_REPO_PASSWORD", "RHDMCENTR_MAVEN_REPO_PASSWORD"}},
+		{key: "dbPassword", value: &spec.CommonConfig.DBPassword, ref: &spec.CommonConfig.DBPasswordSecretKeyRef, generate: true,
+			envVars: []string{"RHPAM_PASSWORD", "MYSQL_PASSWORD", "POSTGRESQL_PASSWORD", "PIM_DATASOURCE_PASSWORD"}},
+		{key: "amqPassword", value: &spec.CommonConfig.AMQPassword, ref: &spec.CommonConfig.AMQPasswordSecretKeyRef, generate: true,
+			envVars: []string{"AMQ_PASSWORD"}},
+		{key: "amqClusterPassword", value: &spec.CommonConfig.AMQClusterPassword, ref: &spec.CommonConfig.AMQClusterPasswordSecretKeyRef, generate: true,
+			envVars: []string{"AMQ_CLUSTER_PASSWORD", "APPFORMER_JMS_BROKER_PASSWORD"}},
+	}
+	if spec.Auth != nil && spec.Auth.LDAP != nil {
+		credentials = append(credentials, credential{key: "ldap.bindCredential", value: &spec.Auth.LDAP.BindCredential, ref: &spec.Auth.LDAP.BindCredentialSecretKeyRef,
+			envVars: []string{"AUTH_LDAP_BIND_CREDENTIAL"}})
+	}
+	if spec.Objects.Console != nil && spec.Objects.Console.SSOClient != nil {
+		client := spec.Objects.Console.SSOClient
+		credentials = append(credentials, credential{key: "console.ssoClient.secret", value: &client.Secret, ref: &client.SecretKeyRef,
+			component: consoleComponent, envVars: []string{"SSO_SECRET"}})
+	}
+	if dashbuilder := spec.Objects.Dashbuilder; dashbuilder != nil {
+		if dashbuilder.SSOClient != nil {
+			credentials = append(credentials, credential{key: "dashbuilder.ssoClient.secret", value: &dashbuilder.SSOClient.Secret, ref: &dashbuilder.SSOClient.SecretKeyRef,
+				component: dashbuilderComponent, envVars: []string{"SSO_SECRET"}})
+		}
+		if dashbuilder.Config != nil {
+			for i := range dashbuilder.Config.KieServerDataSets {
+				dataSet := &dashbuilder.Config.KieServerDataSets[i]
+				credentials = append(credentials, credential{key: fmt.Sprintf("dashbuilder.kieServerDataSets.%d.password", i), value: &dataSet.Password, ref: &dataSet.PasswordSecretKeyRef,
+					component: dashbuilderComponent, envVars: []string{getDataSetPasswordEnv(dataSet.Name)}})
+			}
+			for i := range dashbuilder.Config.KieServerTemplates {
+				template := &dashbuilder.Config.KieServerTemplates[i]
+				credentials = append(credentials, credential{key: fmt.Sprintf("dashbuilder.kieServerTemplates.%d.password", i), value: &template.Password, ref: &template.PasswordSecretKeyRef,
+					component: dashbuilderComponent, envVars: []string{getDataSetPasswordEnv(template.Name)}})
+			}
+		}
+	}
+	for i := range spec.Objects.Servers {
+		server := &spec.Objects.Servers[i]
+		if server.SSOClient != nil {
+			credentials = append(credentials, credential{key: fmt.Sprintf("servers.%s.ssoClient.secret", server.Name), value: &server.SSOClient.Secret, ref: &server.SSOClient.SecretKeyRef,
+				component: serverComponent(server.Name), envVars: []string{"SSO_SECRET"}})
+		}
+		if server.Database != nil && server.Database.ExternalConfig != nil {
+			database := &server.Database.ExternalConfig.CommonExternalDatabaseObject
+			credentials = append(credentials, credential{key: fmt.Sprintf("servers.%s.database.password", server.Name), value: &database.Password, ref: &database.PasswordSecretKeyRef,
+				component: serverComponent(server.Name), envVars: []string{"RHPAM_PASSWORD"}})
+		}
+	}
+	if spec.Objects.ProcessMigration != nil && spec.Objects.ProcessMigration.Database.ExternalConfig != nil {
+		database := &spec.Objects.ProcessMigration.Database.ExternalConfig.CommonExternalDatabaseObject
+		credentials = append(credentials, credential{key: "processMigration.database.password", value: &database.Password, ref: &database.PasswordSecretKeyRef,
+			component: processMigrationComponent, envVars: []string{"PIM_DATASOURCE_PASSWORD"}})
+	}
+	return credentials
+}
+
+// getDataSetPasswordEnv returns the name of the env var holding the password of a dashbuilder dataset or server template
+func getDataSetPasswordEnv(name string) string {
+	return fmt.Sprintf("%s_PASSWORD", strings.Replace(name, "-", "_", -1))
+}
+
+// resolveCredentials sets the value of every credential of the applied spec. Referenced credentials are loaded from
+// their Secret, while the ones neither referenced nor set inline are read from the credentials Secret. Passwords
+// generated by SetDefaults are only kept when the credentials Secret does not hold them yet.
+func resolveCredentials(cr *api.KieApp, service kubernetes.PlatformService) error {
+	inline := map[string]bool{}
+	for _, credential := range getCredentials(&cr.Spec) {
+		inline[credential.key] = len(*credential.value) > 0
+	}
+	secrets := map[string]*corev1.Secret{}
+	getSecret := func(name string) (*corev1.Secret, error) {
+		if secret, found := secrets[name]; found {
+			return secret, nil
+		}
+		secret := &corev1.Secret{}
+		if err := service.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, secret); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			log.Debugf("Secret %s/%s not found", cr.Namespace, name)
+		}
+		secrets[name] = secret
+		return secret, nil
+	}
+	for _, credential := range getCredentials(&cr.Status.Applied) {
+		if ref := *credential.ref; ref != nil {
+			secret, err := getSecret(ref.Name)
+			if err != nil {
+				return err
+			}
+			if value, ok := secret.Data[ref.Key]; ok {
+				*credential.value = string(value)
+			} else if ref.Optional == nil || !*ref.Optional {
+				return fmt.Errorf("key %s not found in Secret %s/%s", ref.Key, cr.Namespace, ref.Name)
+			}
+			continue
+		}
+		if len(*credential.value) > 0 && (!credential.generate || inline[credential.key]) {
+			continue
+		}
+		secret, err := getSecret(GetCredentialsSecretName(cr))
+		if err != nil {
+			return err
+		}
+		if value, ok := secret.Data[credential.key]; ok {
+			*credential.value = string(value)
+		}
+	}
+	return nil
+}
+
+// getCredentialsSecret returns the Secret holding the credentials that are set inline or generated by the operator
+func getCredentialsSecret(cr *api.KieApp) *corev1.Secret {
+	data := map[string][]byte{}
+	for _, credential := range getCredentials(&cr.Status.Applied) {
+		if *credential.ref == nil && len(*credential.value) > 0 {
+			data[credential.key] = []byte(*credential.value)
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	secret := &corev1.Secret{
+		Type: corev1.SecretTypeOpaque,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetCredentialsSecretName(cr),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":         cr.Status.Applied.CommonConfig.ApplicationName,
+				"application": cr.Status.Applied.CommonConfig.ApplicationName,
+			},
+		},
+		Data: data,
+	}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return secret
+}
+
+// ClearCredentials removes the credentials from the applied spec, so the KieApp status is never persisted with them,
+// even when the reconciliation fails before ReferenceCredentials
+func ClearCredentials(cr *api.KieApp) {
+	for _, credential := range getCredentials(&cr.Status.Applied) {
+		*credential.value = ""
+	}
+}
+
+// credentialEnv is the value a credential env var is rendered with, along with the Secret key holding it
+type credentialEnv struct {
+	value string
+	ref   *corev1.SecretKeySelector
+}
+
+// ReferenceCredentials replaces the env vars carrying credentials in the container environments with references to
+// the Secrets holding them, and removes the credentials from the applied spec so they are not persisted in the KieApp
+// status. Env vars are matched by name within the component carrying the credential, and are left untouched when their
+// value was overridden.
+func ReferenceCredentials(env api.Environment, cr *api.KieApp) api.Environment {
+	envs := map[string]map[string]credentialEnv{}
+	for _, credential := range getCredentials(&cr.Status.Applied) {
+		if len(*credential.value) == 0 {
+			continue
+		}
+		ref := *credential.ref
+		if ref == nil {
+			ref = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: GetCredentialsSecretName(cr)},
+				Key:                  credential.key,
+			}
+		}
+		if envs[credential.component] == nil {
+			envs[credential.component] = map[string]credentialEnv{}
+		}
+		for _, name := range credential.envVars {
+			envs[credential.component][name] = credentialEnv{value: *credential.value, ref: ref}
+		}
+		*credential.value = ""
+	}
+	referenceEnv := func(containers []corev1.Container, componentEnvs map[string]credentialEnv) {
+		for i := range containers {
+			for j := range containers[i].Env {
+				envVar := &containers[i].Env[j]
+				if credentialEnv, found := componentEnvs[envVar.Name]; found && envVar.ValueFrom == nil && envVar.Value == credentialEnv.value {
+					envVar.Value = ""
+					envVar.ValueFrom = &corev1.EnvVarSource{SecretKeyRef: credentialEnv.ref.DeepCopy()}
+				}
+			}
+		}
+	}
+	reference := func(object *api.CustomObject, component string) {
+		// the credentials of the component take precedence over the ones shared by all the components
+		componentEnvs := map[string]credentialEnv{}
+		for name, credentialEnv := range envs[""] {
+			componentEnvs[name] = credentialEnv
+		}
+		for name, credentialEnv := range envs[component] {
+			componentEnvs[name] = credentialEnv
+		}
+		for i := range object.DeploymentConfigs {
+			if template := object.DeploymentConfigs[i].Spec.Template; template != nil {
+				referenceEnv(template.Spec.InitContainers, componentEnvs)
+				referenceEnv(template.Spec.Containers, componentEnvs)
+			}
+		}
+		for i := range object.StatefulSets {
+			referenceEnv(object.StatefulSets[i].Spec.Template.Spec.InitContainers, componentEnvs)
+			referenceEnv(object.StatefulSets[i].Spec.Template.Spec.Containers, componentEnvs)
+		}
+	}
+	reference(&env.Console, consoleComponent)
+	reference(&env.Dashbuilder, dashbuilderComponent)
+	reference(&env.SmartRouter, "")
+	reference(&env.ProcessMigration, processMigrationComponent)
+	for i := range env.Servers {
+		serverSet, _ := GetServerSet(cr, i)
+		reference(&env.Servers[i], serverComponent(serverSet.Name))
+	}
+	for i := range env.Databases {
+		reference(&env.Databases[i], "")
+	}
+	for i := range env.Others {
+		reference(&env.Others[i], "")
+	}
+	return env
+}
@@ -0,0 +1,183 @@
+package defaults
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveCredentialsFromSecret(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			CommonConfig: api.CommonConfig{
+				AdminPasswordSecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "admin"},
+					Key:                  "password",
+				},
+			},
+		},
+	}
+	service := test.MockService()
+	err := service.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "test-ns"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	})
+	assert.Nil(t, err)
+
+	env, err := GetEnvironment(cr, service)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", cr.Status.Applied.CommonConfig.AdminPassword)
+	assert.Equal(t, constants.DefaultPassword, cr.Status.Applied.CommonConfig.KeyStorePassword)
+
+	secret := env.Others[0].Secrets[len(env.Others[0].Secrets)-1]
+	assert.Equal(t, "test-credentials", secret.Name)
+	assert.Equal(t, []byte(constants.DefaultPassword), secret.Data["keyStorePassword"])
+	assert.NotContains(t, secret.Data, "adminPassword")
+
+	cr.Spec.CommonConfig.AdminPasswordSecretKeyRef.Key = "missing"
+	_, err = GetEnvironment(cr, service)
+	assert.EqualError(t, err, "key missing not found in Secret test-ns/admin")
+}
+
+func TestResolveCredentialsReusesStoredPasswords(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+		},
+	}
+	service := test.MockService()
+	err := service.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-credentials", Namespace: "test-ns"},
+		Data:       map[string][]byte{"adminPassword": []byte("stored")},
+	})
+	assert.Nil(t, err)
+
+	_, err = GetEnvironment(cr, service)
+	assert.Nil(t, err)
+	assert.Equal(t, "stored", cr.Status.Applied.CommonConfig.AdminPassword)
+	assert.NotEmpty(t, cr.Status.Applied.CommonConfig.DBPassword)
+	assert.NotEqual(t, constants.DefaultPassword, cr.Status.Applied.CommonConfig.DBPassword)
+}
+
+func TestReferenceCredentials(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			CommonConfig: api.CommonConfig{
+				AdminPassword: "inline",
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+
+	env = ReferenceCredentials(env, cr)
+	assert.Empty(t, cr.Status.Applied.CommonConfig.AdminPassword)
+	assert.Empty(t, cr.Status.Applied.CommonConfig.KeyStorePassword)
+	assert.Empty(t, cr.Status.Applied.CommonConfig.DBPassword)
+
+	var referenced bool
+	for _, envVar := range env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, "inline", envVar.Value, envVar.Name)
+		if envVar.Name == "KIE_ADMIN_PWD" {
+			assert.Equal(t, &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "test-credentials"},
+				Key:                  "adminPassword",
+			}, envVar.ValueFrom.SecretKeyRef)
+			referenced = true
+		}
+	}
+	assert.True(t, referenced)
+}
+
+func TestReferenceCredentialsByComponent(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			CommonConfig: api.CommonConfig{
+				DBPassword: "shared",
+			},
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{
+					KieAppObject: api.KieAppObject{
+						Env: []corev1.EnvVar{{Name: "KIE_ADMIN_PWD", Value: "overridden"}},
+					},
+				},
+				Servers: []api.KieServerSet{
+					{
+						Name: "external",
+						Database: &api.DatabaseObject{
+							InternalDatabaseObject: api.InternalDatabaseObject{Type: api.DatabaseExternal},
+							ExternalConfig: &api.ExternalDatabaseObject{
+								Dialect: "org.hibernate.dialect.MySQL8Dialect",
+								CommonExtDBObjectURL: api.CommonExtDBObjectURL{
+									JdbcURL: "jdbc:mysql://mydb:3306/rhpam",
+									CommonExternalDatabaseObject: api.CommonExternalDatabaseObject{
+										Driver:   "mysql",
+										Username: "rhpam",
+										Password: "shared",
+									},
+								},
+							},
+						},
+					},
+				},
+				ProcessMigration: &api.ProcessMigrationObject{
+					Database: api.ProcessMigrationDatabaseObject{
+						InternalDatabaseObject: api.InternalDatabaseObject{Type: api.DatabaseMySQL},
+					},
+				},
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	env = ReferenceCredentials(ConsolidateObjects(env, cr), cr)
+
+	getEnv := func(containers []corev1.Container, name string) corev1.EnvVar {
+		for _, envVar := range containers[0].Env {
+			if envVar.Name == name {
+				return envVar
+			}
+		}
+		t.Fatalf("env var %s not found", name)
+		return corev1.EnvVar{}
+	}
+	console := env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers
+	assert.Equal(t, corev1.EnvVar{Name: "KIE_ADMIN_PWD", Value: "overridden"}, getEnv(console, "KIE_ADMIN_PWD"))
+
+	server := getEnv(env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers, "RHPAM_PASSWORD")
+	assert.Empty(t, server.Value)
+	assert.Equal(t, "servers.external.database.password", server.ValueFrom.SecretKeyRef.Key)
+
+	processMigration := env.ProcessMigration.DeploymentConfigs[0].Spec.Template.Spec.Containers
+	assert.Equal(t, "adminPassword", getEnv(processMigration, "KIE_ADMIN_PWD").ValueFrom.SecretKeyRef.Key)
+	assert.Equal(t, "dbPassword", getEnv(processMigration, "PIM_DATASOURCE_PASSWORD").ValueFrom.SecretKeyRef.Key)
+	applicationConfig := env.ProcessMigration.ConfigMaps[0].Data["application.yaml"]
+	assert.Contains(t, applicationConfig, "password: ${KIE_ADMIN_PWD}")
+	assert.Contains(t, applicationConfig, "password: ${PIM_DATASOURCE_PASSWORD}")
+	assert.NotContains(t, applicationConfig, "shared")
+}
@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/RHsyseng/operator-utils/pkg/logs"
 	"github.com/RHsyseng/operator-utils/pkg/utils/kubernetes"
@@ -27,16 +28,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
-var (
-	log          = logs.GetLogger("kieapp.defaults")
-	caOptsAppend = []string{
-		"-Djavax.net.ssl.trustStoreType=jks",
-		"-Djavax.net.ssl.trustStore=" + constants.TruststorePath + "/" + constants.TruststoreName,
-		"-Djavax.net.ssl.trustStorePassword=" + constants.TruststorePwd,
-	}
-)
+var log = logs.GetLogger("kieapp.defaults")
 
 // GetEnvironment returns an Environment from merging the common config and the config
 // related to the environment set in the KieApp definition
@@ -60,7 +55,7 @@ func GetEnvironment(cr *api.KieApp, service kubernetes.PlatformService) (api.Env
 		cr.Status.Applied.Version = constants.CurrentVersion
 		cr.Spec.Version = ""
 	}
-	envTemplate, err := getEnvTemplate(cr)
+	envTemplate, err := getEnvTemplate(cr, service)
 	if err != nil {
 		return api.Environment{}, err
 	}
@@ -150,8 +145,14 @@ func GetEnvironment(cr *api.KieApp, service kubernetes.PlatformService) (api.Env
 	if err != nil {
 		return api.Environment{}, err
 	}
+	if err := CheckExposedHostnames(mergedEnv, cr); err != nil {
+		return api.Environment{}, err
+	}
 	overrideKafkaTopicsEnv(cr, &mergedEnv)
 	setProductLabels(cr, &mergedEnv)
+	if secret := getCredentialsSecret(cr); secret != nil {
+		mergedEnv.Others[0].Secrets = append(mergedEnv.Others[0].Secrets, *secret)
+	}
 	return mergedEnv, nil
 }
 
@@ -303,8 +304,11 @@ func findCustomObjectByName(template api.CustomObject, objects []api.CustomObjec
 	return api.CustomObject{}, false
 }
 
-func getEnvTemplate(cr *api.KieApp) (envTemplate api.EnvTemplate, err error) {
+func getEnvTemplate(cr *api.KieApp, service kubernetes.PlatformService) (envTemplate api.EnvTemplate, err error) {
 	SetDefaults(cr)
+	if err = resolveCredentials(cr, service); err != nil {
+		return envTemplate, err
+	}
 	serversConfig, err := getServersConfig(cr)
 	if err != nil {
 		return envTemplate, err
@@ -315,9 +319,11 @@ func getEnvTemplate(cr *api.KieApp) (envTemplate api.EnvTemplate, err error) {
 		SmartRouter: getSmartRouterTemplate(cr),
 		Constants:   *getTemplateConstants(cr),
 	}
-	if IsOcpCA(cr) {
-		envTemplate.OpenshiftCaBundle = cr.Status.Applied.Truststore.OpenshiftCaBundle
-	}
+	keystoreFormat := GetKeystoreOptions(cr).Format
+	envTemplate.KeystoreName = shared.KeystoreName(keystoreFormat)
+	envTemplate.KeystoreType = shared.KeystoreType(keystoreFormat)
+	envTemplate.OpenshiftCaBundle = IsOcpCA(cr)
+	envTemplate.Truststore = HasTruststore(cr)
 
 	dashbuilderTemplate, err := getDashbuilderTemplate(cr, serversConfig, &envTemplate.Console)
 	if err != nil {
@@ -723,11 +729,11 @@ func serverSortBlanks(serverSets []api.KieServerSet) []api.KieServerSet {
 }
 
 func setCAJavaAppend(cr *api.KieApp, jvm *api.JvmObject) *api.JvmObject {
-	if IsOcpCA(cr) {
+	if HasTruststore(cr) {
 		if jvm == nil {
 			jvm = &api.JvmObject{}
 		}
-		for _, caOption := range caOptsAppend {
+		for _, caOption := range getCAJavaOpts(cr) {
 			if !strings.Contains(jvm.JavaOptsAppend, caOption) {
 				jvm.JavaOptsAppend = strings.Join([]string{jvm.JavaOptsAppend, caOption}, " ")
 			}
@@ -737,6 +743,83 @@ func setCAJavaAppend(cr *api.KieApp, jvm *api.JvmObject) *api.JvmObject {
 	return jvm
 }
 
+// getCAJavaOpts returns the JVM options setting the truststore generated from the CA bundles as the default one
+func getCAJavaOpts(cr *api.KieApp) []string {
+	format := GetKeystoreOptions(cr).Format
+	return []string{
+		"-Djavax.net.ssl.trustStoreType=" + format,
+		"-Djavax.net.ssl.trustStore=" + constants.TruststorePath + "/" + shared.TruststoreName(format),
+		"-Djavax.net.ssl.trustStorePassword=" + constants.TruststorePwd,
+	}
+}
+
+// RemoveTruststore removes the truststore volume and the JVM options setting it from the pods of the environment, when
+// none of the CA bundles it is generated from is available yet, e.g. until Openshift injects its own or cert-manager
+// issues the certificates. The pods would otherwise wait for the missing secret, or trust no CA at all.
+func RemoveTruststore(env api.Environment, cr *api.KieApp) api.Environment {
+	volumeName := cr.Status.Applied.CommonConfig.ApplicationName + constants.TruststoreSecret
+	caOptions := sets.NewString(getCAJavaOpts(cr)...)
+	objects := []*api.CustomObject{&env.Console, &env.Dashbuilder, &env.SmartRouter, &env.ProcessMigration}
+	for i := range env.Servers {
+		objects = append(objects, &env.Servers[i])
+	}
+	for _, object := range objects {
+		var templates []*corev1.PodTemplateSpec
+		for i := range object.DeploymentConfigs {
+			templates = append(templates, object.DeploymentConfigs[i].Spec.Template)
+		}
+		for i := range object.Deployments {
+			templates = append(templates, &object.Deployments[i].Spec.Template)
+		}
+		for i := range object.StatefulSets {
+			templates = append(templates, &object.StatefulSets[i].Spec.Template)
+		}
+		for _, template := range templates {
+			if template != nil {
+				removePodTruststore(&template.Spec, volumeName, caOptions)
+			}
+		}
+	}
+	return env
+}
+
+func removePodTruststore(pod *corev1.PodSpec, volumeName string, caOptions sets.String) {
+	var volumes []corev1.Volume
+	for _, volume := range pod.Volumes {
+		if volume.Name != volumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	pod.Volumes = volumes
+	for i := range pod.Containers {
+		container := &pod.Containers[i]
+		var volumeMounts []corev1.VolumeMount
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name != volumeName {
+				volumeMounts = append(volumeMounts, volumeMount)
+			}
+		}
+		container.VolumeMounts = volumeMounts
+		var env []corev1.EnvVar
+		for _, envVar := range container.Env {
+			if envVar.Name == "JAVA_OPTS_APPEND" {
+				var options []string
+				for _, option := range strings.Fields(envVar.Value) {
+					if !caOptions.Has(option) {
+						options = append(options, option)
+					}
+				}
+				if len(options) == 0 {
+					continue
+				}
+				envVar.Value = strings.Join(options, " ")
+			}
+			env = append(env, envVar)
+		}
+		container.Env = env
+	}
+}
+
 // Returns the templates to use depending on whether the spec was defined with a common configuration
 // or a specific one.
 func getServersConfig(cr *api.KieApp) ([]api.ServerTemplate, error) {
@@ -945,7 +1028,7 @@ func ConsolidateObjects(env api.Environment, cr *api.KieApp) api.Environment {
 				serverSet.Build.Env)
 		}
 	}
-	return env
+	return setProbes(env, cr)
 }
 
 // ConstructObject returns an object after merging the environment object and the one defined in the CR
@@ -961,11 +1044,172 @@ func ConstructObject(object api.CustomObject, appObject api.KieAppObject) api.Cu
 			}
 			dc.Spec.Template.Spec.Containers[containerIndex] = c
 		}
+		setPodScheduling(dc.Spec.Template, dc.Spec.Selector, appObject)
+		setPodExtensions(dc.Spec.Template, appObject)
 		object.DeploymentConfigs[dcIndex] = dc
 	}
+	for index := range object.StatefulSets {
+		statefulSet := &object.StatefulSets[index]
+		var selector map[string]string
+		if statefulSet.Spec.Selector != nil {
+			selector = statefulSet.Spec.Selector.MatchLabels
+		}
+		setPodScheduling(&statefulSet.Spec.Template, selector, appObject)
+		setPodExtensions(&statefulSet.Spec.Template, appObject)
+	}
 	return object
 }
 
+// setPodScheduling applies the scheduling constraints, priority, labels and annotations defined in the CR to the pod template
+func setPodScheduling(template *corev1.PodTemplateSpec, selector map[string]string, appObject api.KieAppObject) {
+	if template == nil {
+		return
+	}
+	if len(appObject.NodeSelector) > 0 {
+		if template.Spec.NodeSelector == nil {
+			template.Spec.NodeSelector = map[string]string{}
+		}
+		for key, value := range appObject.NodeSelector {
+			template.Spec.NodeSelector[key] = value
+		}
+	}
+	template.Spec.Tolerations = mergeTolerations(template.Spec.Tolerations, appObject.Tolerations)
+	if appObject.Affinity != nil {
+		template.Spec.Affinity = appObject.Affinity.DeepCopy()
+	}
+	template.Spec.TopologySpreadConstraints = mergeTopologySpreadConstraints(template.Spec.TopologySpreadConstraints, appObject.TopologySpreadConstraints)
+	if appObject.PriorityClassName != "" {
+		template.Spec.PriorityClassName = appObject.PriorityClassName
+	}
+	for key, value := range appObject.PodLabels {
+		if _, selected := selector[key]; selected {
+			log.Warnf("Pod label %s is used by the selector and will not be overridden", key)
+			continue
+		}
+		if template.Labels == nil {
+			template.Labels = map[string]string{}
+		}
+		template.Labels[key] = value
+	}
+	if len(appObject.PodAnnotations) > 0 {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		for key, value := range appObject.PodAnnotations {
+			template.Annotations[key] = value
+		}
+	}
+}
+
+// setPodExtensions adds the sidecars, init containers, volumes and volume mounts defined in the CR to the pod template.
+// They are set with the defaults of the API server, so the deployed template does not differ from the requested one.
+func setPodExtensions(template *corev1.PodTemplateSpec, appObject api.KieAppObject) {
+	if template == nil {
+		return
+	}
+	if len(appObject.VolumeMounts) > 0 {
+		for index := range template.Spec.Containers {
+			container := &template.Spec.Containers[index]
+			mounts, err := mergeVolumeMounts(container.VolumeMounts, deepCopyVolumeMounts(appObject.VolumeMounts))
+			if err != nil {
+				log.Error("Error merging volume mounts. ", err)
+				continue
+			}
+			container.VolumeMounts = mounts
+		}
+	}
+	template.Spec.Containers = addContainers(template.Spec.Containers, appObject.Sidecars)
+	template.Spec.InitContainers = addContainers(template.Spec.InitContainers, appObject.InitContainers)
+	if len(appObject.Volumes) > 0 {
+		volumes := make([]corev1.Volume, len(appObject.Volumes))
+		for index := range appObject.Volumes {
+			volumes[index] = *appObject.Volumes[index].DeepCopy()
+			setVolumeDefaults(&volumes[index])
+		}
+		merged, err := mergeVolumes(template.Spec.Volumes, volumes)
+		if err != nil {
+			log.Error("Error merging volumes. ", err)
+			return
+		}
+		template.Spec.Volumes = merged
+	}
+}
+
+// addContainers returns the containers with the added ones replacing those of the same name, or else appended
+func addContainers(containers []corev1.Container, added []corev1.Container) []corev1.Container {
+	for index := range added {
+		container := *added[index].DeepCopy()
+		setContainerDefaults(&container)
+		replaced := false
+		for existing := range containers {
+			if containers[existing].Name == container.Name {
+				containers[existing] = container
+				replaced = true
+			}
+		}
+		if !replaced {
+			containers = append(containers, container)
+		}
+	}
+	return containers
+}
+
+func deepCopyVolumeMounts(mounts []corev1.VolumeMount) []corev1.VolumeMount {
+	copied := make([]corev1.VolumeMount, len(mounts))
+	for index := range mounts {
+		copied[index] = *mounts[index].DeepCopy()
+	}
+	return copied
+}
+
+func setContainerDefaults(container *corev1.Container) {
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = corev1.PullIfNotPresent
+		if image := container.Image[strings.LastIndex(container.Image, "/")+1:]; !strings.Contains(image, "@") &&
+			(!strings.Contains(image, ":") || strings.HasSuffix(image, ":latest")) {
+			container.ImagePullPolicy = corev1.PullAlways
+		}
+	}
+	if container.TerminationMessagePath == "" {
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+	for index := range container.Ports {
+		if container.Ports[index].Protocol == "" {
+			container.Ports[index].Protocol = corev1.ProtocolTCP
+		}
+	}
+	for index := range container.Env {
+		if valueFrom := container.Env[index].ValueFrom; valueFrom != nil && valueFrom.FieldRef != nil && valueFrom.FieldRef.APIVersion == "" {
+			valueFrom.FieldRef.APIVersion = "v1"
+		}
+	}
+}
+
+func setVolumeDefaults(volume *corev1.Volume) {
+	source := &volume.VolumeSource
+	defaultMode := corev1.ConfigMapVolumeSourceDefaultMode
+	switch {
+	case source.ConfigMap != nil && source.ConfigMap.DefaultMode == nil:
+		source.ConfigMap.DefaultMode = &defaultMode
+	case source.Secret != nil && source.Secret.DefaultMode == nil:
+		source.Secret.DefaultMode = &defaultMode
+	case source.Projected != nil && source.Projected.DefaultMode == nil:
+		source.Projected.DefaultMode = &defaultMode
+	case source.DownwardAPI != nil:
+		if source.DownwardAPI.DefaultMode == nil {
+			source.DownwardAPI.DefaultMode = &defaultMode
+		}
+		for index := range source.DownwardAPI.Items {
+			if fieldRef := source.DownwardAPI.Items[index].FieldRef; fieldRef != nil && fieldRef.APIVersion == "" {
+				fieldRef.APIVersion = "v1"
+			}
+		}
+	}
+}
+
 func getKieDeploymentName(applicationName string, setName string, arrayIdx, deploymentsIdx int) string {
 	name := setName
 	if name == "" {
@@ -1341,6 +1585,11 @@ func Pint32(i int32) *int32 {
 	return &i
 }
 
+// Pint64 returns a pointer to an integer
+func Pint64(i int64) *int64 {
+	return &i
+}
+
 // Pbool returns a pointer to a boolean
 func Pbool(b bool) *bool {
 	return &b
@@ -1392,6 +1641,9 @@ func SetDefaults(cr *api.KieApp) {
 			specApply.Version = cr.Status.Applied.Version
 		}
 	}
+	if len(specApply.Platform) == 0 {
+		specApply.Platform = cr.Status.Applied.Platform
+	}
 	if err := mergo.Merge(&specApply.CommonConfig, cr.Status.Applied.CommonConfig); err != nil {
 		log.Error(err)
 	}
@@ -1401,13 +1653,8 @@ func SetDefaults(cr *api.KieApp) {
 	if len(specApply.CommonConfig.AdminUser) == 0 {
 		specApply.CommonConfig.AdminUser = constants.DefaultAdminUser
 	}
-	if specApply.CommonConfig.StartupStrategy == nil {
-		specApply.CommonConfig.StartupStrategy = &api.StartupStrategy{StrategyName: api.OpenshiftStartupStrategy, ControllerTemplateCacheTTL: Pint(5000)}
-	}
-	if len(specApply.Objects.Servers) == 0 {
-		specApply.Objects.Servers = []api.KieServerSet{{Deployments: Pint(constants.DefaultKieDeployments)}}
-	}
-	setKieSetNames(specApply)
+	setStartupStrategy(specApply, specApply.Platform)
+	setServerSetDefaults(specApply)
 
 	for index := range specApply.Objects.Servers {
 		addWebhookTypes(specApply.Objects.Servers[index].Build)
@@ -1415,18 +1662,10 @@ func SetDefaults(cr *api.KieApp) {
 			retainAppliedPwds(&specApply.Objects.Servers[index], statusServer)
 		}
 		addWebhookPwds(specApply.Objects.Servers[index].Build)
-		checkJvmOnServer(&specApply.Objects.Servers[index])
-		setResourcesDefault(&specApply.Objects.Servers[index].KieAppObject, constants.ServersLimits, constants.ServerRequests)
 	}
+	setObjectDefaults(specApply)
 
 	if specApply.Objects.Console != nil {
-		checkJvmOnConsole(specApply.Objects.Console)
-		if strings.Contains(string(specApply.Environment), "authoring") {
-			setResourcesDefault(&specApply.Objects.Console.KieAppObject, constants.ConsoleAuthoringLimits, constants.ConsoleAuthoringRequests)
-		} else if strings.Contains(string(specApply.Environment), "production") {
-			setResourcesDefault(&specApply.Objects.Console.KieAppObject, constants.ConsoleProdLimits, constants.ConsoleProdRequests)
-		}
-
 		if cr.Spec.Environment == api.RhpamAuthoringHA || cr.Spec.Environment == api.RhdmAuthoringHA {
 			if specApply.Objects.Console.DataGridAuth != nil {
 				if len(specApply.Objects.Console.DataGridAuth.Username) == 0 {
@@ -1442,25 +1681,84 @@ func SetDefaults(cr *api.KieApp) {
 		}
 	}
 
-	if specApply.Objects.Dashbuilder != nil {
-		checkJvmOnDashbuilder(specApply.Objects.Dashbuilder)
-		setResourcesDefault(&specApply.Objects.Dashbuilder.KieAppObject, constants.DashbuilderLimits, constants.DashbuilderRequests)
+	isTrialEnv := strings.HasSuffix(string(specApply.Environment), constants.TrialEnvSuffix)
+	setPasswords(specApply, isTrialEnv)
+
+	cr.Status.Applied = *specApply
+}
+
+// SetSpecDefaults writes the defaults that hold no credentials into the KieApp spec, so the effective configuration
+// is visible to users. Defaults depending on the platform use the given one when the spec does not request any.
+// Once written, the defaults are part of the spec, so they are not updated when later operator versions change them.
+func SetSpecDefaults(cr *api.KieApp, platform api.PlatformType) {
+	spec := &cr.Spec
+	if len(spec.Platform) > 0 {
+		platform = spec.Platform
+	}
+	setStartupStrategy(spec, platform)
+	// server set names are derived from the application name, which defaults to the KieApp name
+	applicationName := spec.CommonConfig.ApplicationName
+	if len(applicationName) == 0 {
+		spec.CommonConfig.ApplicationName = cr.Name
 	}
+	// KieApps created with generateName have no name yet at admission time, their server sets are defaulted when
+	// reconciled and written into the spec on the next update
+	if len(spec.CommonConfig.ApplicationName) > 0 {
+		setServerSetDefaults(spec)
+	}
+	spec.CommonConfig.ApplicationName = applicationName
+	setObjectDefaults(spec)
+}
 
-	if specApply.Objects.SmartRouter != nil {
-		checkJvmOnSmartRouter(specApply.Objects.SmartRouter)
-		setResourcesDefault(&specApply.Objects.SmartRouter.KieAppObject, constants.SmartRouterLimits, constants.SmartRouterRequests)
+func setStartupStrategy(spec *api.KieAppSpec, platform api.PlatformType) {
+	if spec.CommonConfig.StartupStrategy != nil {
+		return
+	}
+	if platform == api.KubernetesPlatform {
+		// the OpenShift startup strategy relies on the DeploymentConfig API
+		spec.CommonConfig.StartupStrategy = &api.StartupStrategy{StrategyName: api.ControllerStartupStrategy}
+	} else {
+		spec.CommonConfig.StartupStrategy = &api.StartupStrategy{StrategyName: api.OpenshiftStartupStrategy, ControllerTemplateCacheTTL: Pint(5000)}
 	}
+}
 
-	if specApply.Objects.ProcessMigration != nil {
-		checkJvmOnProcessMigration(specApply.Objects.ProcessMigration)
-		setResourcesDefault(&specApply.Objects.ProcessMigration.KieAppObject, constants.ProcessMigrationLimits, constants.ProcessMigrationRequests)
+func setServerSetDefaults(spec *api.KieAppSpec) {
+	if len(spec.Objects.Servers) == 0 {
+		spec.Objects.Servers = []api.KieServerSet{{Deployments: Pint(constants.DefaultKieDeployments)}}
 	}
+	setKieSetNames(spec)
+}
 
-	isTrialEnv := strings.HasSuffix(string(specApply.Environment), constants.TrialEnvSuffix)
-	setPasswords(specApply, isTrialEnv)
+// setObjectDefaults sets the JVM and resources defaults of every component
+func setObjectDefaults(spec *api.KieAppSpec) {
+	for index := range spec.Objects.Servers {
+		checkJvmOnServer(&spec.Objects.Servers[index])
+		setResourcesDefault(&spec.Objects.Servers[index].KieAppObject, constants.ServersLimits, constants.ServerRequests)
+	}
 
-	cr.Status.Applied = *specApply
+	if spec.Objects.Console != nil {
+		checkJvmOnConsole(spec.Objects.Console)
+		if strings.Contains(string(spec.Environment), "authoring") {
+			setResourcesDefault(&spec.Objects.Console.KieAppObject, constants.ConsoleAuthoringLimits, constants.ConsoleAuthoringRequests)
+		} else if strings.Contains(string(spec.Environment), "production") {
+			setResourcesDefault(&spec.Objects.Console.KieAppObject, constants.ConsoleProdLimits, constants.ConsoleProdRequests)
+		}
+	}
+
+	if spec.Objects.Dashbuilder != nil {
+		checkJvmOnDashbuilder(spec.Objects.Dashbuilder)
+		setResourcesDefault(&spec.Objects.Dashbuilder.KieAppObject, constants.DashbuilderLimits, constants.DashbuilderRequests)
+	}
+
+	if spec.Objects.SmartRouter != nil {
+		checkJvmOnSmartRouter(spec.Objects.SmartRouter)
+		setResourcesDefault(&spec.Objects.SmartRouter.KieAppObject, constants.SmartRouterLimits, constants.SmartRouterRequests)
+	}
+
+	if spec.Objects.ProcessMigration != nil {
+		checkJvmOnProcessMigration(spec.Objects.ProcessMigration)
+		setResourcesDefault(&spec.Objects.ProcessMigration.KieAppObject, constants.ProcessMigrationLimits, constants.ProcessMigrationRequests)
+	}
 }
 
 func checkJvmOnConsole(console *api.ConsoleObject) {
@@ -1698,8 +1996,10 @@ func getProcessMigrationTemplate(cr *api.KieApp, serversConfig []api.ServerTempl
 		// route hostname, if invalid it will not be set
 		processMigrationTemplate.RouteHostname = getRouteHostname(cr.Status.Applied.Objects.ProcessMigration)
 
-		// JVM configuration
-		cr.Status.Applied.Objects.ProcessMigration.Jvm = setCAJavaAppend(cr, cr.Status.Applied.Objects.ProcessMigration.Jvm)
+		// JVM configuration, the process migration reaches the kie servers over http and only trusts the CA bundles
+		if hasTruststoreBundles(cr) {
+			cr.Status.Applied.Objects.ProcessMigration.Jvm = setCAJavaAppend(cr, cr.Status.Applied.Objects.ProcessMigration.Jvm)
+		}
 		if cr.Status.Applied.Objects.ProcessMigration.Jvm != nil {
 			processMigrationTemplate.Jvm = *cr.Status.Applied.Objects.ProcessMigration.Jvm.DeepCopy()
 		}
@@ -1896,6 +2196,42 @@ func IsOcpCA(cr *api.KieApp) bool {
 		semver.Compare(semver.MajorMinor("v"+cr.Status.Applied.Version), "v7.11") >= 0
 }
 
+// HasTruststore returns whether the components trust the CA signing their keystores when SSL is enabled, or the CA
+// bundles of Openshift or of the truststore sources, in which case a truststore is generated from them
+func HasTruststore(cr *api.KieApp) bool {
+	return (hasTruststoreBundles(cr) || !cr.Status.Applied.CommonConfig.DisableSsl) &&
+		semver.Compare(semver.MajorMinor("v"+cr.Status.Applied.Version), "v7.11") >= 0
+}
+
+// hasTruststoreBundles returns whether the CA bundles of Openshift or of the truststore sources are trusted
+func hasTruststoreBundles(cr *api.KieApp) bool {
+	return cr.Status.Applied.Truststore != nil &&
+		(cr.Status.Applied.Truststore.OpenshiftCaBundle || len(cr.Status.Applied.Truststore.From) > 0)
+}
+
+// GetCertificateRenewBefore returns how long before their expiry the CA and keystores generated for the KieApp are renewed
+func GetCertificateRenewBefore(cr *api.KieApp) time.Duration {
+	if cr.Status.Applied.TLS != nil && cr.Status.Applied.TLS.RenewBefore != nil {
+		return cr.Status.Applied.TLS.RenewBefore.Duration
+	}
+	return constants.KeystoreRenewBefore * 24 * time.Hour
+}
+
+// GetKeystoreOptions returns the format of the keystores and truststore generated for the KieApp, and the algorithm of
+// their keys
+func GetKeystoreOptions(cr *api.KieApp) shared.KeystoreOptions {
+	opts := shared.KeystoreOptions{Format: constants.JKSKeystoreFormat}
+	if tls := cr.Status.Applied.TLS; tls != nil {
+		if tls.KeystoreFormat != "" {
+			opts.Format = string(tls.KeystoreFormat)
+		}
+		opts.KeyAlgorithm = string(tls.KeyAlgorithm)
+		opts.KeySize = tls.KeySize
+	}
+	opts.KeyAlgorithm, opts.KeySize = opts.GetKeyAlgorithm()
+	return opts
+}
+
 func getDatabaseDeploymentTemplate(cr *api.KieApp, serversConfig []api.ServerTemplate,
 	processMigrationTemplate *api.ProcessMigrationTemplate) []api.DatabaseTemplate {
 	var databaseDeploymentTemplate []api.DatabaseTemplate
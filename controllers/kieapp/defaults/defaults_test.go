@@ -15,6 +15,7 @@ import (
 	appsv1 "github.com/openshift/api/apps/v1"
 	buildv1 "github.com/openshift/api/build/v1"
 	"github.com/stretchr/testify/assert"
+	kappsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -291,6 +292,7 @@ func getDashKeyAndVolume() (corev1.VolumeMount, corev1.Volume) {
 func assertHTTPEmpty(t *testing.T, container corev1.Container) {
 	assert.Empty(t, getEnvVariable(container, "HTTPS_KEYSTORE_DIR"))
 	assert.Empty(t, getEnvVariable(container, "HTTPS_KEYSTORE"))
+	assert.Empty(t, getEnvVariable(container, "HTTPS_KEYSTORE_TYPE"))
 	assert.Empty(t, getEnvVariable(container, "HTTPS_NAME"))
 	assert.Empty(t, getEnvVariable(container, "HTTPS_PASSWORD"))
 }
@@ -298,6 +300,7 @@ func assertHTTPEmpty(t *testing.T, container corev1.Container) {
 func assertHTTPSEnvs(t *testing.T, keystoreVolumeName string, container corev1.Container) {
 	assert.Equal(t, keystoreVolumeName, getEnvVariable(container, "HTTPS_KEYSTORE_DIR"))
 	assert.Equal(t, constants.KeystoreName, getEnvVariable(container, "HTTPS_KEYSTORE"))
+	assert.Equal(t, "JKS", getEnvVariable(container, "HTTPS_KEYSTORE_TYPE"))
 	assert.Equal(t, "jboss", getEnvVariable(container, "HTTPS_NAME"))
 	assert.Empty(t, "", getEnvVariable(container, "HTTPS_PASSWORD"))
 }
@@ -1423,11 +1426,16 @@ func createJvmTestObject() *api.JvmObject {
 	return &jvmObject
 }
 
+// withCAJavaOpts returns the JVM options followed by the ones trusting the CA signing the keystores, as SSL is enabled
+func withCAJavaOpts(javaOpts string) string {
+	return strings.Join(append([]string{javaOpts}, getCAJavaOpts(&api.KieApp{})...), " ")
+}
+
 func testJvmEnv(t *testing.T, envs []corev1.EnvVar) {
 	for _, env := range envs {
 		switch e := env.Name; e {
 		case "JAVA_OPTS_APPEND":
-			assert.Equal(t, "-Dsome.property=foo", env.Value)
+			assert.Equal(t, withCAJavaOpts("-Dsome.property=foo"), env.Value)
 
 		case "JAVA_MAX_MEM_RATIO":
 			assert.Equal(t, "80", env.Value)
@@ -2220,6 +2228,120 @@ func TestConstructConsoleObject(t *testing.T) {
 	}
 }
 
+func TestConstructObjectPodScheduling(t *testing.T) {
+	appObject := api.KieAppObject{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/kie": ""},
+		Tolerations: []corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "kie", Effect: corev1.TaintEffectNoSchedule},
+		},
+		Affinity: &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+				},
+			},
+		},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 1, WhenUnsatisfiable: corev1.DoNotSchedule},
+		},
+		PriorityClassName: "high-priority",
+		PodLabels:         map[string]string{"team": "kie", "deploymentConfig": "other"},
+		PodAnnotations:    map[string]string{"backup.velero.io/backup-volumes": "data"},
+	}
+	object := api.CustomObject{
+		DeploymentConfigs: []appsv1.DeploymentConfig{
+			{
+				Spec: appsv1.DeploymentConfigSpec{
+					Selector: map[string]string{"deploymentConfig": "test"},
+					Template: &corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"deploymentConfig": "test"}},
+						Spec: corev1.PodSpec{
+							NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+							Containers:   []corev1.Container{{Name: "test"}},
+						},
+					},
+				},
+			},
+		},
+		StatefulSets: []kappsv1.StatefulSet{
+			{
+				Spec: kappsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-db"}},
+				},
+			},
+		},
+	}
+	object = ConstructObject(object, appObject)
+
+	dcTemplate := object.DeploymentConfigs[0].Spec.Template
+	statefulSetTemplate := object.StatefulSets[0].Spec.Template
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "node-role.kubernetes.io/kie": ""}, dcTemplate.Spec.NodeSelector)
+	assert.Equal(t, map[string]string{"node-role.kubernetes.io/kie": ""}, statefulSetTemplate.Spec.NodeSelector)
+	for _, template := range []corev1.PodTemplateSpec{*dcTemplate, statefulSetTemplate} {
+		assert.Equal(t, appObject.Tolerations, template.Spec.Tolerations)
+		assert.Equal(t, appObject.Affinity, template.Spec.Affinity)
+		assert.Equal(t, appObject.TopologySpreadConstraints, template.Spec.TopologySpreadConstraints)
+		assert.Equal(t, "high-priority", template.Spec.PriorityClassName)
+		assert.Equal(t, "kie", template.Labels["team"])
+		assert.Equal(t, "data", template.Annotations["backup.velero.io/backup-volumes"])
+	}
+	assert.Equal(t, "test", dcTemplate.Labels["deploymentConfig"], "Selector labels should not be overridden")
+	assert.Equal(t, "other", statefulSetTemplate.Labels["deploymentConfig"], "Labels outside the selector can be set")
+}
+
+func TestConstructObjectPodExtensions(t *testing.T) {
+	appObject := api.KieAppObject{
+		Sidecars: []corev1.Container{
+			{Name: "log-shipper", Image: "quay.io/example/fluent-bit:1.9", Ports: []corev1.ContainerPort{{ContainerPort: 2020}}},
+		},
+		InitContainers: []corev1.Container{
+			{Name: "fetch-jars", Image: "quay.io/example/fetch-jars", VolumeMounts: []corev1.VolumeMount{{Name: "jars", MountPath: "/jars"}}},
+		},
+		Volumes: []corev1.Volume{
+			{Name: "jars", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "custom"}}}},
+		},
+		VolumeMounts: []corev1.VolumeMount{{Name: "jars", MountPath: "/opt/jars", ReadOnly: true}},
+	}
+	object := api.CustomObject{
+		DeploymentConfigs: []appsv1.DeploymentConfig{
+			{
+				Spec: appsv1.DeploymentConfigSpec{
+					Template: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "test", VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}}},
+							Volumes:    []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	object = ConstructObject(object, appObject)
+	// constructed twice, as on each reconciliation, without adding the extensions again
+	object = ConstructObject(object, appObject)
+
+	podSpec := object.DeploymentConfigs[0].Spec.Template.Spec
+	assert.Len(t, podSpec.Containers, 2)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "data", MountPath: "/data"}, {Name: "jars", MountPath: "/opt/jars", ReadOnly: true}}, podSpec.Containers[0].VolumeMounts)
+	sidecar := podSpec.Containers[1]
+	assert.Equal(t, "log-shipper", sidecar.Name)
+	assert.Empty(t, sidecar.VolumeMounts)
+	assert.Equal(t, corev1.PullIfNotPresent, sidecar.ImagePullPolicy)
+	assert.Equal(t, corev1.ProtocolTCP, sidecar.Ports[0].Protocol)
+	assert.Equal(t, corev1.TerminationMessagePathDefault, sidecar.TerminationMessagePath)
+	assert.Empty(t, appObject.Sidecars[0].ImagePullPolicy, "The CR should not be modified")
+
+	assert.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, corev1.PullAlways, podSpec.InitContainers[0].ImagePullPolicy)
+
+	assert.Len(t, podSpec.Volumes, 2)
+	assert.Equal(t, "config", podSpec.Volumes[0].Name)
+	assert.Equal(t, "custom", podSpec.Volumes[0].ConfigMap.Name, "Volumes should replace the ones with the same name")
+	assert.Equal(t, corev1.ConfigMapVolumeSourceDefaultMode, *podSpec.Volumes[0].ConfigMap.DefaultMode)
+	assert.Equal(t, "jars", podSpec.Volumes[1].Name)
+}
+
 func TestConstructDashbuilderObject(t *testing.T) {
 	name := "test"
 	cr := &api.KieApp{
@@ -2661,10 +2783,11 @@ func TestOpenshiftCA(t *testing.T) {
 		MountPath: constants.TruststorePath,
 		ReadOnly:  true,
 	}
-	assert.NotContains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
-	assert.NotContains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
-	assert.NotContains(t, env.Dashbuilder.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
-	assert.NotContains(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	// the CA signing the keystores is trusted as SSL is enabled
+	assert.Contains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	assert.Contains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	assert.Contains(t, env.Dashbuilder.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	assert.Contains(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
 	trustVol := corev1.Volume{
 		Name: cr.Status.Applied.CommonConfig.ApplicationName + constants.TruststoreSecret,
 		VolumeSource: corev1.VolumeSource{
@@ -2673,11 +2796,25 @@ func TestOpenshiftCA(t *testing.T) {
 			},
 		},
 	}
+	assert.Contains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
+	assert.Contains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
+	assert.Contains(t, env.Dashbuilder.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
+	assert.Contains(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
+
+	cr.Spec.CommonConfig.DisableSsl = true
+	env, err = GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	assert.False(t, HasTruststore(cr))
+	assert.NotContains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	assert.NotContains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	assert.NotContains(t, env.Dashbuilder.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
+	assert.NotContains(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts, trustVolMnt)
 	assert.NotContains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
 	assert.NotContains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
 	assert.NotContains(t, env.Dashbuilder.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
 	assert.NotContains(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
 
+	cr.Spec.CommonConfig.DisableSsl = false
 	cr.Spec.Truststore = &api.KieAppTruststore{
 		OpenshiftCaBundle: true,
 	}
@@ -2703,6 +2840,8 @@ func TestOpenshiftCA(t *testing.T) {
 	assert.NotNil(t, cr.Status.Applied.Objects.SmartRouter.Jvm)
 	assert.NotNil(t, cr.Status.Applied.Objects.Servers[0].Jvm)
 
+	caOptsAppend := getCAJavaOpts(cr)
+	assert.Contains(t, caOptsAppend, "-Djavax.net.ssl.trustStore="+constants.TruststorePath+"/"+constants.TruststoreName)
 	for _, caOption := range caOptsAppend {
 		assert.Contains(t, cr.Status.Applied.Objects.Console.Jvm.JavaOptsAppend, caOption)
 		assert.Contains(t, cr.Status.Applied.Objects.Dashbuilder.Jvm.JavaOptsAppend, caOption)
@@ -2726,6 +2865,79 @@ func TestOpenshiftCA(t *testing.T) {
 	assert.Contains(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, smartRouterVar)
 	assert.Contains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, envVar)
 }
+
+func TestTruststoreSources(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Truststore: &api.KieAppTruststore{
+				From: []api.TruststoreSource{
+					{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"}, Key: "ca.crt"}},
+				},
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	assert.True(t, HasTruststore(cr))
+	assert.False(t, IsOcpCA(cr))
+	// Openshift isn't asked to inject its CA bundle
+	assert.Empty(t, env.Others[0].ConfigMaps)
+
+	trustVol := corev1.Volume{
+		Name: cr.Status.Applied.CommonConfig.ApplicationName + constants.TruststoreSecret,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: cr.Status.Applied.CommonConfig.ApplicationName + constants.TruststoreSecret,
+			},
+		},
+	}
+	assert.Contains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
+	assert.Contains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Volumes, trustVol)
+	envVar := corev1.EnvVar{
+		Name:  "JAVA_OPTS_APPEND",
+		Value: strings.Join(getCAJavaOpts(cr), " "),
+	}
+	assert.Contains(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, envVar)
+	assert.Contains(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, envVar)
+
+	cr.Status.Applied.Version = "7.10.1"
+	assert.False(t, HasTruststore(cr), "Truststores are only supported from 7.11")
+}
+
+func TestPKCS12KeystoreFormat(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Truststore:  &api.KieAppTruststore{OpenshiftCaBundle: true},
+			TLS:         &api.KieAppTLS{KeystoreFormat: api.KeystoreFormatPKCS12, KeyAlgorithm: api.KeyAlgorithmECDSA},
+			Objects: api.KieAppObjects{
+				SmartRouter: &api.SmartRouterObject{},
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	opts := GetKeystoreOptions(cr)
+	assert.Equal(t, constants.PKCS12KeystoreFormat, opts.Format)
+	assert.Equal(t, constants.DefaultECDSAKeySize, opts.KeySize)
+
+	for _, container := range []corev1.Container{env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0], env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0]} {
+		assert.Equal(t, constants.PKCS12KeystoreName, getEnvVariable(container, "HTTPS_KEYSTORE"), container.Name)
+		assert.Equal(t, "PKCS12", getEnvVariable(container, "HTTPS_KEYSTORE_TYPE"), container.Name)
+		assert.Contains(t, getEnvVariable(container, "JAVA_OPTS_APPEND"), "-Djavax.net.ssl.trustStoreType=pkcs12", container.Name)
+		assert.Contains(t, getEnvVariable(container, "JAVA_OPTS_APPEND"), "-Djavax.net.ssl.trustStore="+constants.TruststorePath+"/"+constants.PKCS12TruststoreName, container.Name)
+	}
+	smartRouter := env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "/etc/smartrouter-secret-volume/"+constants.PKCS12KeystoreName, getEnvVariable(smartRouter, "KIE_SERVER_ROUTER_TLS_KEYSTORE"))
+}
+
 func TestMergeTrialAndCommonConfig(t *testing.T) {
 	cr := &api.KieApp{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3588,8 +3800,8 @@ func TestDatabaseExternal(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s", idx), env.Servers[i].Services[0].ObjectMeta.Name)
 		assert.Equal(t, 1, len(env.Servers[i].DeploymentConfigs))
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s", idx), env.Servers[i].DeploymentConfigs[0].Name)
-		assert.Equal(t, 1, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts))
-		assert.Equal(t, 1, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes))
+		assert.Equal(t, 2, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts))
+		assert.Equal(t, 2, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes))
 		assert.Equal(t, 0, len(env.Servers[i].PersistentVolumeClaims))
 		assert.Equal(t, "RHPAM", getEnvVariable(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0], "DATASOURCES"))
 		assert.Equal(t, "true", getEnvVariable(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0], "RHPAM_JTA"))
@@ -3651,11 +3863,11 @@ func TestDatabaseH2(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s", idx), env.Servers[i].Services[0].ObjectMeta.Name)
 		assert.Equal(t, 1, len(env.Servers[i].DeploymentConfigs))
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s", idx), env.Servers[i].DeploymentConfigs[0].Name)
-		assert.Equal(t, 2, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts))
-		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts[1].Name)
-		assert.Equal(t, 2, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes))
-		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[1].Name)
-		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-claim", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+		assert.Equal(t, 3, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts))
+		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts[2].Name)
+		assert.Equal(t, 3, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes))
+		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[2].Name)
+		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-claim", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[2].PersistentVolumeClaim.ClaimName)
 		assert.Equal(t, 1, len(env.Servers[i].PersistentVolumeClaims))
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-claim", idx), env.Servers[i].PersistentVolumeClaims[0].Name)
 		assert.Equal(t, resource.MustParse("10Mi"), env.Servers[i].PersistentVolumeClaims[0].Spec.Resources.Requests["storage"])
@@ -3761,11 +3973,11 @@ func TestDatabaseH2Ephemeral(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s", idx), env.Servers[i].Services[0].ObjectMeta.Name)
 		assert.Equal(t, 1, len(env.Servers[i].DeploymentConfigs))
 		assert.Equal(t, fmt.Sprintf("test-kieserver%s", idx), env.Servers[i].DeploymentConfigs[0].Name)
-		assert.Equal(t, 2, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts))
-		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts[1].Name)
-		assert.Equal(t, 2, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes))
-		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[1].Name)
-		assert.NotNil(t, env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[1].EmptyDir)
+		assert.Equal(t, 3, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts))
+		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].VolumeMounts[2].Name)
+		assert.Equal(t, 3, len(env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes))
+		assert.Equal(t, fmt.Sprintf("test-kieserver%s-kie-pvol", idx), env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[2].Name)
+		assert.NotNil(t, env.Servers[i].DeploymentConfigs[0].Spec.Template.Spec.Volumes[2].EmptyDir)
 		assert.Equal(t, 0, len(env.Servers[i].PersistentVolumeClaims))
 	}
 }
@@ -5188,7 +5400,7 @@ func TestProcessMigrationRouteCustomConfig(t *testing.T) {
 
 	assert.Equal(t, "true", getEnvVariable(env.ProcessMigration.DeploymentConfigs[0].Spec.Template.Spec.Containers[0], "SCRIPT_DEBUG"))
 	assert.Equal(t, "/tmp/test.jar", getEnvVariable(env.ProcessMigration.DeploymentConfigs[0].Spec.Template.Spec.Containers[0], "JBOSS_KIE_EXTRA_CLASSPATH"))
-	testJvmObjectWithoutJavaMaxMemRatio(t, env.ProcessMigration.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env)
+	testJvmObjectWithoutJavaMaxMemRatio(t, env.ProcessMigration.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, "-Dsome.property=foo")
 
 	cr = &api.KieApp{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5263,6 +5475,13 @@ func TestMergeProcessMigrationDB(t *testing.T) {
 			},
 			api.Environment{
 				ProcessMigration: api.CustomObject{
+					DeploymentConfigs: []appsv1.DeploymentConfig{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "kietest-process-migration",
+							},
+						},
+					},
 					ConfigMaps: []corev1.ConfigMap{
 						{
 							ObjectMeta: metav1.ObjectMeta{
@@ -5814,7 +6033,7 @@ func TestJvmDefaultConsole(t *testing.T) {
 		},
 	}
 	env, _ := GetEnvironment(cr, test.MockService())
-	testJvmObjectWithoutJavaMaxMemRatio(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env)
+	testJvmObjectWithoutJavaMaxMemRatio(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, withCAJavaOpts("-Dsome.property=foo"))
 }
 
 func TestJvmEmptyConsole(t *testing.T) {
@@ -5850,7 +6069,7 @@ func TestJvmDefaultSmartRouter(t *testing.T) {
 		},
 	}
 	env, _ := GetEnvironment(cr, test.MockService())
-	testJvmObjectWithoutJavaMaxMemRatio(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env)
+	testJvmObjectWithoutJavaMaxMemRatio(t, env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, withCAJavaOpts("-Dsome.property=foo"))
 }
 
 func TestJvmEmptySmartRouter(t *testing.T) {
@@ -5888,7 +6107,7 @@ func TestJvmDefaultServers(t *testing.T) {
 		},
 	}
 	env, _ := GetEnvironment(cr, test.MockService())
-	testJvmObjectWithoutJavaMaxMemRatio(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env)
+	testJvmObjectWithoutJavaMaxMemRatio(t, env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Env, withCAJavaOpts("-Dsome.property=foo"))
 }
 
 func TestJvmEmptyServer(t *testing.T) {
@@ -5942,9 +6161,9 @@ func createJvmTestObjectWithoutJavaMaxMemRatio() *api.JvmObject {
 	return &jvmObject
 }
 
-func testJvmObjectWithoutJavaMaxMemRatio(t *testing.T, envs []corev1.EnvVar) {
+func testJvmObjectWithoutJavaMaxMemRatio(t *testing.T, envs []corev1.EnvVar, javaOptsAppend string) {
 
-	assert.Equal(t, "-Dsome.property=foo", getSpecEnv(envs, "JAVA_OPTS_APPEND"))
+	assert.Equal(t, javaOptsAppend, getSpecEnv(envs, "JAVA_OPTS_APPEND"))
 	assert.Equal(t, "4096", getSpecEnv(envs, "JAVA_MAX_INITIAL_MEM"))
 	assert.Equal(t, "true", getSpecEnv(envs, "JAVA_DIAGNOSTICS"))
 	assert.Equal(t, "true", getSpecEnv(envs, "JAVA_DEBUG"))
@@ -7349,3 +7568,40 @@ func DataGridAuth(t *testing.T, environment api.EnvironmentType) {
 	assert.Equal(t, cr.Status.Applied.Objects.Console.DataGridAuth.Username, "InfinispanUser")
 	assert.Equal(t, cr.Status.Applied.Objects.Console.DataGridAuth.Password, "InfinispanPassword")
 }
+
+func TestSetSpecDefaults(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{},
+			},
+		},
+	}
+	SetSpecDefaults(cr, api.KubernetesPlatform)
+	assert.Empty(t, cr.Spec.CommonConfig.ApplicationName)
+	assert.Empty(t, cr.Spec.CommonConfig.AdminPassword)
+	assert.Equal(t, api.ControllerStartupStrategy, cr.Spec.CommonConfig.StartupStrategy.StrategyName)
+	assert.Len(t, cr.Spec.Objects.Servers, 1)
+	assert.Equal(t, "test-kieserver", cr.Spec.Objects.Servers[0].Name)
+	assert.Equal(t, int32(80), *cr.Spec.Objects.Servers[0].Jvm.JavaMaxMemRatio)
+	assert.Equal(t, constants.ServersLimits["MEM"], cr.Spec.Objects.Servers[0].Resources.Limits.Memory().String())
+	assert.Equal(t, constants.ConsoleProdLimits["CPU"], cr.Spec.Objects.Console.Resources.Limits.Cpu().String())
+	assert.Nil(t, cr.Spec.Objects.SmartRouter)
+
+	expected := cr.Spec.DeepCopy()
+	SetSpecDefaults(cr, api.OpenShiftPlatform)
+	assert.Equal(t, expected, &cr.Spec)
+
+	SetDefaults(cr)
+	assert.Equal(t, cr.Spec.Objects.Servers, cr.Status.Applied.Objects.Servers)
+
+	generated := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "test-"},
+		Spec:       api.KieAppSpec{Environment: api.RhpamProduction},
+	}
+	SetSpecDefaults(generated, api.OpenShiftPlatform)
+	assert.Empty(t, generated.Spec.Objects.Servers)
+	assert.Equal(t, api.OpenshiftStartupStrategy, generated.Spec.CommonConfig.StartupStrategy.StrategyName)
+}
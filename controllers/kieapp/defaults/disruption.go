@@ -0,0 +1,98 @@
+package defaults
+
+import (
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// AddPodDisruptionBudgets adds a PodDisruptionBudget for every DeploymentConfig and StatefulSet of the console,
+// dashbuilder, smartrouter, kieservers, datagrid and AMQ that runs more than one replica, or may be autoscaled to more.
+// It is called after AddAutoscaling.
+func AddPodDisruptionBudgets(env api.Environment, cr *api.KieApp) api.Environment {
+	config := cr.Status.Applied.PodDisruptionBudget
+	if config != nil && config.Disabled {
+		return env
+	}
+	add := func(object *api.CustomObject) {
+		if object.Omit {
+			return
+		}
+		for _, dc := range object.DeploymentConfigs {
+			if dc.Spec.Replicas > 1 || isAutoscaledAbove(*object, dc.Name, 1) {
+				object.PodDisruptionBudgets = append(object.PodDisruptionBudgets, getPodDisruptionBudget(dc.ObjectMeta, dc.Spec.Selector, dc.Spec.Template, cr))
+			}
+		}
+		for _, statefulSet := range object.StatefulSets {
+			if statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas > 1 {
+				var selector map[string]string
+				if statefulSet.Spec.Selector != nil {
+					selector = statefulSet.Spec.Selector.MatchLabels
+				}
+				object.PodDisruptionBudgets = append(object.PodDisruptionBudgets, getPodDisruptionBudget(statefulSet.ObjectMeta, selector, &statefulSet.Spec.Template, cr))
+			}
+		}
+	}
+	add(&env.Console)
+	add(&env.Dashbuilder)
+	add(&env.SmartRouter)
+	for i := range env.Servers {
+		add(&env.Servers[i])
+	}
+	for i := range env.Others {
+		add(&env.Others[i])
+	}
+	return env
+}
+
+// isAutoscaledAbove tells whether a HorizontalPodAutoscaler of the object may scale the named workload above the replicas
+func isAutoscaledAbove(object api.CustomObject, name string, replicas int32) bool {
+	for _, hpa := range object.HorizontalPodAutoscalers {
+		if hpa.Spec.ScaleTargetRef.Name == name && hpa.Spec.MaxReplicas > replicas {
+			return true
+		}
+	}
+	return false
+}
+
+// getPodSelector returns the labels selecting the workload pods, their deploymentConfig label when set, as the selector
+// of some workloads, e.g. the AMQ StatefulSet, matches every pod of the application
+func getPodSelector(selector map[string]string, template *corev1.PodTemplateSpec) map[string]string {
+	if template != nil && template.Labels["deploymentConfig"] != "" {
+		return map[string]string{"deploymentConfig": template.Labels["deploymentConfig"]}
+	}
+	return selector
+}
+
+func getPodDisruptionBudget(workload metav1.ObjectMeta, selector map[string]string, template *corev1.PodTemplateSpec, cr *api.KieApp) policyv1.PodDisruptionBudget {
+	selector = getPodSelector(selector, template)
+	labels := map[string]string{}
+	for key, value := range workload.Labels {
+		labels[key] = value
+	}
+	pdb := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workload.Name,
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+	config := cr.Status.Applied.PodDisruptionBudget
+	if config != nil && config.MinAvailable != nil {
+		minAvailable := *config.MinAvailable
+		pdb.Spec.MinAvailable = &minAvailable
+	} else if config != nil && config.MaxUnavailable != nil {
+		maxUnavailable := *config.MaxUnavailable
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	} else {
+		maxUnavailable := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+	pdb.SetGroupVersionKind(policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+	return pdb
+}
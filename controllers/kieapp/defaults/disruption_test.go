@@ -0,0 +1,109 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func getDisruptionEnvironment(t *testing.T, cr *api.KieApp) api.Environment {
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	return AddPodDisruptionBudgets(AddAutoscaling(ConsolidateObjects(env, cr), cr), cr)
+}
+
+func getPodDisruptionBudgets(env api.Environment) map[string]policyv1.PodDisruptionBudget {
+	pdbs := map[string]policyv1.PodDisruptionBudget{}
+	objects := append([]api.CustomObject{env.Console, env.Dashbuilder, env.SmartRouter}, env.Servers...)
+	for _, object := range append(objects, env.Others...) {
+		for _, pdb := range object.PodDisruptionBudgets {
+			pdbs[pdb.Name] = pdb
+		}
+	}
+	return pdbs
+}
+
+func TestAddPodDisruptionBudgetsSingleReplica(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec:       api.KieAppSpec{Environment: api.RhpamTrial},
+	}
+	env := getDisruptionEnvironment(t, cr)
+	assert.Empty(t, getPodDisruptionBudgets(env))
+}
+
+func TestAddPodDisruptionBudgetsAuthoringHA(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamAuthoringHA,
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{Replicas: Pint32(3)}},
+			},
+		},
+	}
+	env := getDisruptionEnvironment(t, cr)
+	pdbs := getPodDisruptionBudgets(env)
+	assert.Len(t, pdbs, 4)
+
+	maxUnavailable := intstr.FromInt(1)
+	console := pdbs["test-rhpamcentr"]
+	assert.Equal(t, "test-ns", console.Namespace)
+	assert.Equal(t, map[string]string{"deploymentConfig": "test-rhpamcentr"}, console.Spec.Selector.MatchLabels)
+	assert.Equal(t, &maxUnavailable, console.Spec.MaxUnavailable)
+	assert.Nil(t, console.Spec.MinAvailable)
+	assert.Equal(t, "PodDisruptionBudget", console.Kind)
+
+	assert.Equal(t, map[string]string{"deploymentConfig": "test-kieserver"}, pdbs["test-kieserver"].Spec.Selector.MatchLabels)
+	assert.Equal(t, map[string]string{"deploymentConfig": "test-datagrid"}, pdbs["test-datagrid"].Spec.Selector.MatchLabels)
+	assert.Equal(t, map[string]string{"deploymentConfig": "test-amq"}, pdbs["test-amq"].Spec.Selector.MatchLabels)
+}
+
+func TestAddPodDisruptionBudgetsConfigured(t *testing.T) {
+	minAvailable := intstr.FromString("50%")
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{KieAppObject: api.KieAppObject{Replicas: Pint32(2)}}},
+			},
+			PodDisruptionBudget: &api.KieAppPodDisruptionBudget{MinAvailable: &minAvailable},
+		},
+	}
+	env := getDisruptionEnvironment(t, cr)
+	pdbs := getPodDisruptionBudgets(env)
+	assert.Len(t, pdbs, 1)
+	server := pdbs["test-kieserver"]
+	assert.Equal(t, &minAvailable, server.Spec.MinAvailable)
+	assert.Nil(t, server.Spec.MaxUnavailable)
+
+	cr.Spec.PodDisruptionBudget.Disabled = true
+	env = getDisruptionEnvironment(t, cr)
+	assert.Empty(t, getPodDisruptionBudgets(env))
+}
+
+func TestAddPodDisruptionBudgetsAutoscaled(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{Autoscaling: &api.KieServerAutoscaling{Enabled: true, MaxReplicas: 3}}},
+			},
+		},
+	}
+	env := getDisruptionEnvironment(t, cr)
+	pdbs := getPodDisruptionBudgets(env)
+	assert.Len(t, pdbs, 1)
+	assert.Equal(t, map[string]string{"deploymentConfig": "test-kieserver"}, pdbs["test-kieserver"].Spec.Selector.MatchLabels)
+
+	cr.Spec.Objects.Servers[0].Autoscaling.MaxReplicas = 1
+	env = getDisruptionEnvironment(t, cr)
+	assert.Empty(t, getPodDisruptionBudgets(env))
+}
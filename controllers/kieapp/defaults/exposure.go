@@ -0,0 +1,253 @@
+package defaults
+
+import (
+	"fmt"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+// routerAnnotationPrefix annotations only understood by the OpenShift router, dropped from Ingresses and HTTPRoutes
+const routerAnnotationPrefix = "haproxy.router.openshift.io/"
+
+// GetExposureType returns the kind of object used to expose the application outside the cluster
+func GetExposureType(cr *api.KieApp) api.ExposureType {
+	if cr.Status.Applied.Exposure != nil && cr.Status.Applied.Exposure.Type != "" {
+		return cr.Status.Applied.Exposure.Type
+	}
+	if IsKubernetes(cr) {
+		return api.IngressExposure
+	}
+	return api.RouteExposure
+}
+
+// ConvertRoutes replaces the Routes of every component with Ingresses or HTTPRoutes, depending on the configured exposure type
+func ConvertRoutes(env api.Environment, cr *api.KieApp) api.Environment {
+	exposureType := GetExposureType(cr)
+	if exposureType == api.RouteExposure {
+		return env
+	}
+	convert := func(object api.CustomObject) api.CustomObject {
+		for _, route := range object.Routes {
+			if exposureType == api.IngressExposure {
+				object.Ingresses = append(object.Ingresses, getIngress(route, object.Services, cr))
+			} else {
+				object.HTTPRoutes = append(object.HTTPRoutes, getHTTPRoute(route, object.Services, cr))
+			}
+		}
+		object.Routes = nil
+		return object
+	}
+	env.Console = convert(env.Console)
+	env.Dashbuilder = convert(env.Dashbuilder)
+	env.SmartRouter = convert(env.SmartRouter)
+	env.ProcessMigration = convert(env.ProcessMigration)
+	for i := range env.Servers {
+		env.Servers[i] = convert(env.Servers[i])
+	}
+	for i := range env.Databases {
+		env.Databases[i] = convert(env.Databases[i])
+	}
+	for i := range env.Others {
+		env.Others[i] = convert(env.Others[i])
+	}
+	return env
+}
+
+// GetExposedHostname returns the hostname requested for the route, or the one generated from the exposure domain. It is
+// empty when neither is configured, which CheckExposedHostnames rejects before any Ingress or HTTPRoute is generated.
+func GetExposedHostname(route routev1.Route, cr *api.KieApp) string {
+	if route.Spec.Host != "" {
+		return route.Spec.Host
+	}
+	if cr.Status.Applied.Exposure != nil && cr.Status.Applied.Exposure.Domain != "" {
+		return fmt.Sprintf("%s-%s.%s", route.Name, cr.Namespace, cr.Status.Applied.Exposure.Domain)
+	}
+	return ""
+}
+
+// CheckExposedHostnames fails when Ingresses or HTTPRoutes replace routes that have neither a routeHostname nor an
+// exposure domain to generate their hostname from, unlike the OpenShift router there is nothing to assign one
+func CheckExposedHostnames(env api.Environment, cr *api.KieApp) error {
+	exposureType := GetExposureType(cr)
+	if exposureType == api.RouteExposure {
+		return nil
+	}
+	var missing []string
+	check := func(object api.CustomObject) {
+		if object.Omit {
+			return
+		}
+		for _, route := range object.Routes {
+			if GetExposedHostname(route, cr) == "" {
+				missing = append(missing, route.Name)
+			}
+		}
+	}
+	check(env.Console)
+	check(env.Dashbuilder)
+	check(env.SmartRouter)
+	check(env.ProcessMigration)
+	for _, object := range env.Servers {
+		check(object)
+	}
+	for _, object := range env.Databases {
+		check(object)
+	}
+	for _, object := range env.Others {
+		check(object)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("spec.exposure.domain or a routeHostname is required to expose %s with an %s", strings.Join(missing, ", "), exposureType)
+	}
+	return nil
+}
+
+func getExposedAnnotations(route routev1.Route, extra map[string]string) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range route.Annotations {
+		if !strings.HasPrefix(key, routerAnnotationPrefix) {
+			annotations[key] = value
+		}
+	}
+	for key, value := range extra {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+func getIngress(route routev1.Route, services []corev1.Service, cr *api.KieApp) networkingv1.Ingress {
+	config := &api.KieAppIngress{}
+	if cr.Status.Applied.Exposure != nil && cr.Status.Applied.Exposure.Ingress != nil {
+		config = cr.Status.Applied.Exposure.Ingress
+	}
+	host := GetExposedHostname(route, cr)
+	path := route.Spec.Path
+	if path == "" {
+		path = "/"
+	}
+	pathType := networkingv1.PathTypePrefix
+	backend := networkingv1.IngressServiceBackend{Name: route.Spec.To.Name}
+	// the route targets a port of the pods, whereas the ingress backend is a port of the service
+	if port := getServicePort(route, services); port != nil {
+		backend.Port.Number = int32(*port)
+	} else if route.Spec.Port != nil {
+		backend.Port.Name = route.Spec.Port.TargetPort.StrVal
+	}
+	ingress := networkingv1.Ingress{
+		ObjectMeta: *route.ObjectMeta.DeepCopy(),
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: config.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     path,
+									PathType: &pathType,
+									Backend:  networkingv1.IngressBackend{Service: &backend},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ingress.Annotations = getExposedAnnotations(route, config.Annotations)
+	ingress.SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("Ingress"))
+	if route.Spec.TLS != nil {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: config.TLSSecret}}
+	}
+	return ingress
+}
+
+func getHTTPRoute(route routev1.Route, services []corev1.Service, cr *api.KieApp) gatewayv1alpha1.HTTPRoute {
+	config := &api.KieAppHTTPRoute{}
+	if cr.Status.Applied.Exposure != nil && cr.Status.Applied.Exposure.HTTPRoute != nil {
+		config = cr.Status.Applied.Exposure.HTTPRoute
+	}
+	path := route.Spec.Path
+	if path == "" {
+		path = "/"
+	}
+	pathType := gatewayv1alpha1.PathMatchPrefix
+	allow := gatewayv1alpha1.GatewayAllowSameNamespace
+	serviceName := route.Spec.To.Name
+	httpRoute := gatewayv1alpha1.HTTPRoute{
+		ObjectMeta: *route.ObjectMeta.DeepCopy(),
+		Spec: gatewayv1alpha1.HTTPRouteSpec{
+			Gateways: &gatewayv1alpha1.RouteGateways{Allow: &allow},
+			Rules: []gatewayv1alpha1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1alpha1.HTTPRouteMatch{
+						{Path: &gatewayv1alpha1.HTTPPathMatch{Type: &pathType, Value: &path}},
+					},
+					ForwardTo: []gatewayv1alpha1.HTTPRouteForwardTo{
+						{
+							ServiceName: &serviceName,
+							Port:        getServicePort(route, services),
+							Weight:      Pint32(1),
+						},
+					},
+				},
+			},
+		},
+	}
+	httpRoute.Annotations = getExposedAnnotations(route, config.Annotations)
+	for key, value := range config.Labels {
+		if httpRoute.Labels == nil {
+			httpRoute.Labels = map[string]string{}
+		}
+		httpRoute.Labels[key] = value
+	}
+	if len(config.Gateways) > 0 {
+		allow = gatewayv1alpha1.GatewayAllowFromList
+		for _, gateway := range config.Gateways {
+			httpRoute.Spec.Gateways.GatewayRefs = append(httpRoute.Spec.Gateways.GatewayRefs, gatewayv1alpha1.GatewayReference{
+				Name:      gateway.Name,
+				Namespace: gateway.Namespace,
+			})
+		}
+	}
+	httpRoute.Spec.Hostnames = []gatewayv1alpha1.Hostname{gatewayv1alpha1.Hostname(GetExposedHostname(route, cr))}
+	if route.Spec.TLS != nil && config.TLSSecret != "" {
+		httpRoute.Spec.TLS = &gatewayv1alpha1.RouteTLSConfig{
+			CertificateRef: gatewayv1alpha1.LocalObjectReference{Group: "core", Kind: "Secret", Name: config.TLSSecret},
+		}
+	}
+	httpRoute.SetGroupVersionKind(gatewayv1alpha1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	return httpRoute
+}
+
+// getServicePort resolves the route target port into the port number of the backing service, as HTTPRoutes only forward to port numbers
+func getServicePort(route routev1.Route, services []corev1.Service) *gatewayv1alpha1.PortNumber {
+	if route.Spec.Port == nil {
+		return nil
+	}
+	targetPort := route.Spec.Port.TargetPort
+	for _, service := range services {
+		if service.Name != route.Spec.To.Name {
+			continue
+		}
+		for _, port := range service.Spec.Ports {
+			if (targetPort.Type == intstr.String && (port.Name == targetPort.StrVal || port.TargetPort.StrVal == targetPort.StrVal)) ||
+				(targetPort.Type == intstr.Int && (port.Port == targetPort.IntVal || port.TargetPort.IntVal == targetPort.IntVal)) {
+				portNumber := gatewayv1alpha1.PortNumber(port.Port)
+				return &portNumber
+			}
+		}
+	}
+	if targetPort.Type == intstr.Int {
+		portNumber := gatewayv1alpha1.PortNumber(targetPort.IntVal)
+		return &portNumber
+	}
+	return nil
+}
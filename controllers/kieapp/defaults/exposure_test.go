@@ -0,0 +1,168 @@
+package defaults
+
+import (
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+func TestGetExposureType(t *testing.T) {
+	cr := &api.KieApp{}
+	assert.Equal(t, api.RouteExposure, GetExposureType(cr))
+	cr.Status.Applied.Platform = api.KubernetesPlatform
+	assert.Equal(t, api.IngressExposure, GetExposureType(cr))
+	cr.Status.Applied.Exposure = &api.KieAppExposure{Type: api.HTTPRouteExposure}
+	assert.Equal(t, api.HTTPRouteExposure, GetExposureType(cr))
+}
+
+func TestConvertRoutesToIngresses(t *testing.T) {
+	className := "nginx"
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Exposure: &api.KieAppExposure{
+				Type:   api.IngressExposure,
+				Domain: "apps.example.com",
+				Ingress: &api.KieAppIngress{
+					IngressClassName: &className,
+					TLSSecret:        "wildcard-tls",
+					Annotations:      map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"},
+				},
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+
+	env = ConvertRoutes(ConsolidateObjects(env, cr), cr)
+	assert.Empty(t, env.Console.Routes)
+	assert.Empty(t, env.Servers[0].Routes)
+	assert.NotEmpty(t, env.Servers[0].Ingresses)
+	assert.Len(t, env.Console.Ingresses, 2)
+	ingress := env.Console.Ingresses[0]
+	assert.Equal(t, "test-rhpamcentr", ingress.Name)
+	assert.Equal(t, &className, ingress.Spec.IngressClassName)
+	assert.Equal(t, "HTTPS", ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"])
+	for key := range ingress.Annotations {
+		assert.NotContains(t, key, "haproxy.router.openshift.io")
+	}
+	host := "test-rhpamcentr-test-ns.apps.example.com"
+	assert.Equal(t, host, ingress.Spec.Rules[0].Host)
+	path := ingress.Spec.Rules[0].HTTP.Paths[0]
+	assert.Equal(t, "/", path.Path)
+	assert.Equal(t, networkingv1.PathTypePrefix, *path.PathType)
+	assert.Equal(t, "test-rhpamcentr", path.Backend.Service.Name)
+	assert.Equal(t, networkingv1.ServiceBackendPort{Number: 8443}, path.Backend.Service.Port)
+	assert.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: "wildcard-tls"}}, ingress.Spec.TLS)
+	assert.Equal(t, "test-rhpamcentr-http", env.Console.Ingresses[1].Name)
+	assert.Empty(t, env.Console.Ingresses[1].Spec.TLS)
+}
+
+func TestGetHTTPRoute(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns"},
+	}
+	cr.Status.Applied.Exposure = &api.KieAppExposure{
+		Type: api.HTTPRouteExposure,
+		HTTPRoute: &api.KieAppHTTPRoute{
+			Labels:    map[string]string{"gateway": "external"},
+			Gateways:  []api.GatewayRef{{Name: "external", Namespace: "gateways"}},
+			TLSSecret: "kieserver-tls",
+		},
+	}
+	route := routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-kieserver",
+			Labels: map[string]string{"app": "test"},
+		},
+		Spec: routev1.RouteSpec{
+			Host: "kieserver.example.com",
+			To:   routev1.RouteTargetReference{Kind: "Service", Name: "test-kieserver"},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString("https")},
+			TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough},
+		},
+	}
+	services := []corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-kieserver"},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 8080, TargetPort: intstr.FromInt(8080)},
+					{Name: "https", Port: 8443, TargetPort: intstr.FromInt(8443)},
+				},
+			},
+		},
+	}
+
+	httpRoute := getHTTPRoute(route, services, cr)
+	assert.Equal(t, "HTTPRoute", httpRoute.Kind)
+	assert.Equal(t, map[string]string{"app": "test", "gateway": "external"}, httpRoute.Labels)
+	assert.Equal(t, gatewayv1alpha1.GatewayAllowFromList, *httpRoute.Spec.Gateways.Allow)
+	assert.Equal(t, []gatewayv1alpha1.GatewayReference{{Name: "external", Namespace: "gateways"}}, httpRoute.Spec.Gateways.GatewayRefs)
+	assert.Equal(t, []gatewayv1alpha1.Hostname{"kieserver.example.com"}, httpRoute.Spec.Hostnames)
+	assert.Equal(t, "kieserver-tls", httpRoute.Spec.TLS.CertificateRef.Name)
+	forwardTo := httpRoute.Spec.Rules[0].ForwardTo[0]
+	assert.Equal(t, "test-kieserver", *forwardTo.ServiceName)
+	assert.Equal(t, gatewayv1alpha1.PortNumber(8443), *forwardTo.Port)
+	assert.Equal(t, int32(1), *forwardTo.Weight)
+
+	cr.Status.Applied.Exposure.HTTPRoute = nil
+	httpRoute = getHTTPRoute(route, services, cr)
+	assert.Equal(t, gatewayv1alpha1.GatewayAllowSameNamespace, *httpRoute.Spec.Gateways.Allow)
+	assert.Nil(t, httpRoute.Spec.TLS)
+
+	// without hostname, the route is exposed on a hostname generated from the domain
+	route.Spec.Host = ""
+	cr.Status.Applied.Exposure.Domain = "apps.example.com"
+	httpRoute = getHTTPRoute(route, services, cr)
+	assert.Equal(t, []gatewayv1alpha1.Hostname{"test-kieserver-test-ns.apps.example.com"}, httpRoute.Spec.Hostnames)
+	ingress := getIngress(route, services, cr)
+	assert.Equal(t, "test-kieserver-test-ns.apps.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, []string{"test-kieserver-test-ns.apps.example.com"}, ingress.Spec.TLS[0].Hosts)
+	assert.Equal(t, networkingv1.ServiceBackendPort{Number: 8443}, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port)
+}
+
+func TestCheckExposedHostnames(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamAuthoring,
+			Platform:    api.KubernetesPlatform,
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{RouteHostname: "console.example.com"}},
+			},
+		},
+	}
+	_, err := GetEnvironment(cr, test.MockService())
+	assert.EqualError(t, err, "spec.exposure.domain or a routeHostname is required to expose test-kieserver with an Ingress")
+
+	cr.Spec.Objects.Servers = []api.KieServerSet{{KieAppObject: api.KieAppObject{RouteHostname: "kieserver.example.com"}}}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	assert.Nil(t, CheckExposedHostnames(env, cr))
+
+	// the additional http routes of the trial environment have no routeHostname
+	cr.Spec.Environment = api.RhpamTrial
+	_, err = GetEnvironment(cr, test.MockService())
+	assert.EqualError(t, err, "spec.exposure.domain or a routeHostname is required to expose test-rhpamcentr-http, test-kieserver-http with an Ingress")
+
+	// the OpenShift router assigns the hostname of routes
+	cr.Spec.Platform = api.OpenShiftPlatform
+	_, err = GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,167 @@
+package defaults
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/RHsyseng/operator-utils/pkg/logs"
+	oappsv1 "github.com/openshift/api/apps/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsKubernetes returns true when the KieApp is deployed on a plain Kubernetes cluster
+func IsKubernetes(cr *api.KieApp) bool {
+	return cr.Status.Applied.Platform == api.KubernetesPlatform
+}
+
+// ConvertToKubernetes replaces the DeploymentConfigs of every component with equivalent Deployments,
+// and drops the objects that are only served by OpenShift, e.g. Routes, ImageStreams and BuildConfigs.
+func ConvertToKubernetes(env api.Environment, cr *api.KieApp) api.Environment {
+	env.Console = convertCustomObject(env.Console, cr)
+	env.Dashbuilder = convertCustomObject(env.Dashbuilder, cr)
+	env.SmartRouter = convertCustomObject(env.SmartRouter, cr)
+	env.ProcessMigration = convertCustomObject(env.ProcessMigration, cr)
+	for i := range env.Servers {
+		env.Servers[i] = convertCustomObject(env.Servers[i], cr)
+	}
+	for i := range env.Databases {
+		env.Databases[i] = convertCustomObject(env.Databases[i], cr)
+	}
+	for i := range env.Others {
+		env.Others[i] = convertCustomObject(env.Others[i], cr)
+	}
+	return env
+}
+
+func convertCustomObject(object api.CustomObject, cr *api.KieApp) api.CustomObject {
+	for _, dc := range object.DeploymentConfigs {
+		object.Deployments = append(object.Deployments, getDeployment(dc, cr))
+	}
+	for i := range object.HorizontalPodAutoscalers {
+		target := &object.HorizontalPodAutoscalers[i].Spec.ScaleTargetRef
+		if target.Kind == "DeploymentConfig" {
+			target.APIVersion = appsv1.SchemeGroupVersion.String()
+			target.Kind = "Deployment"
+		}
+	}
+	if len(object.BuildConfigs) > 0 {
+		log.Warnf("BuildConfigs are not supported on %s, the %d configured builds will not be created", api.KubernetesPlatform, len(object.BuildConfigs))
+	}
+	object.DeploymentConfigs = nil
+	object.BuildConfigs = nil
+	object.ImageStreams = nil
+	object.Routes = nil
+	return object
+}
+
+// getDeployment builds the apps/v1 Deployment equivalent to the given DeploymentConfig, resolving
+// the images of the ImageChange triggers into image references that can be pulled directly
+func getDeployment(dc oappsv1.DeploymentConfig, cr *api.KieApp) appsv1.Deployment {
+	deployment := appsv1.Deployment{
+		ObjectMeta: *dc.ObjectMeta.DeepCopy(),
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             Pint32(dc.Spec.Replicas),
+			MinReadySeconds:      dc.Spec.MinReadySeconds,
+			RevisionHistoryLimit: dc.Spec.RevisionHistoryLimit,
+			Paused:               dc.Spec.Paused,
+			Strategy:             getDeploymentStrategy(dc.Spec.Strategy),
+		},
+	}
+	deployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	if len(dc.Spec.Selector) > 0 {
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: dc.Spec.Selector}
+	}
+	if dc.Spec.Template != nil {
+		deployment.Spec.Template = *dc.Spec.Template.DeepCopy()
+	}
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.Type != oappsv1.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+		for _, containerName := range trigger.ImageChangeParams.ContainerNames {
+			resolveImage(deployment.Spec.Template.Spec.InitContainers, containerName, trigger.ImageChangeParams.From, cr)
+			resolveImage(deployment.Spec.Template.Spec.Containers, containerName, trigger.ImageChangeParams.From, cr)
+		}
+	}
+	return deployment
+}
+
+func getDeploymentStrategy(dcStrategy oappsv1.DeploymentStrategy) appsv1.DeploymentStrategy {
+	if dcStrategy.Type == oappsv1.DeploymentStrategyTypeRecreate {
+		return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	}
+	strategy := appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+	if dcStrategy.RollingParams != nil {
+		strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: dcStrategy.RollingParams.MaxUnavailable,
+			MaxSurge:       dcStrategy.RollingParams.MaxSurge,
+		}
+	}
+	return strategy
+}
+
+// resolveImage sets the image of the named container to a pullable reference, as there is no ImageStream to resolve it from
+func resolveImage(containers []corev1.Container, containerName string, from corev1.ObjectReference, cr *api.KieApp) {
+	for i := range containers {
+		if containers[i].Name != containerName {
+			continue
+		}
+		if from.Kind == "DockerImage" {
+			containers[i].Image = from.Name
+		} else if !strings.Contains(containers[i].Image, "/") {
+			containers[i].Image = GetRegistryImageURL(from.Name, containers[i].Image, cr)
+		}
+	}
+}
+
+// GetImageRegistry returns a copy of the registry configured for the KieApp, defaulting it when missing
+func GetImageRegistry(cr *api.KieApp) *api.KieAppRegistry {
+	registry := &api.KieAppRegistry{
+		Insecure: logs.GetBoolEnv("INSECURE"),
+	}
+	if cr.Status.Applied.ImageRegistry != nil {
+		registry = cr.Status.Applied.ImageRegistry.DeepCopy()
+	}
+	if registry.Registry == "" {
+		registry.Registry = logs.GetEnv("REGISTRY", constants.ImageRegistry)
+	}
+	return registry
+}
+
+// GetRegistryImageURL returns the registry image that backs the given ImageStreamTag
+func GetRegistryImageURL(tagRefName, imageURL string, cr *api.KieApp) string {
+	result := strings.Split(tagRefName, ":")
+	if len(result) == 1 {
+		result = append(result, "latest")
+	}
+	product := GetProduct(cr.Status.Applied.Environment)
+	imageName := fmt.Sprintf("%s:%s", result[0], result[1])
+	major, _, _ := GetMajorMinorMicro(cr.Status.Applied.Version)
+	regContext := fmt.Sprintf("%s-%s", product, major)
+	if _, _, imageContext := GetImage(imageURL); imageContext != "" {
+		regContext = imageContext
+	}
+
+	registryAddress := GetImageRegistry(cr).Registry
+	if strings.Contains(result[0], "datagrid") {
+		registryAddress = constants.ImageRegistry
+		regContext = "jboss-datagrid-7"
+	} else if strings.Contains(result[0], "amq-broker-7") {
+		registryAddress = constants.ImageRegistry
+		regContext = "amq-broker-7"
+		if strings.Contains(result[0], "scaledown") {
+			regContext = "amq-broker-7-tech-preview"
+		}
+	} else if result[0] == "postgresql" || result[0] == "mysql" {
+		registryAddress = constants.ImageRegistry
+		regContext = "rhscl"
+		pattern := regexp.MustCompile("[0-9]+")
+		imageName = fmt.Sprintf("%s-%s-rhel7:%s", result[0], strings.Join(pattern.FindAllString(result[1], -1), ""), "latest")
+	}
+	return fmt.Sprintf("%s/%s/%s", registryAddress, regContext, imageName)
+}
@@ -0,0 +1,112 @@
+package defaults
+
+import (
+	"strings"
+	"testing"
+
+	oappsv1 "github.com/openshift/api/apps/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestConvertToKubernetes(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+		},
+		Spec: api.KieAppSpec{
+			Environment:  api.RhpamProduction,
+			Platform:     api.KubernetesPlatform,
+			Exposure:     &api.KieAppExposure{Domain: "apps.example.com"},
+			UseImageTags: true,
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	assert.Equal(t, api.ControllerStartupStrategy, cr.Status.Applied.CommonConfig.StartupStrategy.StrategyName)
+	assert.True(t, IsKubernetes(cr))
+
+	env = ConvertToKubernetes(ConsolidateObjects(env, cr), cr)
+	for _, object := range []api.CustomObject{env.Console, env.SmartRouter, env.Servers[0]} {
+		assert.Empty(t, object.DeploymentConfigs)
+		assert.Empty(t, object.Routes)
+		assert.Empty(t, object.ImageStreams)
+		assert.Empty(t, object.BuildConfigs)
+	}
+	assert.Len(t, env.Console.Deployments, 1)
+	console := env.Console.Deployments[0]
+	assert.Equal(t, "test-rhpamcentrmon", console.Name)
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, console.Spec.Strategy.Type)
+	assert.Equal(t, "100%", console.Spec.Strategy.RollingUpdate.MaxSurge.String())
+	assert.Equal(t, "test-rhpamcentrmon", console.Spec.Selector.MatchLabels["deploymentConfig"])
+	assert.Equal(t, bcmImage+":"+constants.CurrentVersion, console.Spec.Template.Spec.Containers[0].Image)
+
+	assert.Len(t, env.Servers[0].Deployments, 1)
+	assert.Equal(t, *cr.Status.Applied.Objects.Servers[0].Replicas, *env.Servers[0].Deployments[0].Spec.Replicas)
+	assert.True(t, strings.Contains(env.Servers[0].Deployments[0].Spec.Template.Spec.Containers[0].Image, "/"))
+}
+
+func TestGetDeployment(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+		},
+	}
+	SetDefaults(cr)
+	maxSurge := intstr.FromString("30%")
+	dc := oappsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-dc"},
+		Spec: oappsv1.DeploymentConfigSpec{
+			Replicas: 2,
+			Selector: map[string]string{"deploymentConfig": "test-dc"},
+			Strategy: oappsv1.DeploymentStrategy{
+				Type:          oappsv1.DeploymentStrategyTypeRolling,
+				RollingParams: &oappsv1.RollingDeploymentStrategyParams{MaxSurge: &maxSurge},
+			},
+			Triggers: oappsv1.DeploymentTriggerPolicies{
+				{
+					Type: oappsv1.DeploymentTriggerOnImageChange,
+					ImageChangeParams: &oappsv1.DeploymentTriggerImageChangeParams{
+						ContainerNames: []string{"custom", "tagged"},
+						From:           corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/custom/image:1.0"},
+					},
+				},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "custom", Image: "image:1.0"},
+						{Name: "untouched", Image: "other:1.0"},
+					},
+				},
+			},
+		},
+	}
+
+	deployment := getDeployment(dc, cr)
+	assert.Equal(t, "Deployment", deployment.Kind)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, &maxSurge, deployment.Spec.Strategy.RollingUpdate.MaxSurge)
+	assert.Equal(t, "quay.io/custom/image:1.0", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "other:1.0", deployment.Spec.Template.Spec.Containers[1].Image)
+}
+
+func TestGetImageRegistry(t *testing.T) {
+	cr := &api.KieApp{}
+	assert.Equal(t, constants.ImageRegistry, GetImageRegistry(cr).Registry)
+	assert.Nil(t, cr.Status.Applied.ImageRegistry)
+
+	cr.Status.Applied.ImageRegistry = &api.KieAppRegistry{Insecure: true}
+	registry := GetImageRegistry(cr)
+	assert.Equal(t, constants.ImageRegistry, registry.Registry)
+	assert.True(t, registry.Insecure)
+	assert.Empty(t, cr.Status.Applied.ImageRegistry.Registry, "The applied registry should be left untouched")
+}
@@ -504,6 +504,12 @@ func mergePodSpecs(baseline corev1.PodSpec, overwrite corev1.PodSpec) (corev1.Po
 		return corev1.PodSpec{}, err
 	}
 	overwrite.Volumes = mergedVolumes
+	overwrite.Tolerations = mergeTolerations(baseline.Tolerations, overwrite.Tolerations)
+	overwrite.TopologySpreadConstraints = mergeTopologySpreadConstraints(baseline.TopologySpreadConstraints, overwrite.TopologySpreadConstraints)
+	if overwrite.Affinity != nil {
+		// an affinity is only meaningful as a whole, merging the terms of both would constrain the pods further
+		baseline.Affinity = nil
+	}
 
 	err = mergo.Merge(&baseline, overwrite, mergo.WithOverride)
 	if err != nil {
@@ -512,6 +518,52 @@ func mergePodSpecs(baseline corev1.PodSpec, overwrite corev1.PodSpec) (corev1.Po
 	return baseline, nil
 }
 
+// mergeTolerations replaces the baseline tolerations of the same key and effect, and appends the others
+func mergeTolerations(baseline []corev1.Toleration, overwrite []corev1.Toleration) []corev1.Toleration {
+	var mergedTolerations []corev1.Toleration
+	for baselineIndex, baselineItem := range baseline {
+		idx, found := findToleration(baselineItem, overwrite)
+		if idx == -1 {
+			log.Debugf("Not found, adding %v to slice\n", baselineItem)
+			mergedTolerations = append(mergedTolerations, baseline[baselineIndex])
+		} else {
+			log.Debugf("Will replace %v on top of %v\n", found, baselineItem)
+			mergedTolerations = append(mergedTolerations, found)
+		}
+	}
+	for overwriteIndex, overwriteItem := range overwrite {
+		idx, _ := findToleration(overwriteItem, mergedTolerations)
+		if idx == -1 {
+			log.Debugf("Not found, appending %v to slice\n", overwriteItem)
+			mergedTolerations = append(mergedTolerations, overwrite[overwriteIndex])
+		}
+	}
+	return mergedTolerations
+}
+
+// mergeTopologySpreadConstraints replaces the baseline constraints of the same topology key, and appends the others
+func mergeTopologySpreadConstraints(baseline []corev1.TopologySpreadConstraint, overwrite []corev1.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	var mergedConstraints []corev1.TopologySpreadConstraint
+	for baselineIndex, baselineItem := range baseline {
+		idx, found := findTopologySpreadConstraint(baselineItem, overwrite)
+		if idx == -1 {
+			log.Debugf("Not found, adding %v to slice\n", baselineItem)
+			mergedConstraints = append(mergedConstraints, baseline[baselineIndex])
+		} else {
+			log.Debugf("Will replace %v on top of %v\n", found, baselineItem)
+			mergedConstraints = append(mergedConstraints, found)
+		}
+	}
+	for overwriteIndex, overwriteItem := range overwrite {
+		idx, _ := findTopologySpreadConstraint(overwriteItem, mergedConstraints)
+		if idx == -1 {
+			log.Debugf("Not found, appending %v to slice\n", overwriteItem)
+			mergedConstraints = append(mergedConstraints, overwrite[overwriteIndex])
+		}
+	}
+	return mergedConstraints
+}
+
 func mergeContainers(baseline []corev1.Container, overwrite []corev1.Container) ([]corev1.Container, error) {
 	if len(overwrite) == 0 {
 		return baseline, nil
@@ -630,6 +682,24 @@ func findVolume(object corev1.Volume, slice []corev1.Volume) (int, corev1.Volume
 	return -1, corev1.Volume{}
 }
 
+func findToleration(object corev1.Toleration, slice []corev1.Toleration) (int, corev1.Toleration) {
+	for index, candidate := range slice {
+		if candidate.Key == object.Key && candidate.Effect == object.Effect {
+			return index, candidate
+		}
+	}
+	return -1, corev1.Toleration{}
+}
+
+func findTopologySpreadConstraint(object corev1.TopologySpreadConstraint, slice []corev1.TopologySpreadConstraint) (int, corev1.TopologySpreadConstraint) {
+	for index, candidate := range slice {
+		if candidate.TopologyKey == object.TopologyKey {
+			return index, candidate
+		}
+	}
+	return -1, corev1.TopologySpreadConstraint{}
+}
+
 func findVolumeMount(object corev1.VolumeMount, slice []corev1.VolumeMount) (int, corev1.VolumeMount) {
 	for index, candidate := range slice {
 		if candidate.Name == object.Name {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -469,6 +470,50 @@ func TestMergeDeploymentconfigs_PodSpec_Volumes(t *testing.T) {
 	assert.Equal(t, "/etc/kieserver/dc1/path2", results[0].Spec.Template.Spec.Containers[0].VolumeMounts[1].MountPath)
 }
 
+func TestMergeDeploymentconfigs_PodSpec_Scheduling(t *testing.T) {
+	baseline := []appsv1.DeploymentConfig{
+		*buildDC("dc1"),
+	}
+	baseline[0].Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	baseline[0].Spec.Template.Spec.Tolerations = []corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "kie", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	}
+	baseline[0].Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+		{TopologyKey: "kubernetes.io/hostname", MaxSkew: 1, WhenUnsatisfiable: corev1.ScheduleAnyway},
+	}
+	baseline[0].Spec.Template.Spec.Affinity = &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{},
+	}
+	overwrite := []appsv1.DeploymentConfig{
+		*buildDC("dc1"),
+	}
+	overwrite[0].Spec.Template.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/kie": ""}
+	overwrite[0].Spec.Template.Spec.Tolerations = []corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "kieserver", Effect: corev1.TaintEffectNoSchedule},
+	}
+	overwrite[0].Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+		{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 1, WhenUnsatisfiable: corev1.DoNotSchedule},
+	}
+	overwrite[0].Spec.Template.Spec.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{},
+	}
+	overwrite[0].Spec.Template.Spec.PriorityClassName = "high-priority"
+
+	results := mergeDeploymentConfigs(baseline, overwrite)
+
+	podSpec := results[0].Spec.Template.Spec
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "node-role.kubernetes.io/kie": ""}, podSpec.NodeSelector)
+	assert.Len(t, podSpec.Tolerations, 2)
+	assert.Equal(t, "kieserver", podSpec.Tolerations[0].Value)
+	assert.Equal(t, "spot", podSpec.Tolerations[1].Key)
+	assert.Len(t, podSpec.TopologySpreadConstraints, 2)
+	assert.Equal(t, "kubernetes.io/hostname", podSpec.TopologySpreadConstraints[0].TopologyKey)
+	assert.Equal(t, "topology.kubernetes.io/zone", podSpec.TopologySpreadConstraints[1].TopologyKey)
+	assert.Equal(t, &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}, podSpec.Affinity)
+	assert.Equal(t, "high-priority", podSpec.PriorityClassName)
+}
+
 func TestMergeConfigMaps(t *testing.T) {
 	baseline := []corev1.ConfigMap{
 		*buildConfigMap("overwrite-cm2",
@@ -582,7 +627,7 @@ func getParsedTemplateWithDB(filename string, name string, object interface{}) e
 }
 
 func getParsedTemplateFromCR(cr *api.KieApp, filename string, object interface{}) error {
-	envTemplate, err := getEnvTemplate(cr)
+	envTemplate, err := getEnvTemplate(cr, test.MockService())
 	if err != nil {
 		log.Error("Error getting environment template", err)
 	}
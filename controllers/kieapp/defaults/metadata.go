@@ -0,0 +1,126 @@
+package defaults
+
+import (
+	"reflect"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// objectMetadata holds the labels and annotations added to the resources generated for a component
+type objectMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// SetCommonMetadata adds the common labels and annotations of the CR, along with the ones of each component, to all the
+// resources of the environment and to the pods of their workloads. The labels and annotations already set by the
+// operator, among which the ones selecting the pods, are kept.
+func SetCommonMetadata(env api.Environment, cr *api.KieApp) api.Environment {
+	spec := cr.Status.Applied
+	common := objectMetadata{labels: spec.CommonLabels, annotations: spec.CommonAnnotations}
+	console, dashbuilder, smartRouter, processMigration := common, common, common, common
+	if spec.Objects.Console != nil {
+		console = getComponentMetadata(common, spec.Objects.Console.KieAppObject)
+	}
+	if spec.Objects.Dashbuilder != nil {
+		dashbuilder = getComponentMetadata(common, spec.Objects.Dashbuilder.KieAppObject)
+	}
+	if spec.Objects.SmartRouter != nil {
+		smartRouter = getComponentMetadata(common, spec.Objects.SmartRouter.KieAppObject)
+	}
+	if spec.Objects.ProcessMigration != nil {
+		processMigration = getComponentMetadata(common, spec.Objects.ProcessMigration.KieAppObject)
+	}
+	setObjectMetadata(&env.Console, console)
+	setObjectMetadata(&env.Dashbuilder, dashbuilder)
+	setObjectMetadata(&env.SmartRouter, smartRouter)
+	setObjectMetadata(&env.ProcessMigration, processMigration)
+	for i := range env.Servers {
+		serverSet, _ := GetServerSet(cr, i)
+		setObjectMetadata(&env.Servers[i], getComponentMetadata(common, serverSet.KieAppObject))
+	}
+	for i := range env.Databases {
+		setObjectMetadata(&env.Databases[i], common)
+	}
+	for i := range env.Others {
+		setObjectMetadata(&env.Others[i], common)
+	}
+	return env
+}
+
+// getComponentMetadata returns the common labels and annotations replaced by the ones defined for the component
+func getComponentMetadata(common objectMetadata, appObject api.KieAppObject) objectMetadata {
+	return objectMetadata{
+		labels:      mergeMetadata(common.labels, appObject.PodLabels),
+		annotations: mergeMetadata(common.annotations, appObject.Annotations),
+	}
+}
+
+func mergeMetadata(common, component map[string]string) map[string]string {
+	if len(component) == 0 {
+		return common
+	}
+	merged := make(map[string]string, len(common)+len(component))
+	for key, value := range common {
+		merged[key] = value
+	}
+	for key, value := range component {
+		merged[key] = value
+	}
+	return merged
+}
+
+// setObjectMetadata adds the labels and annotations to every resource of the object, and to the pod templates of its
+// DeploymentConfigs, Deployments and StatefulSets
+func setObjectMetadata(object *api.CustomObject, metadata objectMetadata) {
+	if len(metadata.labels) == 0 && len(metadata.annotations) == 0 {
+		return
+	}
+	objectValue := reflect.ValueOf(object).Elem()
+	for i := 0; i < objectValue.NumField(); i++ {
+		resources := objectValue.Field(i)
+		if resources.Kind() != reflect.Slice {
+			continue
+		}
+		for j := 0; j < resources.Len(); j++ {
+			if resource, ok := resources.Index(j).Addr().Interface().(metav1.Object); ok {
+				resource.SetLabels(addMissing(resource.GetLabels(), metadata.labels))
+				resource.SetAnnotations(addMissing(resource.GetAnnotations(), metadata.annotations))
+			}
+		}
+	}
+	var templates []*corev1.PodTemplateSpec
+	for i := range object.DeploymentConfigs {
+		templates = append(templates, object.DeploymentConfigs[i].Spec.Template)
+	}
+	for i := range object.Deployments {
+		templates = append(templates, &object.Deployments[i].Spec.Template)
+	}
+	for i := range object.StatefulSets {
+		templates = append(templates, &object.StatefulSets[i].Spec.Template)
+	}
+	for _, template := range templates {
+		if template != nil {
+			template.Labels = addMissing(template.Labels, metadata.labels)
+			template.Annotations = addMissing(template.Annotations, metadata.annotations)
+		}
+	}
+}
+
+// addMissing returns a copy of the existing entries along with the added ones whose key is not set yet. The existing
+// map is not modified, as it may be shared by several resources.
+func addMissing(existing, added map[string]string) map[string]string {
+	if len(added) == 0 {
+		return existing
+	}
+	result := make(map[string]string, len(existing)+len(added))
+	for key, value := range added {
+		result[key] = value
+	}
+	for key, value := range existing {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCommonMetadata(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment:       api.RhpamAuthoring,
+			CommonLabels:      map[string]string{"cost-center": "cc-1234", "team": "bpm", "application": "other"},
+			CommonAnnotations: map[string]string{"backup.velero.io/backup-volumes": "data"},
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{KieAppObject: api.KieAppObject{
+					PodLabels:   map[string]string{"team": "decisions"},
+					Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+				}}},
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	env = SetCommonMetadata(ConsolidateObjects(env, cr), cr)
+
+	console := env.Console
+	for _, meta := range []metav1.ObjectMeta{console.DeploymentConfigs[0].ObjectMeta, console.Services[0].ObjectMeta, console.Routes[0].ObjectMeta, console.PersistentVolumeClaims[0].ObjectMeta} {
+		assert.Equal(t, "cc-1234", meta.Labels["cost-center"], meta.Name)
+		assert.Equal(t, "bpm", meta.Labels["team"], meta.Name)
+		assert.Equal(t, "data", meta.Annotations["backup.velero.io/backup-volumes"], meta.Name)
+		assert.Empty(t, meta.Annotations["sidecar.istio.io/inject"], meta.Name)
+	}
+	template := console.DeploymentConfigs[0].Spec.Template
+	assert.Equal(t, "cc-1234", template.Labels["cost-center"])
+	assert.Equal(t, "test", template.Labels["application"], "The labels set by the operator should be kept")
+	assert.Equal(t, constants.ProductName, template.Labels[constants.LabelRHproductName])
+	assert.Equal(t, "data", template.Annotations["backup.velero.io/backup-volumes"])
+
+	server := env.Servers[0]
+	for _, meta := range []metav1.ObjectMeta{server.DeploymentConfigs[0].ObjectMeta, server.DeploymentConfigs[0].Spec.Template.ObjectMeta, server.Services[0].ObjectMeta} {
+		assert.Equal(t, "cc-1234", meta.Labels["cost-center"], meta.Name)
+		assert.Equal(t, "decisions", meta.Labels["team"], meta.Name)
+		assert.Equal(t, "true", meta.Annotations["sidecar.istio.io/inject"], meta.Name)
+		assert.Equal(t, "data", meta.Annotations["backup.velero.io/backup-volumes"], meta.Name)
+	}
+	assert.Equal(t, "bpm", console.DeploymentConfigs[0].Labels["team"], "The labels of a component should not leak to the others")
+}
@@ -0,0 +1,219 @@
+package defaults
+
+import (
+	"fmt"
+	"strings"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/shared"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Paths of the Prometheus endpoints exposed by the monitored components
+const (
+	kieServerMetricsPath   = "/services/rest/metrics"
+	consoleMetricsPath     = "/rest/metrics"
+	smartRouterMetricsPath = "/metrics"
+)
+
+// IsMonitored returns true when ServiceMonitors and a PrometheusRule are generated for the KieApp
+func IsMonitored(cr *api.KieApp) bool {
+	return cr.Status.Applied.Monitoring != nil && cr.Status.Applied.Monitoring.Enabled
+}
+
+// GetMonitoringSecretName returns the name of the Secret holding the user the ServiceMonitors authenticate with
+func GetMonitoringSecretName(cr *api.KieApp) string {
+	return fmt.Sprintf(constants.MonitoringSecret, cr.Name)
+}
+
+// AddMonitoring adds a ServiceMonitor to the console, smartrouter and each kieserver set, enables the Prometheus
+// extension of the kieservers, and adds the default PrometheusRule when it is not disabled
+func AddMonitoring(env api.Environment, cr *api.KieApp) api.Environment {
+	if !IsMonitored(cr) {
+		return env
+	}
+	var workloads []string
+	monitor := func(object *api.CustomObject, path string) {
+		if object.Omit {
+			return
+		}
+		service := getMonitoredService(object.Services)
+		if service == nil {
+			return
+		}
+		object.ServiceMonitors = append(object.ServiceMonitors, getServiceMonitor(*service, path, cr))
+		for _, dc := range object.DeploymentConfigs {
+			workloads = append(workloads, dc.Name)
+		}
+	}
+	monitor(&env.Console, consoleMetricsPath)
+	monitor(&env.SmartRouter, smartRouterMetricsPath)
+	for i := range env.Servers {
+		monitor(&env.Servers[i], kieServerMetricsPath)
+		for j := range env.Servers[i].DeploymentConfigs {
+			if template := env.Servers[i].DeploymentConfigs[j].Spec.Template; template != nil {
+				for k := range template.Spec.Containers {
+					container := &template.Spec.Containers[k]
+					container.Env = shared.EnvOverride(container.Env, []corev1.EnvVar{{Name: "PROMETHEUS_SERVER_EXT_DISABLED", Value: "false"}})
+				}
+			}
+		}
+	}
+	if len(workloads) == 0 {
+		return env
+	}
+	monitoring := api.CustomObject{Secrets: []corev1.Secret{getMonitoringSecret(cr)}}
+	if !cr.Status.Applied.Monitoring.DisableRules {
+		monitoring.PrometheusRules = append(monitoring.PrometheusRules, getPrometheusRule(workloads, cr))
+	}
+	env.Others = append(env.Others, monitoring)
+	return env
+}
+
+// getMonitoredService returns the service of the component, labeled with its own name, that exposes the http port
+func getMonitoredService(services []corev1.Service) *corev1.Service {
+	for i := range services {
+		if services[i].Labels["service"] != services[i].Name {
+			continue
+		}
+		for _, port := range services[i].Spec.Ports {
+			if port.Name == "http" {
+				return &services[i]
+			}
+		}
+	}
+	return nil
+}
+
+func getMonitoringLabels(cr *api.KieApp) map[string]string {
+	labels := map[string]string{
+		"app":         cr.Status.Applied.CommonConfig.ApplicationName,
+		"application": cr.Status.Applied.CommonConfig.ApplicationName,
+	}
+	for key, value := range cr.Status.Applied.Monitoring.Labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// getAdminPasswordSelector returns the Secret key holding the admin password, as referenced by the containers
+func getAdminPasswordSelector(cr *api.KieApp) corev1.SecretKeySelector {
+	if ref := cr.Status.Applied.CommonConfig.AdminPasswordSecretKeyRef; ref != nil {
+		return *ref.DeepCopy()
+	}
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: GetCredentialsSecretName(cr)},
+		Key:                  "adminPassword",
+	}
+}
+
+func getMonitoringSecret(cr *api.KieApp) corev1.Secret {
+	secret := corev1.Secret{
+		Type: corev1.SecretTypeOpaque,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetMonitoringSecretName(cr),
+			Namespace: cr.Namespace,
+			Labels:    getMonitoringLabels(cr),
+		},
+		Data: map[string][]byte{"username": []byte(cr.Status.Applied.CommonConfig.AdminUser)},
+	}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return secret
+}
+
+func getServiceMonitor(service corev1.Service, path string, cr *api.KieApp) monv1.ServiceMonitor {
+	serviceMonitor := monv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service.Name,
+			Namespace: cr.Namespace,
+			Labels:    getMonitoringLabels(cr),
+		},
+		Spec: monv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"service": service.Name}},
+			Endpoints: []monv1.Endpoint{
+				{
+					Port:     "http",
+					Path:     path,
+					Interval: cr.Status.Applied.Monitoring.Interval,
+					BasicAuth: &monv1.BasicAuth{
+						Username: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: GetMonitoringSecretName(cr)},
+							Key:                  "username",
+						},
+						Password: getAdminPasswordSelector(cr),
+					},
+				},
+			},
+		},
+	}
+	serviceMonitor.SetGroupVersionKind(monv1.SchemeGroupVersion.WithKind(monv1.ServiceMonitorsKind))
+	return serviceMonitor
+}
+
+// getPrometheusRule returns the rule alerting when the monitored workloads miss replicas or run out of heap
+func getPrometheusRule(workloads []string, cr *api.KieApp) monv1.PrometheusRule {
+	threshold := int32(constants.DefaultHeapUsageThreshold)
+	if cr.Status.Applied.Monitoring.HeapUsageThreshold != nil {
+		threshold = *cr.Status.Applied.Monitoring.HeapUsageThreshold
+	}
+	names := strings.Join(workloads, "|")
+	unavailable := fmt.Sprintf(`kube_replicationcontroller_spec_replicas{namespace="%[1]s",replicationcontroller=~"(%[2]s)-[0-9]+"} - kube_replicationcontroller_status_ready_replicas{namespace="%[1]s",replicationcontroller=~"(%[2]s)-[0-9]+"} > 0`, cr.Namespace, names)
+	if IsKubernetes(cr) {
+		unavailable = fmt.Sprintf(`kube_deployment_status_replicas_unavailable{namespace="%s",deployment=~"%s"} > 0`, cr.Namespace, names)
+	}
+	heap := fmt.Sprintf(`sum by (namespace, pod) (jvm_memory_bytes_used{area="heap",namespace="%[1]s",job=~"%[2]s"}) / sum by (namespace, pod) (jvm_memory_bytes_max{area="heap",namespace="%[1]s",job=~"%[2]s"}) * 100 > %[3]d`, cr.Namespace, names, threshold)
+	rule := monv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels:    getMonitoringLabels(cr),
+		},
+		Spec: monv1.PrometheusRuleSpec{
+			Groups: []monv1.RuleGroup{
+				{
+					Name: fmt.Sprintf("%s.%s.rules", cr.Namespace, cr.Name),
+					Rules: []monv1.Rule{
+						{
+							Alert: "KieAppReplicasUnavailable",
+							Expr:  intstr.FromString(unavailable),
+							For:   "10m",
+							Labels: map[string]string{
+								"severity": "warning",
+							},
+							Annotations: map[string]string{
+								"summary":     "KieApp replicas are unavailable",
+								"description": fmt.Sprintf("Some replicas of {{ $labels.namespace }}/{{ $labels.%s }} of KieApp %s have not been available for 10 minutes.", getWorkloadLabel(cr), cr.Name),
+							},
+						},
+						{
+							Alert: "KieAppHighHeapUsage",
+							Expr:  intstr.FromString(heap),
+							For:   "15m",
+							Labels: map[string]string{
+								"severity": "warning",
+							},
+							Annotations: map[string]string{
+								"summary":     "KieApp heap usage is high",
+								"description": fmt.Sprintf("Pod {{ $labels.namespace }}/{{ $labels.pod }} of KieApp %s has used more than %d%% of its maximum heap for 15 minutes.", cr.Name, threshold),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	rule.SetGroupVersionKind(monv1.SchemeGroupVersion.WithKind(monv1.PrometheusRuleKind))
+	return rule
+}
+
+// getWorkloadLabel returns the kube-state-metrics label naming the workloads of the replicas alert
+func getWorkloadLabel(cr *api.KieApp) string {
+	if IsKubernetes(cr) {
+		return "deployment"
+	}
+	return "replicationcontroller"
+}
@@ -0,0 +1,119 @@
+package defaults
+
+import (
+	"context"
+	"testing"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/shared"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getMonitoredEnvironment(t *testing.T, cr *api.KieApp) api.Environment {
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	return AddMonitoring(ConsolidateObjects(env, cr), cr)
+}
+
+func TestAddMonitoringDisabled(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec:       api.KieAppSpec{Environment: api.RhpamTrial},
+	}
+	env := getMonitoredEnvironment(t, cr)
+	assert.Empty(t, env.Console.ServiceMonitors)
+	assert.Empty(t, env.Servers[0].ServiceMonitors)
+	for _, other := range env.Others {
+		assert.Empty(t, other.PrometheusRules)
+	}
+}
+
+func TestAddMonitoring(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Monitoring: &api.KieAppMonitoring{
+				Enabled:            true,
+				Interval:           "30s",
+				Labels:             map[string]string{"prometheus": "kie"},
+				HeapUsageThreshold: Pint32(80),
+			},
+		},
+	}
+	env := getMonitoredEnvironment(t, cr)
+
+	assert.Len(t, env.Console.ServiceMonitors, 1)
+	consoleMonitor := env.Console.ServiceMonitors[0]
+	assert.Equal(t, "test-rhpamcentr", consoleMonitor.Name)
+	assert.Equal(t, "kie", consoleMonitor.Labels["prometheus"])
+	assert.Equal(t, map[string]string{"service": "test-rhpamcentr"}, consoleMonitor.Spec.Selector.MatchLabels)
+	assert.Equal(t, consoleMetricsPath, consoleMonitor.Spec.Endpoints[0].Path)
+
+	assert.Len(t, env.Servers[0].ServiceMonitors, 1)
+	serverMonitor := env.Servers[0].ServiceMonitors[0]
+	assert.Equal(t, "test-kieserver", serverMonitor.Name)
+	endpoint := serverMonitor.Spec.Endpoints[0]
+	assert.Equal(t, "http", endpoint.Port)
+	assert.Equal(t, kieServerMetricsPath, endpoint.Path)
+	assert.Equal(t, "30s", endpoint.Interval)
+	assert.Equal(t, &monv1.BasicAuth{
+		Username: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "test-monitoring"}, Key: "username"},
+		Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "test-credentials"}, Key: "adminPassword"},
+	}, endpoint.BasicAuth)
+	container := env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "false", container.Env[shared.GetEnvVar("PROMETHEUS_SERVER_EXT_DISABLED", container.Env)].Value)
+
+	monitoring := env.Others[len(env.Others)-1]
+	assert.Len(t, monitoring.Secrets, 1)
+	assert.Equal(t, "test-monitoring", monitoring.Secrets[0].Name)
+	assert.Equal(t, []byte(cr.Status.Applied.CommonConfig.AdminUser), monitoring.Secrets[0].Data["username"])
+	assert.Len(t, monitoring.PrometheusRules, 1)
+	rules := monitoring.PrometheusRules[0].Spec.Groups[0].Rules
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "KieAppReplicasUnavailable", rules[0].Alert)
+	assert.Contains(t, rules[0].Expr.StrVal, `replicationcontroller=~"(test-rhpamcentr|test-kieserver)-[0-9]+"`)
+	assert.Equal(t, "KieAppHighHeapUsage", rules[1].Alert)
+	assert.Contains(t, rules[1].Expr.StrVal, `job=~"test-rhpamcentr|test-kieserver"`)
+	assert.Contains(t, rules[1].Expr.StrVal, "> 80")
+}
+
+func TestAddMonitoringOnKubernetes(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			CommonConfig: api.CommonConfig{
+				AdminPasswordSecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "admin"},
+					Key:                  "password",
+				},
+			},
+			Monitoring: &api.KieAppMonitoring{Enabled: true, DisableRules: true},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "test-ns"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}))
+	env, err := GetEnvironment(cr, service)
+	assert.Nil(t, err)
+	env = AddMonitoring(ConsolidateObjects(env, cr), cr)
+
+	assert.Equal(t, *cr.Spec.CommonConfig.AdminPasswordSecretKeyRef, env.Servers[0].ServiceMonitors[0].Spec.Endpoints[0].BasicAuth.Password)
+	monitoring := env.Others[len(env.Others)-1]
+	assert.Len(t, monitoring.Secrets, 1)
+	assert.Empty(t, monitoring.PrometheusRules)
+
+	cr.Status.Applied.Monitoring.DisableRules = false
+	rule := getPrometheusRule([]string{"test-kieserver"}, cr)
+	assert.Equal(t, `kube_deployment_status_replicas_unavailable{namespace="test-ns",deployment=~"test-kieserver"} > 0`, rule.Spec.Groups[0].Rules[0].Expr.StrVal)
+	assert.Contains(t, rule.Spec.Groups[0].Rules[1].Expr.StrVal, "> 90")
+}
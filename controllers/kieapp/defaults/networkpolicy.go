@@ -0,0 +1,313 @@
+package defaults
+
+import (
+	"strings"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Labels of the namespaces of the OpenShift ingress controllers and cluster monitoring stack
+var (
+	ingressPolicyGroup    = map[string]string{"network.openshift.io/policy-group": "ingress"}
+	monitoringPolicyGroup = map[string]string{"network.openshift.io/policy-group": "monitoring"}
+)
+
+// componentRole identifies the component a workload belongs to, which decides the components allowed to reach it
+type componentRole int
+
+const (
+	dependencyRole componentRole = iota
+	consoleRole
+	smartRouterRole
+	dashbuilderRole
+	processMigrationRole
+	kieServerRole
+)
+
+// allowedClients are the components calling the services of each component: the console, smartrouter, dashbuilder and
+// process instance migration call the kieservers, which call back the console and smartrouter they register with
+var allowedClients = map[componentRole][]componentRole{
+	kieServerRole:   {consoleRole, smartRouterRole, dashbuilderRole, processMigrationRole},
+	consoleRole:     {kieServerRole, smartRouterRole},
+	smartRouterRole: {kieServerRole},
+}
+
+// isolatedWorkload is a DeploymentConfig or StatefulSet of the environment along with the services selecting its pods
+type isolatedWorkload struct {
+	object   *api.CustomObject
+	meta     metav1.ObjectMeta
+	selector map[string]string
+	template *corev1.PodTemplateSpec
+	services []corev1.Service
+	role     componentRole
+	// the workload using this dependency, e.g. the console using its datagrid and AMQ
+	owner *isolatedWorkload
+	// whether the workloads of the same object reach each other, as those of a kieserver set do
+	grouped bool
+	peers   []networkingv1.NetworkPolicyPeer
+}
+
+// IsNetworkIsolated returns true when NetworkPolicies are generated for the KieApp
+func IsNetworkIsolated(cr *api.KieApp) bool {
+	return cr.Status.Applied.NetworkPolicy != nil && cr.Status.Applied.NetworkPolicy.Enabled
+}
+
+// AddNetworkPolicies adds a NetworkPolicy for every DeploymentConfig and StatefulSet of the environment. Each policy
+// allows the traffic between the pods of the workload, and the traffic to the ports of its services from:
+//   - the console, smartrouter, dashbuilder and process instance migration, for the kieservers
+//   - the kieservers, for the console and smartrouter they register with
+//   - the other workloads of the same kieserver set, e.g. its broker and database
+//   - the console, for its datagrid and AMQ, and the kieservers or process instance migration owning a database
+//   - the router namespaces when one of its services is exposed, and the monitoring namespaces when it is monitored
+//   - the peers configured for the component
+func AddNetworkPolicies(env api.Environment, cr *api.KieApp) api.Environment {
+	if !IsNetworkIsolated(cr) {
+		return env
+	}
+	var workloads []*isolatedWorkload
+	var services []corev1.Service
+	exposed := map[string]bool{}
+	collect := func(object *api.CustomObject, role componentRole, grouped bool, peers []networkingv1.NetworkPolicyPeer) []*isolatedWorkload {
+		if object.Omit {
+			return nil
+		}
+		services = append(services, object.Services...)
+		for name := range getExposedServices(*object) {
+			exposed[name] = true
+		}
+		var collected []*isolatedWorkload
+		for _, dc := range object.DeploymentConfigs {
+			if dc.Spec.Template == nil {
+				continue
+			}
+			collected = append(collected, &isolatedWorkload{
+				object:   object,
+				meta:     dc.ObjectMeta,
+				selector: getPodSelector(dc.Spec.Selector, dc.Spec.Template),
+				template: dc.Spec.Template,
+				role:     role,
+				grouped:  grouped,
+				peers:    peers,
+			})
+		}
+		for i := range object.StatefulSets {
+			statefulSet := &object.StatefulSets[i]
+			var selector map[string]string
+			if statefulSet.Spec.Selector != nil {
+				selector = statefulSet.Spec.Selector.MatchLabels
+			}
+			collected = append(collected, &isolatedWorkload{
+				object:   object,
+				meta:     statefulSet.ObjectMeta,
+				selector: getPodSelector(selector, &statefulSet.Spec.Template),
+				template: &statefulSet.Spec.Template,
+				role:     role,
+				grouped:  grouped,
+				peers:    peers,
+			})
+		}
+		workloads = append(workloads, collected...)
+		return collected
+	}
+	objects := cr.Status.Applied.Objects
+	var peers []networkingv1.NetworkPolicyPeer
+	if objects.Console != nil {
+		peers = objects.Console.NetworkPolicyPeers
+	}
+	consoles := collect(&env.Console, consoleRole, false, peers)
+	peers = nil
+	if objects.SmartRouter != nil {
+		peers = objects.SmartRouter.NetworkPolicyPeers
+	}
+	collect(&env.SmartRouter, smartRouterRole, false, peers)
+	peers = nil
+	if objects.Dashbuilder != nil {
+		peers = objects.Dashbuilder.NetworkPolicyPeers
+	}
+	collect(&env.Dashbuilder, dashbuilderRole, false, peers)
+	peers = nil
+	if objects.ProcessMigration != nil {
+		peers = objects.ProcessMigration.NetworkPolicyPeers
+	}
+	owners := collect(&env.ProcessMigration, processMigrationRole, false, peers)
+	for i := range env.Servers {
+		serverSet, kieDeploymentName := GetServerSet(cr, i)
+		for _, workload := range collect(&env.Servers[i], dependencyRole, true, serverSet.NetworkPolicyPeers) {
+			if workload.meta.Name == kieDeploymentName {
+				workload.role = kieServerRole
+				owners = append(owners, workload)
+			}
+		}
+	}
+	// the databases are named after the kieserver or process instance migration using them
+	for i := range env.Databases {
+		for _, workload := range collect(&env.Databases[i], dependencyRole, false, nil) {
+			for _, owner := range owners {
+				if strings.HasPrefix(workload.meta.Name, owner.meta.Name+"-") && (workload.owner == nil || len(owner.meta.Name) > len(workload.owner.meta.Name)) {
+					workload.owner = owner
+				}
+			}
+		}
+	}
+	// the other workloads are the datagrid and AMQ of the console
+	for i := range env.Others {
+		for _, workload := range collect(&env.Others[i], dependencyRole, false, nil) {
+			if len(consoles) > 0 {
+				workload.owner = consoles[0]
+			}
+		}
+	}
+
+	for _, workload := range workloads {
+		for _, service := range services {
+			if len(service.Spec.Selector) > 0 && labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(workload.template.Labels)) {
+				workload.services = append(workload.services, service)
+			}
+		}
+	}
+	for _, workload := range workloads {
+		workload.object.NetworkPolicies = append(workload.object.NetworkPolicies, getNetworkPolicy(workload, workloads, exposed, cr))
+	}
+	return env
+}
+
+// getExposedServices returns the names of the services targeted by the Routes, Ingresses and HTTPRoutes of the object
+func getExposedServices(object api.CustomObject) map[string]bool {
+	names := map[string]bool{}
+	for _, route := range object.Routes {
+		names[route.Spec.To.Name] = true
+	}
+	for _, ingress := range object.Ingresses {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					names[path.Backend.Service.Name] = true
+				}
+			}
+		}
+	}
+	for _, httpRoute := range object.HTTPRoutes {
+		for _, rule := range httpRoute.Spec.Rules {
+			for _, forwardTo := range rule.ForwardTo {
+				if forwardTo.ServiceName != nil {
+					names[*forwardTo.ServiceName] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// allows returns true when the client workload calls the services of the target workload
+func (target *isolatedWorkload) allows(client *isolatedWorkload) bool {
+	if target.grouped && client.object == target.object {
+		return true
+	}
+	if target.owner != nil {
+		return client == target.owner
+	}
+	for _, role := range allowedClients[target.role] {
+		if client.role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func getNetworkPolicy(workload *isolatedWorkload, workloads []*isolatedWorkload, exposed map[string]bool, cr *api.KieApp) networkingv1.NetworkPolicy {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, client := range workloads {
+		if client == workload {
+			continue
+		}
+		if workload.allows(client) {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: client.selector}})
+		}
+	}
+	for _, service := range workload.services {
+		if !exposed[service.Name] {
+			continue
+		}
+		if selector := getRouterNamespaceSelector(cr); selector != nil {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{NamespaceSelector: selector})
+		} else {
+			log.Warnf("No routerNamespaceSelector set, the ingress controller is not allowed to reach %s", workload.meta.Name)
+		}
+		break
+	}
+	if len(workload.object.ServiceMonitors) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: monitoringPolicyGroup}})
+	}
+	for _, peer := range workload.peers {
+		peers = append(peers, *peer.DeepCopy())
+	}
+
+	policyLabels := map[string]string{}
+	for key, value := range workload.meta.Labels {
+		policyLabels[key] = value
+	}
+	networkPolicy := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workload.meta.Name,
+			Namespace: cr.Namespace,
+			Labels:    policyLabels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: workload.selector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: workload.selector}}}},
+			},
+		},
+	}
+	if len(peers) > 0 {
+		networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: getNetworkPolicyPorts(workload.services),
+			From:  peers,
+		})
+	}
+	networkPolicy.SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+	return networkPolicy
+}
+
+// getNetworkPolicyPorts returns the container ports targeted by the services, no ports allowing them all
+func getNetworkPolicyPorts(services []corev1.Service) []networkingv1.NetworkPolicyPort {
+	var ports []networkingv1.NetworkPolicyPort
+	added := map[string]bool{}
+	for _, service := range services {
+		for _, servicePort := range service.Spec.Ports {
+			target := servicePort.TargetPort
+			if target.IntValue() == 0 && target.Type == intstr.Int {
+				target = intstr.FromInt(int(servicePort.Port))
+			}
+			protocol := servicePort.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			if key := string(protocol) + "/" + target.String(); !added[key] {
+				added[key] = true
+				ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &target})
+			}
+		}
+	}
+	return ports
+}
+
+// getRouterNamespaceSelector returns the selector of the router namespaces, which defaults to the OpenShift ingress
+// policy group. It is nil on Kubernetes when none is configured, as no label is common to its ingress controllers.
+func getRouterNamespaceSelector(cr *api.KieApp) *metav1.LabelSelector {
+	if selector := cr.Status.Applied.NetworkPolicy.RouterNamespaceSelector; selector != nil {
+		return selector.DeepCopy()
+	} else if IsKubernetes(cr) {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: ingressPolicyGroup}
+}
@@ -0,0 +1,160 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getNetworkPolicies(t *testing.T, cr *api.KieApp) map[string]networkingv1.NetworkPolicy {
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	env = AddNetworkPolicies(AddMonitoring(ConsolidateObjects(env, cr), cr), cr)
+	networkPolicies := map[string]networkingv1.NetworkPolicy{}
+	objects := append([]api.CustomObject{env.Console, env.SmartRouter, env.Dashbuilder, env.ProcessMigration}, env.Servers...)
+	objects = append(objects, env.Databases...)
+	for _, object := range append(objects, env.Others...) {
+		for _, networkPolicy := range object.NetworkPolicies {
+			networkPolicies[networkPolicy.Name] = networkPolicy
+		}
+	}
+	return networkPolicies
+}
+
+// getAllowedPeers returns the peers allowed to reach the ports of the services of the pods, by their selected labels
+func getAllowedPeers(networkPolicy networkingv1.NetworkPolicy) []map[string]string {
+	var peers []map[string]string
+	for _, rule := range networkPolicy.Spec.Ingress[1:] {
+		for _, peer := range rule.From {
+			if peer.PodSelector != nil {
+				peers = append(peers, peer.PodSelector.MatchLabels)
+			} else {
+				peers = append(peers, peer.NamespaceSelector.MatchLabels)
+			}
+		}
+	}
+	return peers
+}
+
+func TestAddNetworkPoliciesDisabled(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec:       api.KieAppSpec{Environment: api.RhpamAuthoringHA},
+	}
+	assert.Empty(t, getNetworkPolicies(t, cr))
+}
+
+func TestAddNetworkPoliciesAuthoringHA(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment:   api.RhpamAuthoringHA,
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true},
+		},
+	}
+	networkPolicies := getNetworkPolicies(t, cr)
+	assert.Len(t, networkPolicies, 5)
+
+	console := networkPolicies["test-rhpamcentr"]
+	assert.Equal(t, "test-ns", console.Namespace)
+	assert.Equal(t, map[string]string{"deploymentConfig": "test-rhpamcentr"}, console.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, console.Spec.PolicyTypes)
+	assert.Equal(t, &metav1.LabelSelector{MatchLabels: map[string]string{"deploymentConfig": "test-rhpamcentr"}}, console.Spec.Ingress[0].From[0].PodSelector)
+	assert.Empty(t, console.Spec.Ingress[0].Ports)
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-kieserver"}, ingressPolicyGroup}, getAllowedPeers(console))
+	var ports []string
+	for _, port := range console.Spec.Ingress[1].Ports {
+		ports = append(ports, port.Port.String())
+	}
+	assert.ElementsMatch(t, []string{"8080", "8443"}, ports)
+
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-rhpamcentr"}, ingressPolicyGroup}, getAllowedPeers(networkPolicies["test-kieserver"]))
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-kieserver"}}, getAllowedPeers(networkPolicies["test-kieserver-mysql"]))
+	assert.Equal(t, "3306", networkPolicies["test-kieserver-mysql"].Spec.Ingress[1].Ports[0].Port.String())
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-rhpamcentr"}}, getAllowedPeers(networkPolicies["test-datagrid"]))
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-rhpamcentr"}}, getAllowedPeers(networkPolicies["test-amq"]))
+}
+
+func TestAddNetworkPoliciesConfigured(t *testing.T) {
+	routerSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}}
+	gateway := networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "gateway"}}}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{
+					KieAppObject: api.KieAppObject{NetworkPolicyPeers: []networkingv1.NetworkPolicyPeer{gateway}},
+					Jms:          &api.KieAppJmsObject{EnableIntegration: true},
+				}},
+			},
+			Monitoring:    &api.KieAppMonitoring{Enabled: true},
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true, RouterNamespaceSelector: routerSelector},
+		},
+	}
+	networkPolicies := getNetworkPolicies(t, cr)
+	assert.Equal(t, []map[string]string{
+		{"deploymentConfig": "test-rhpamcentrmon"},
+		{"deploymentConfig": "test-kieserver-amq"},
+		routerSelector.MatchLabels,
+		monitoringPolicyGroup,
+		gateway.NamespaceSelector.MatchLabels,
+	}, getAllowedPeers(networkPolicies["test-kieserver"]))
+	assert.Contains(t, getAllowedPeers(networkPolicies["test-kieserver-amq"]), map[string]string{"deploymentConfig": "test-kieserver"})
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-kieserver"}}, getAllowedPeers(networkPolicies["test-kieserver-postgresql"]))
+}
+
+func TestAddNetworkPoliciesProcessMigration(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamAuthoring,
+			Objects: api.KieAppObjects{
+				ProcessMigration: &api.ProcessMigrationObject{
+					Database: api.ProcessMigrationDatabaseObject{InternalDatabaseObject: api.InternalDatabaseObject{Type: api.DatabaseMySQL}},
+				},
+			},
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true},
+		},
+	}
+	networkPolicies := getNetworkPolicies(t, cr)
+	assert.Contains(t, getAllowedPeers(networkPolicies["test-kieserver"]), map[string]string{"deploymentConfig": "test-process-migration"})
+	assert.Equal(t, []map[string]string{ingressPolicyGroup}, getAllowedPeers(networkPolicies["test-process-migration"]))
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-process-migration"}}, getAllowedPeers(networkPolicies["test-process-migration-mysql"]))
+}
+
+func TestAddNetworkPoliciesDashbuilder(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamAuthoring,
+			Objects: api.KieAppObjects{
+				Dashbuilder: &api.DashbuilderObject{},
+			},
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true},
+		},
+	}
+	networkPolicies := getNetworkPolicies(t, cr)
+	assert.Contains(t, getAllowedPeers(networkPolicies["test-kieserver"]), map[string]string{"deploymentConfig": "test-rhpamdash"})
+	assert.Equal(t, []map[string]string{ingressPolicyGroup}, getAllowedPeers(networkPolicies["test-rhpamdash"]))
+	assert.NotContains(t, getAllowedPeers(networkPolicies["test-rhpamcentr"]), map[string]string{"deploymentConfig": "test-rhpamdash"})
+}
+
+func TestAddNetworkPoliciesKubernetes(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment:   api.RhpamTrial,
+			Exposure:      &api.KieAppExposure{Domain: "apps.example.com"},
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true},
+		},
+	}
+	cr.Status.Applied.Platform = api.KubernetesPlatform
+	networkPolicies := getNetworkPolicies(t, cr)
+	assert.Equal(t, []map[string]string{{"deploymentConfig": "test-kieserver"}}, getAllowedPeers(networkPolicies["test-rhpamcentr"]),
+		"The OpenShift ingress policy group should not be assumed on Kubernetes")
+}
@@ -0,0 +1,147 @@
+package defaults
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// overlayComponent holds the objects of the environment generated for a component, by the name used in overlay targets
+type overlayComponent struct {
+	name    string
+	objects []*api.CustomObject
+}
+
+// ApplyOverlays patches the resources of the environment with the overlays of the CR, in order, and returns the
+// resources each overlay was applied to
+func ApplyOverlays(env api.Environment, cr *api.KieApp) (api.Environment, []api.AppliedOverlay, error) {
+	overlays := cr.Status.Applied.Overlays
+	if len(overlays) == 0 {
+		return env, nil, nil
+	}
+	components := []overlayComponent{
+		{name: "console", objects: []*api.CustomObject{&env.Console}},
+		{name: "smartRouter", objects: []*api.CustomObject{&env.SmartRouter}},
+		{name: "processMigration", objects: []*api.CustomObject{&env.ProcessMigration}},
+		{name: "dashbuilder", objects: []*api.CustomObject{&env.Dashbuilder}},
+		{name: "servers"},
+		{name: "databases"},
+		{name: "others"},
+	}
+	for i := range env.Servers {
+		components[4].objects = append(components[4].objects, &env.Servers[i])
+	}
+	for i := range env.Databases {
+		components[5].objects = append(components[5].objects, &env.Databases[i])
+	}
+	for i := range env.Others {
+		components[6].objects = append(components[6].objects, &env.Others[i])
+	}
+
+	var applied []api.AppliedOverlay
+	for index, overlay := range overlays {
+		patch, err := getOverlayPatch(overlay)
+		if err != nil {
+			return env, nil, fmt.Errorf("invalid patch of overlay %d: %v", index, err)
+		}
+		for _, component := range components {
+			if overlay.Target.Component != "" && overlay.Target.Component != component.name {
+				continue
+			}
+			for _, object := range component.objects {
+				if object.Omit {
+					continue
+				}
+				patched, err := applyOverlay(object, index, overlay, patch)
+				applied = append(applied, patched...)
+				if err != nil {
+					return env, nil, fmt.Errorf("failed to apply overlay %d: %v", index, err)
+				}
+			}
+		}
+	}
+	return env, applied, nil
+}
+
+// getOverlayPatch returns the JSON document of the patch of the overlay, decoded when it is a JSON patch
+func getOverlayPatch(overlay api.KieAppOverlay) (interface{}, error) {
+	if overlay.StrategicMergePatch != "" && overlay.JSONPatch != "" {
+		return nil, fmt.Errorf("strategicMergePatch and jsonPatch cannot be both set")
+	}
+	if overlay.JSONPatch != "" {
+		patch, err := yaml.YAMLToJSON([]byte(overlay.JSONPatch))
+		if err != nil {
+			return nil, err
+		}
+		return jsonpatch.DecodePatch(patch)
+	}
+	if overlay.StrategicMergePatch != "" {
+		patch, err := yaml.YAMLToJSON([]byte(overlay.StrategicMergePatch))
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(patch, &fields); err != nil {
+			return nil, err
+		}
+		return patch, nil
+	}
+	return nil, fmt.Errorf("either strategicMergePatch or jsonPatch must be set")
+}
+
+// applyOverlay patches the resources of the object selected by the target of the overlay, and returns them. The kind of
+// a resource is the name of its type.
+func applyOverlay(object *api.CustomObject, index int, overlay api.KieAppOverlay, patch interface{}) ([]api.AppliedOverlay, error) {
+	var patched []api.AppliedOverlay
+	objectValue := reflect.ValueOf(object).Elem()
+	for i := 0; i < objectValue.NumField(); i++ {
+		resources := objectValue.Field(i)
+		if resources.Kind() != reflect.Slice {
+			continue
+		}
+		resourceType := resources.Type().Elem()
+		if overlay.Target.Kind != "" && overlay.Target.Kind != resourceType.Name() {
+			continue
+		}
+		for j := 0; j < resources.Len(); j++ {
+			resource, ok := resources.Index(j).Addr().Interface().(metav1.Object)
+			if !ok {
+				continue
+			}
+			name := resource.GetName()
+			if overlay.Target.Name != "" {
+				if matched, _ := path.Match(overlay.Target.Name, name); !matched {
+					continue
+				}
+			}
+			original, err := json.Marshal(resource)
+			if err != nil {
+				return patched, err
+			}
+			var modified []byte
+			switch patch := patch.(type) {
+			case jsonpatch.Patch:
+				modified, err = patch.Apply(original)
+			case []byte:
+				modified, err = strategicpatch.StrategicMergePatch(original, patch, reflect.New(resourceType).Interface())
+			}
+			if err != nil {
+				return patched, fmt.Errorf("%s %s: %v", resourceType.Name(), name, err)
+			}
+			result := reflect.New(resourceType)
+			if err := json.Unmarshal(modified, result.Interface()); err != nil {
+				return patched, fmt.Errorf("%s %s: %v", resourceType.Name(), name, err)
+			}
+			resources.Index(j).Set(result.Elem())
+			patched = append(patched, api.AppliedOverlay{Index: index, Kind: resourceType.Name(), Name: name})
+		}
+	}
+	return patched, nil
+}
@@ -0,0 +1,114 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getOverlayEnvironment(t *testing.T, cr *api.KieApp) api.Environment {
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	return ConsolidateObjects(env, cr)
+}
+
+func TestApplyOverlaysStrategicMergePatch(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Overlays: []api.KieAppOverlay{
+				{
+					Target: api.KieAppOverlayTarget{Kind: "DeploymentConfig", Component: "servers"},
+					StrategicMergePatch: `
+spec:
+  template:
+    spec:
+      containers:
+        - name: test-kieserver
+          lifecycle:
+            preStop:
+              exec:
+                command: ["/bin/sh", "-c", "sleep 10"]`,
+				},
+				{
+					Target:              api.KieAppOverlayTarget{Kind: "Service", Name: "*-rhpamcentr*"},
+					StrategicMergePatch: `{"metadata": {"annotations": {"example.com/owner": "kie"}}}`,
+				},
+			},
+		},
+	}
+	env := getOverlayEnvironment(t, cr)
+	env, applied, err := ApplyOverlays(env, cr)
+	assert.Nil(t, err)
+	assert.Equal(t, []api.AppliedOverlay{
+		{Index: 0, Kind: "DeploymentConfig", Name: "test-kieserver"},
+		{Index: 1, Kind: "Service", Name: "test-rhpamcentr"},
+	}, applied)
+
+	containers := env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Containers
+	assert.Len(t, containers, 1)
+	assert.Equal(t, "test-kieserver", containers[0].Name)
+	assert.NotEmpty(t, containers[0].Image, "Fields missing from the patch should be kept")
+	assert.Equal(t, []string{"/bin/sh", "-c", "sleep 10"}, containers[0].Lifecycle.PreStop.Exec.Command)
+	assert.Nil(t, env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0].Lifecycle)
+	assert.Equal(t, "kie", env.Console.Services[0].Annotations["example.com/owner"])
+	assert.Empty(t, env.Servers[0].Services[0].Annotations["example.com/owner"])
+}
+
+func TestApplyOverlaysJSONPatch(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Overlays: []api.KieAppOverlay{{
+				Target: api.KieAppOverlayTarget{Kind: "DeploymentConfig", Name: "test-rhpamcentr"},
+				JSONPatch: `
+- op: replace
+  path: /spec/template/spec/containers/0/readinessProbe/initialDelaySeconds
+  value: 120
+- op: remove
+  path: /spec/template/spec/containers/0/livenessProbe`,
+			}},
+		},
+	}
+	env := getOverlayEnvironment(t, cr)
+	env, applied, err := ApplyOverlays(env, cr)
+	assert.Nil(t, err)
+	assert.Equal(t, []api.AppliedOverlay{{Index: 0, Kind: "DeploymentConfig", Name: "test-rhpamcentr"}}, applied)
+	container := env.Console.DeploymentConfigs[0].Spec.Template.Spec.Containers[0]
+	assert.Equal(t, int32(120), container.ReadinessProbe.InitialDelaySeconds)
+	assert.Nil(t, container.LivenessProbe)
+
+	cr.Spec.Overlays[0].JSONPatch = `[{"op": "test", "path": "/spec/replicas", "value": 5}]`
+	env = getOverlayEnvironment(t, cr)
+	_, _, err = ApplyOverlays(env, cr)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "DeploymentConfig test-rhpamcentr")
+}
+
+func TestApplyOverlaysConverted(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Overlays: []api.KieAppOverlay{{
+				Target:              api.KieAppOverlayTarget{Kind: "Deployment"},
+				StrategicMergePatch: `{"spec": {"template": {"spec": {"dnsPolicy": "None"}}}}`,
+			}},
+		},
+	}
+	env := ConvertToKubernetes(getOverlayEnvironment(t, cr), cr)
+	env, applied, err := ApplyOverlays(env, cr)
+	assert.Nil(t, err)
+	assert.Len(t, applied, 2)
+	for _, deployment := range append(env.Console.Deployments, env.Servers[0].Deployments...) {
+		assert.Equal(t, corev1.DNSNone, deployment.Spec.Template.Spec.DNSPolicy)
+	}
+}
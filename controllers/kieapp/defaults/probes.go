@@ -0,0 +1,118 @@
+package defaults
+
+import (
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// setProbes overrides the probes of the main container of the DeploymentConfigs and StatefulSets of the components and
+// databases with the ones defined in the CR. The brokers of the kieservers keep the probes of their template.
+func setProbes(env api.Environment, cr *api.KieApp) api.Environment {
+	objects := cr.Status.Applied.Objects
+	databases := map[string]*api.KieAppProbes{}
+	if objects.Console != nil {
+		setObjectProbes(&env.Console, nil, objects.Console.Probes)
+	}
+	if objects.Dashbuilder != nil {
+		setObjectProbes(&env.Dashbuilder, nil, objects.Dashbuilder.Probes)
+	}
+	if objects.SmartRouter != nil {
+		setObjectProbes(&env.SmartRouter, nil, objects.SmartRouter.Probes)
+	}
+	if objects.ProcessMigration != nil {
+		setObjectProbes(&env.ProcessMigration, nil, objects.ProcessMigration.Probes)
+		database := objects.ProcessMigration.Database.InternalDatabaseObject
+		databases[cr.Name+"-process-migration-"+string(database.Type)] = database.Probes
+	}
+	for i := range env.Servers {
+		serverSet, kieName := GetServerSet(cr, i)
+		if serverSet.Database != nil {
+			databases[kieName+"-"+string(serverSet.Database.Type)] = serverSet.Database.Probes
+		}
+		setObjectProbes(&env.Servers[i], map[string]*api.KieAppProbes{kieName: serverSet.Probes}, nil)
+	}
+	for i := range env.Databases {
+		setObjectProbes(&env.Databases[i], databases, nil)
+	}
+	return env
+}
+
+// setObjectProbes sets the probes found for the name of each DeploymentConfig and StatefulSet of the object, or else
+// the default ones
+func setObjectProbes(object *api.CustomObject, named map[string]*api.KieAppProbes, defaultProbes *api.KieAppProbes) {
+	getProbes := func(name string) *api.KieAppProbes {
+		if probes, found := named[name]; found {
+			return probes
+		}
+		return defaultProbes
+	}
+	for i := range object.DeploymentConfigs {
+		dc := &object.DeploymentConfigs[i]
+		if dc.Spec.Template != nil {
+			setContainerProbes(dc.Spec.Template.Spec.Containers, getProbes(dc.Name))
+		}
+	}
+	for i := range object.StatefulSets {
+		statefulSet := &object.StatefulSets[i]
+		setContainerProbes(statefulSet.Spec.Template.Spec.Containers, getProbes(statefulSet.Name))
+	}
+}
+
+func setContainerProbes(containers []corev1.Container, probes *api.KieAppProbes) {
+	if probes == nil || len(containers) == 0 {
+		return
+	}
+	container := &containers[0]
+	container.LivenessProbe = mergeProbe(container.LivenessProbe, probes.Liveness)
+	container.ReadinessProbe = mergeProbe(container.ReadinessProbe, probes.Readiness)
+	container.StartupProbe = mergeProbe(container.StartupProbe, probes.Startup)
+}
+
+// mergeProbe returns the probe replaced by the custom one of the override, with the timings of the override. Timings
+// are ignored when there is no probe to apply them to.
+func mergeProbe(probe *corev1.Probe, override *api.KieAppProbe) *corev1.Probe {
+	if override == nil {
+		return probe
+	}
+	if override.Custom != nil {
+		probe = override.Custom.DeepCopy()
+		if probe.HTTPGet != nil && probe.HTTPGet.Scheme == "" {
+			probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+		}
+	}
+	if probe == nil {
+		log.Warn("Probe timings are ignored, as the container has no such probe and no custom one is defined")
+		return nil
+	}
+	if override.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *override.InitialDelaySeconds
+	}
+	if override.PeriodSeconds != nil {
+		probe.PeriodSeconds = *override.PeriodSeconds
+	}
+	if override.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *override.TimeoutSeconds
+	}
+	if override.FailureThreshold != nil {
+		probe.FailureThreshold = *override.FailureThreshold
+	}
+	setProbeDefaults(probe)
+	return probe
+}
+
+// setProbeDefaults sets the timings left unset to the defaults of the API server, so the probes are not found to differ
+// from the deployed ones on every reconcile
+func setProbeDefaults(probe *corev1.Probe) {
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+}
@@ -0,0 +1,92 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestSetProbes(t *testing.T) {
+	startup := &corev1.Probe{
+		Handler:          corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/services/rest/server/readycheck", Port: intstr.FromInt(8080)}},
+		PeriodSeconds:    10,
+		FailureThreshold: 60,
+	}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{Probes: &api.KieAppProbes{
+					Liveness: &api.KieAppProbe{InitialDelaySeconds: Pint32(300)},
+				}}},
+				Servers: []api.KieServerSet{{
+					KieAppObject: api.KieAppObject{
+						Probes: &api.KieAppProbes{
+							Readiness: &api.KieAppProbe{FailureThreshold: Pint32(100), TimeoutSeconds: Pint32(5)},
+							Startup:   &api.KieAppProbe{Custom: startup, InitialDelaySeconds: Pint32(30)},
+						},
+						Sidecars: []corev1.Container{{Name: "log-shipper", Image: "fluent-bit"}},
+					},
+					Database: &api.DatabaseObject{InternalDatabaseObject: api.InternalDatabaseObject{
+						Type:   api.DatabasePostgreSQL,
+						Probes: &api.KieAppProbes{Liveness: &api.KieAppProbe{PeriodSeconds: Pint32(30)}},
+					}},
+					Jms: &api.KieAppJmsObject{EnableIntegration: true},
+				}},
+			},
+		},
+	}
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	env = ConsolidateObjects(env, cr)
+	templates := getPodTemplates(env)
+
+	console := templates["test-rhpamcentrmon"].Spec.Containers[0]
+	assert.Equal(t, int32(300), console.LivenessProbe.InitialDelaySeconds)
+	assert.Equal(t, "/rest/healthy", console.LivenessProbe.HTTPGet.Path, "The handler of the template should be kept")
+
+	server := templates["test-kieserver"].Spec.Containers[0]
+	assert.Equal(t, int32(100), server.ReadinessProbe.FailureThreshold)
+	assert.Equal(t, int32(5), server.ReadinessProbe.TimeoutSeconds)
+	assert.Equal(t, int32(5), server.ReadinessProbe.PeriodSeconds)
+	assert.Equal(t, int32(30), server.StartupProbe.InitialDelaySeconds)
+	assert.Equal(t, int32(60), server.StartupProbe.FailureThreshold)
+	assert.Equal(t, corev1.URISchemeHTTP, server.StartupProbe.HTTPGet.Scheme)
+	assert.Equal(t, int32(1), server.StartupProbe.TimeoutSeconds, "The custom probe should get the defaults of the API server")
+	assert.Equal(t, int32(1), server.StartupProbe.SuccessThreshold)
+	assert.Empty(t, startup.InitialDelaySeconds, "The CR should not be modified")
+	assert.Nil(t, templates["test-kieserver"].Spec.Containers[1].ReadinessProbe, "Sidecars keep their probes")
+
+	broker := templates["test-kieserver-amq"].Spec.Containers[0]
+	assert.Nil(t, broker.StartupProbe)
+	assert.NotEqual(t, int32(100), broker.ReadinessProbe.FailureThreshold)
+
+	database := templates["test-kieserver-postgresql"].Spec.Containers[0]
+	assert.Equal(t, int32(30), database.LivenessProbe.PeriodSeconds)
+	assert.Equal(t, int32(120), database.LivenessProbe.InitialDelaySeconds)
+}
+
+func TestMergeProbeWithoutProbe(t *testing.T) {
+	assert.Nil(t, mergeProbe(nil, &api.KieAppProbe{PeriodSeconds: Pint32(10)}))
+	probe := &corev1.Probe{PeriodSeconds: 5}
+	assert.Equal(t, probe, mergeProbe(probe, nil))
+}
+
+func TestMergeProbeDefaults(t *testing.T) {
+	probe := mergeProbe(nil, &api.KieAppProbe{Custom: &corev1.Probe{
+		Handler: corev1.Handler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)}},
+	}})
+	assert.Equal(t, int32(10), probe.PeriodSeconds)
+	assert.Equal(t, int32(1), probe.TimeoutSeconds)
+	assert.Equal(t, int32(1), probe.SuccessThreshold)
+	assert.Equal(t, int32(3), probe.FailureThreshold)
+
+	probe = mergeProbe(&corev1.Probe{PeriodSeconds: 5, FailureThreshold: 60}, &api.KieAppProbe{TimeoutSeconds: Pint32(2)})
+	assert.Equal(t, &corev1.Probe{PeriodSeconds: 5, TimeoutSeconds: 2, SuccessThreshold: 1, FailureThreshold: 60}, probe)
+}
@@ -0,0 +1,284 @@
+package defaults
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/imdario/mergo"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Volume types and capabilities allowed by the restricted Pod Security standard
+var (
+	restrictedVolumeTypes       = []string{"configMap", "csi", "downwardAPI", "emptyDir", "ephemeral", "persistentVolumeClaim", "projected", "secret"}
+	restrictedAddedCapabilities = map[corev1.Capability]bool{"NET_BIND_SERVICE": true}
+)
+
+// securityOverrides are the pod and container security contexts defined in the CR for a workload
+type securityOverrides struct {
+	pod       *corev1.PodSecurityContext
+	container *corev1.SecurityContext
+}
+
+// IsRestricted returns true when the restricted security context preset is applied to the KieApp
+func IsRestricted(cr *api.KieApp) bool {
+	return cr.Status.Applied.SecurityContextPreset == api.RestrictedSecurityContextPreset
+}
+
+// SetSecurityContexts applies the security context preset to the pods and containers of all the DeploymentConfigs and
+// StatefulSets of the environment, then the security contexts defined for their component, database or broker. Fields
+// set in the CR replace the ones of the preset.
+func SetSecurityContexts(env api.Environment, cr *api.KieApp) api.Environment {
+	objects := cr.Status.Applied.Objects
+	var console, dashbuilder, smartRouter, processMigration securityOverrides
+	databases := map[string]securityOverrides{}
+	if objects.Console != nil {
+		console = getSecurityOverrides(objects.Console.KieAppObject)
+	}
+	if objects.Dashbuilder != nil {
+		dashbuilder = getSecurityOverrides(objects.Dashbuilder.KieAppObject)
+	}
+	if objects.SmartRouter != nil {
+		smartRouter = getSecurityOverrides(objects.SmartRouter.KieAppObject)
+	}
+	if objects.ProcessMigration != nil {
+		processMigration = getSecurityOverrides(objects.ProcessMigration.KieAppObject)
+		database := objects.ProcessMigration.Database.InternalDatabaseObject
+		databases[cr.Name+"-process-migration-"+string(database.Type)] = securityOverrides{database.PodSecurityContext, database.SecurityContext}
+	}
+	setObjectSecurityContexts(&env.Console, cr, nil, console)
+	setObjectSecurityContexts(&env.Dashbuilder, cr, nil, dashbuilder)
+	setObjectSecurityContexts(&env.SmartRouter, cr, nil, smartRouter)
+	setObjectSecurityContexts(&env.ProcessMigration, cr, nil, processMigration)
+	for i := range env.Servers {
+		serverSet, kieName := GetServerSet(cr, i)
+		if serverSet.Database != nil {
+			database := serverSet.Database.InternalDatabaseObject
+			databases[kieName+"-"+string(database.Type)] = securityOverrides{database.PodSecurityContext, database.SecurityContext}
+		}
+		broker := map[string]securityOverrides{kieName + "-amq": {}}
+		if serverSet.Jms != nil {
+			broker[kieName+"-amq"] = securityOverrides{serverSet.Jms.PodSecurityContext, serverSet.Jms.SecurityContext}
+		}
+		setObjectSecurityContexts(&env.Servers[i], cr, broker, getSecurityOverrides(serverSet.KieAppObject))
+	}
+	for i := range env.Databases {
+		setObjectSecurityContexts(&env.Databases[i], cr, databases, securityOverrides{})
+	}
+	for i := range env.Others {
+		setObjectSecurityContexts(&env.Others[i], cr, nil, securityOverrides{})
+	}
+	return env
+}
+
+func getSecurityOverrides(appObject api.KieAppObject) securityOverrides {
+	return securityOverrides{appObject.PodSecurityContext, appObject.SecurityContext}
+}
+
+// setObjectSecurityContexts sets the security contexts of the pod templates of the object, with the overrides found
+// for the name of each DeploymentConfig and StatefulSet, or else the default ones
+func setObjectSecurityContexts(object *api.CustomObject, cr *api.KieApp, named map[string]securityOverrides, defaultOverrides securityOverrides) {
+	getOverrides := func(name string) securityOverrides {
+		if overrides, found := named[name]; found {
+			return overrides
+		}
+		return defaultOverrides
+	}
+	for i := range object.DeploymentConfigs {
+		dc := &object.DeploymentConfigs[i]
+		setPodSecurityContexts(dc.Spec.Template, cr, getOverrides(dc.Name))
+	}
+	for i := range object.StatefulSets {
+		statefulSet := &object.StatefulSets[i]
+		setPodSecurityContexts(&statefulSet.Spec.Template, cr, getOverrides(statefulSet.Name))
+	}
+}
+
+func setPodSecurityContexts(template *corev1.PodTemplateSpec, cr *api.KieApp, overrides securityOverrides) {
+	if template == nil {
+		return
+	}
+	if IsRestricted(cr) {
+		if template.Spec.SecurityContext == nil {
+			template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		template.Spec.SecurityContext.RunAsNonRoot = Pbool(true)
+		template.Spec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	if overrides.pod != nil {
+		if template.Spec.SecurityContext == nil {
+			template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		if err := mergo.Merge(template.Spec.SecurityContext, *overrides.pod.DeepCopy(), mergo.WithOverride); err != nil {
+			log.Error("Error merging interfaces. ", err)
+		}
+	}
+	for _, containers := range [][]corev1.Container{template.Spec.InitContainers, template.Spec.Containers} {
+		for i := range containers {
+			setContainerSecurityContext(&containers[i], cr, overrides.container)
+		}
+	}
+}
+
+func setContainerSecurityContext(container *corev1.Container, cr *api.KieApp, override *corev1.SecurityContext) {
+	if IsRestricted(cr) {
+		if container.SecurityContext == nil {
+			container.SecurityContext = &corev1.SecurityContext{}
+		}
+		container.SecurityContext.AllowPrivilegeEscalation = Pbool(false)
+		container.SecurityContext.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	}
+	if override != nil {
+		if container.SecurityContext == nil {
+			container.SecurityContext = &corev1.SecurityContext{}
+		}
+		if err := mergo.Merge(container.SecurityContext, *override.DeepCopy(), mergo.WithOverride); err != nil {
+			log.Error("Error merging interfaces. ", err)
+		}
+	}
+}
+
+// ValidateRestrictedPodSecurity checks the pod templates of the DeploymentConfigs, Deployments and StatefulSets of the
+// environment against the restricted Pod Security standard, which the overrides and overlays defined in the CR might
+// not comply with
+func ValidateRestrictedPodSecurity(env api.Environment) error {
+	errs := field.ErrorList{}
+	objects := append([]api.CustomObject{env.Console, env.SmartRouter, env.ProcessMigration, env.Dashbuilder}, env.Servers...)
+	objects = append(append(objects, env.Databases...), env.Others...)
+	for _, object := range objects {
+		if object.Omit {
+			continue
+		}
+		for _, dc := range object.DeploymentConfigs {
+			if dc.Spec.Template != nil {
+				path := field.NewPath("DeploymentConfig").Key(dc.Name).Child("spec", "template", "spec")
+				errs = append(errs, validateRestrictedPodSpec(path, &dc.Spec.Template.Spec)...)
+			}
+		}
+		for _, deployment := range object.Deployments {
+			path := field.NewPath("Deployment").Key(deployment.Name).Child("spec", "template", "spec")
+			errs = append(errs, validateRestrictedPodSpec(path, &deployment.Spec.Template.Spec)...)
+		}
+		for _, statefulSet := range object.StatefulSets {
+			path := field.NewPath("StatefulSet").Key(statefulSet.Name).Child("spec", "template", "spec")
+			errs = append(errs, validateRestrictedPodSpec(path, &statefulSet.Spec.Template.Spec)...)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("the restricted Pod Security standard is not met: %v", errs.ToAggregate())
+	}
+	return nil
+}
+
+func validateRestrictedPodSpec(path *field.Path, spec *corev1.PodSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		errs = append(errs, field.Forbidden(path, "host namespaces are not allowed"))
+	}
+	for i, volume := range spec.Volumes {
+		if volumeType := getVolumeType(volume); !sets.NewString(restrictedVolumeTypes...).Has(volumeType) {
+			errs = append(errs, field.NotSupported(path.Child("volumes").Index(i), volumeType, restrictedVolumeTypes))
+		}
+	}
+	podRunAsNonRoot := false
+	podSeccomp := false
+	if podContext := spec.SecurityContext; podContext != nil {
+		contextPath := path.Child("securityContext")
+		podRunAsNonRoot = podContext.RunAsNonRoot != nil && *podContext.RunAsNonRoot
+		if podContext.RunAsNonRoot != nil && !*podContext.RunAsNonRoot {
+			errs = append(errs, field.Invalid(contextPath.Child("runAsNonRoot"), false, "must be true"))
+		}
+		if podContext.RunAsUser != nil && *podContext.RunAsUser == 0 {
+			errs = append(errs, field.Invalid(contextPath.Child("runAsUser"), 0, "must not be 0"))
+		}
+		if podContext.SeccompProfile != nil {
+			podSeccomp = true
+			errs = append(errs, validateRestrictedSeccompProfile(contextPath.Child("seccompProfile"), podContext.SeccompProfile)...)
+		}
+	}
+	for _, containers := range []struct {
+		name       string
+		containers []corev1.Container
+	}{{"initContainers", spec.InitContainers}, {"containers", spec.Containers}} {
+		for i, container := range containers.containers {
+			containerPath := path.Child(containers.name).Index(i)
+			for j, port := range container.Ports {
+				if port.HostPort != 0 {
+					errs = append(errs, field.Forbidden(containerPath.Child("ports").Index(j).Child("hostPort"), "host ports are not allowed"))
+				}
+			}
+			errs = append(errs, validateRestrictedSecurityContext(containerPath.Child("securityContext"), container.SecurityContext, podRunAsNonRoot, podSeccomp)...)
+		}
+	}
+	return errs
+}
+
+func validateRestrictedSecurityContext(path *field.Path, context *corev1.SecurityContext, podRunAsNonRoot, podSeccomp bool) field.ErrorList {
+	errs := field.ErrorList{}
+	if context == nil {
+		context = &corev1.SecurityContext{}
+	}
+	if context.Privileged != nil && *context.Privileged {
+		errs = append(errs, field.Forbidden(path.Child("privileged"), "privileged containers are not allowed"))
+	}
+	if context.ProcMount != nil && *context.ProcMount != corev1.DefaultProcMount {
+		errs = append(errs, field.NotSupported(path.Child("procMount"), *context.ProcMount, []string{string(corev1.DefaultProcMount)}))
+	}
+	if context.AllowPrivilegeEscalation == nil || *context.AllowPrivilegeEscalation {
+		errs = append(errs, field.Required(path.Child("allowPrivilegeEscalation"), "must be false"))
+	}
+	droppedAll := false
+	if context.Capabilities != nil {
+		for _, capability := range context.Capabilities.Drop {
+			droppedAll = droppedAll || capability == "ALL"
+		}
+		for i, capability := range context.Capabilities.Add {
+			if !restrictedAddedCapabilities[capability] {
+				errs = append(errs, field.Forbidden(path.Child("capabilities", "add").Index(i), fmt.Sprintf("capability %s is not allowed", capability)))
+			}
+		}
+	}
+	if !droppedAll {
+		errs = append(errs, field.Required(path.Child("capabilities", "drop"), "must include ALL"))
+	}
+	if context.RunAsNonRoot != nil {
+		if !*context.RunAsNonRoot {
+			errs = append(errs, field.Invalid(path.Child("runAsNonRoot"), false, "must be true"))
+		}
+	} else if !podRunAsNonRoot {
+		errs = append(errs, field.Required(path.Child("runAsNonRoot"), "must be true for the pod or container"))
+	}
+	if context.RunAsUser != nil && *context.RunAsUser == 0 {
+		errs = append(errs, field.Invalid(path.Child("runAsUser"), 0, "must not be 0"))
+	}
+	if context.SeccompProfile != nil {
+		errs = append(errs, validateRestrictedSeccompProfile(path.Child("seccompProfile"), context.SeccompProfile)...)
+	} else if !podSeccomp {
+		errs = append(errs, field.Required(path.Child("seccompProfile"), "must be set for the pod or container"))
+	}
+	return errs
+}
+
+func validateRestrictedSeccompProfile(path *field.Path, profile *corev1.SeccompProfile) field.ErrorList {
+	if profile.Type != corev1.SeccompProfileTypeRuntimeDefault && profile.Type != corev1.SeccompProfileTypeLocalhost {
+		return field.ErrorList{field.NotSupported(path.Child("type"), profile.Type,
+			[]string{string(corev1.SeccompProfileTypeRuntimeDefault), string(corev1.SeccompProfileTypeLocalhost)})}
+	}
+	return nil
+}
+
+// getVolumeType returns the json name of the source set for the volume. Like the API server, a volume without source
+// is considered an emptyDir.
+func getVolumeType(volume corev1.Volume) string {
+	source := reflect.ValueOf(volume.VolumeSource)
+	for i := 0; i < source.NumField(); i++ {
+		if !source.Field(i).IsNil() {
+			return strings.Split(source.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return "emptyDir"
+}
@@ -0,0 +1,144 @@
+package defaults
+
+import (
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getSecurityEnvironment(t *testing.T, cr *api.KieApp) api.Environment {
+	env, err := GetEnvironment(cr, test.MockService())
+	assert.Nil(t, err)
+	return SetSecurityContexts(ConsolidateObjects(env, cr), cr)
+}
+
+// getPodTemplates returns the pod templates of the DeploymentConfigs and StatefulSets of the environment, by their name
+func getPodTemplates(env api.Environment) map[string]corev1.PodTemplateSpec {
+	templates := map[string]corev1.PodTemplateSpec{}
+	objects := append([]api.CustomObject{env.Console, env.SmartRouter, env.Dashbuilder, env.ProcessMigration}, env.Servers...)
+	objects = append(objects, env.Databases...)
+	for _, object := range append(objects, env.Others...) {
+		for _, dc := range object.DeploymentConfigs {
+			templates[dc.Name] = *dc.Spec.Template
+		}
+		for _, statefulSet := range object.StatefulSets {
+			templates[statefulSet.Name] = statefulSet.Spec.Template
+		}
+	}
+	return templates
+}
+
+func TestSetSecurityContextsWithoutPreset(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec:       api.KieAppSpec{Environment: api.RhpamTrial},
+	}
+	env := getSecurityEnvironment(t, cr)
+	for name, template := range getPodTemplates(env) {
+		assert.Nil(t, template.Spec.SecurityContext, name)
+		assert.Nil(t, template.Spec.Containers[0].SecurityContext, name)
+	}
+	assert.Error(t, ValidateRestrictedPodSecurity(env))
+}
+
+func TestSetSecurityContextsRestricted(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment:           api.RhpamAuthoringHA,
+			SecurityContextPreset: api.RestrictedSecurityContextPreset,
+		},
+	}
+	env := getSecurityEnvironment(t, cr)
+	templates := getPodTemplates(env)
+	assert.Contains(t, templates, "test-datagrid")
+	assert.Contains(t, templates, "test-amq")
+	for name, template := range templates {
+		assert.Equal(t, Pbool(true), template.Spec.SecurityContext.RunAsNonRoot, name)
+		assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, template.Spec.SecurityContext.SeccompProfile.Type, name)
+		for _, container := range append(template.Spec.InitContainers, template.Spec.Containers...) {
+			assert.Equal(t, Pbool(false), container.SecurityContext.AllowPrivilegeEscalation, name)
+			assert.Equal(t, []corev1.Capability{"ALL"}, container.SecurityContext.Capabilities.Drop, name)
+		}
+	}
+	assert.Nil(t, ValidateRestrictedPodSecurity(env))
+}
+
+func TestSetSecurityContextsOverrides(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment:           api.RhpamProduction,
+			SecurityContextPreset: api.RestrictedSecurityContextPreset,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{
+					KieAppObject: api.KieAppObject{
+						PodSecurityContext: &corev1.PodSecurityContext{FSGroup: Pint64(185)},
+						SecurityContext:    &corev1.SecurityContext{ReadOnlyRootFilesystem: Pbool(true)},
+					},
+					Database: &api.DatabaseObject{InternalDatabaseObject: api.InternalDatabaseObject{
+						Type:               api.DatabasePostgreSQL,
+						PodSecurityContext: &corev1.PodSecurityContext{RunAsUser: Pint64(26)},
+					}},
+					Jms: &api.KieAppJmsObject{
+						EnableIntegration: true,
+						SecurityContext:   &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: []corev1.Capability{"NET_BIND_SERVICE"}}},
+					},
+				}},
+			},
+		},
+	}
+	env := getSecurityEnvironment(t, cr)
+	templates := getPodTemplates(env)
+
+	server := templates["test-kieserver"]
+	assert.Equal(t, Pint64(185), server.Spec.SecurityContext.FSGroup)
+	assert.Equal(t, Pbool(true), server.Spec.SecurityContext.RunAsNonRoot)
+	assert.Equal(t, Pbool(true), server.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem)
+	assert.Equal(t, Pbool(false), server.Spec.Containers[0].SecurityContext.AllowPrivilegeEscalation)
+
+	database := templates["test-kieserver-postgresql"]
+	assert.Equal(t, Pint64(26), database.Spec.SecurityContext.RunAsUser)
+	assert.Nil(t, database.Spec.SecurityContext.FSGroup)
+	assert.Nil(t, database.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem)
+
+	broker := templates["test-kieserver-amq"]
+	assert.Nil(t, broker.Spec.SecurityContext.FSGroup)
+	assert.Equal(t, []corev1.Capability{"NET_BIND_SERVICE"}, broker.Spec.Containers[0].SecurityContext.Capabilities.Add)
+
+	assert.Nil(t, templates["test-rhpamcentrmon"].Spec.SecurityContext.FSGroup)
+	assert.Nil(t, ValidateRestrictedPodSecurity(env))
+}
+
+func TestValidateRestrictedPodSecurity(t *testing.T) {
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment:           api.RhpamTrial,
+			SecurityContextPreset: api.RestrictedSecurityContextPreset,
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{
+					PodSecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: Pbool(false)},
+					SecurityContext:    &corev1.SecurityContext{Privileged: Pbool(true)},
+				}},
+			},
+		},
+	}
+	env := getSecurityEnvironment(t, cr)
+	err := ValidateRestrictedPodSecurity(env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "DeploymentConfig[test-rhpamcentr].spec.template.spec.securityContext.runAsNonRoot")
+	assert.Contains(t, err.Error(), "DeploymentConfig[test-rhpamcentr].spec.template.spec.containers[0].securityContext.privileged")
+	assert.NotContains(t, err.Error(), "test-kieserver")
+
+	cr.Spec.Objects.Console.SecurityContext = &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}}}
+	cr.Spec.Objects.Console.PodSecurityContext = nil
+	env = getSecurityEnvironment(t, cr)
+	err = ValidateRestrictedPodSecurity(env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "capability SYS_ADMIN is not allowed")
+}
@@ -0,0 +1,308 @@
+package defaults
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/shared"
+	"golang.org/x/mod/semver"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateKieApp checks the KieApp spec for configurations the operator cannot deploy. When old is set, the KieApp is
+// being updated and its version is also checked against the one already applied.
+func ValidateKieApp(cr, old *api.KieApp) field.ErrorList {
+	specPath := field.NewPath("spec")
+	objectsPath := specPath.Child("objects")
+	errs := field.ErrorList{}
+
+	if version := cr.Spec.Version; version != "" {
+		if !checkVersion(version) {
+			errs = append(errs, field.NotSupported(specPath.Child("version"), version, constants.SupportedVersions))
+		} else if old != nil && old.Status.Applied.Version != "" &&
+			semver.Compare("v"+version, "v"+old.Status.Applied.Version) < 0 {
+			errs = append(errs, field.Forbidden(specPath.Child("version"),
+				fmt.Sprintf("downgrade from the applied version %s is not supported", old.Status.Applied.Version)))
+		}
+	}
+
+	objects := cr.Spec.Objects
+	if objects.ProcessMigration != nil && GetProduct(cr.Spec.Environment) == constants.RhdmPrefix {
+		errs = append(errs, field.Forbidden(objectsPath.Child("processMigration"),
+			fmt.Sprintf("process instance migration is not available for the %s environment", cr.Spec.Environment)))
+	}
+	if objects.Console != nil {
+		errs = append(errs, shared.ValidateHostname(objectsPath.Child("console", "routeHostname"), objects.Console.RouteHostname)...)
+		errs = append(errs, validatePodExtensions(objectsPath.Child("console"), objects.Console.KieAppObject)...)
+	}
+	if objects.Dashbuilder != nil {
+		errs = append(errs, shared.ValidateHostname(objectsPath.Child("dashbuilder", "routeHostname"), objects.Dashbuilder.RouteHostname)...)
+		errs = append(errs, validatePodExtensions(objectsPath.Child("dashbuilder"), objects.Dashbuilder.KieAppObject)...)
+	}
+	if objects.SmartRouter != nil {
+		errs = append(errs, shared.ValidateHostname(objectsPath.Child("smartRouter", "routeHostname"), objects.SmartRouter.RouteHostname)...)
+		errs = append(errs, validatePodExtensions(objectsPath.Child("smartRouter"), objects.SmartRouter.KieAppObject)...)
+	}
+	if objects.ProcessMigration != nil {
+		errs = append(errs, shared.ValidateHostname(objectsPath.Child("processMigration", "routeHostname"), objects.ProcessMigration.RouteHostname)...)
+		errs = append(errs, validatePodExtensions(objectsPath.Child("processMigration"), objects.ProcessMigration.KieAppObject)...)
+		errs = append(errs, validateProbes(objectsPath.Child("processMigration", "database", "probes"), objects.ProcessMigration.Database.Probes)...)
+	}
+
+	if exposure := cr.Spec.Exposure; exposure != nil && exposure.Type == api.RouteExposure && cr.Spec.Platform == api.KubernetesPlatform {
+		errs = append(errs, field.NotSupported(specPath.Child("exposure", "type"), exposure.Type,
+			[]string{string(api.IngressExposure), string(api.HTTPRouteExposure)}))
+	}
+	errs = append(errs, validateExposedHostnames(specPath, cr)...)
+	if networkPolicy := cr.Spec.NetworkPolicy; networkPolicy != nil && networkPolicy.Enabled && networkPolicy.RouterNamespaceSelector == nil && cr.Spec.Platform == api.KubernetesPlatform {
+		errs = append(errs, field.Required(specPath.Child("networkPolicy", "routerNamespaceSelector"),
+			"must select the namespaces of the ingress controller on Kubernetes"))
+	}
+	if pdb := cr.Spec.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("podDisruptionBudget", "maxUnavailable"), "cannot be set along with minAvailable"))
+	}
+	if tls := cr.Spec.TLS; tls != nil && tls.RenewBefore != nil {
+		if renewBefore := tls.RenewBefore.Duration; renewBefore <= 0 || renewBefore >= constants.KeystoreValidity*24*time.Hour {
+			errs = append(errs, field.Invalid(specPath.Child("tls", "renewBefore"), renewBefore.String(),
+				fmt.Sprintf("must be positive and shorter than the %d days the keystores are valid for", constants.KeystoreValidity)))
+		}
+	}
+	if tls := cr.Spec.TLS; tls != nil && tls.KeySize != 0 {
+		if tls.KeyAlgorithm == api.KeyAlgorithmECDSA {
+			if tls.KeySize != 256 && tls.KeySize != 384 {
+				errs = append(errs, field.NotSupported(specPath.Child("tls", "keySize"), tls.KeySize, []string{"256", "384"}))
+			}
+		} else if tls.KeySize < constants.DefaultRSAKeySize || tls.KeySize > 8192 {
+			errs = append(errs, field.Invalid(specPath.Child("tls", "keySize"), tls.KeySize,
+				fmt.Sprintf("must be between %d and 8192 bits for RSA keys", constants.DefaultRSAKeySize)))
+		}
+	}
+	if truststore := cr.Spec.Truststore; truststore != nil {
+		for i, source := range truststore.From {
+			sourcePath := specPath.Child("truststore", "from").Index(i)
+			if source.ConfigMapKeyRef != nil && source.SecretKeyRef != nil {
+				errs = append(errs, field.Forbidden(sourcePath.Child("secretKeyRef"), "cannot be set along with configMapKeyRef"))
+			} else if source.ConfigMapKeyRef == nil && source.SecretKeyRef == nil {
+				errs = append(errs, field.Required(sourcePath, "either configMapKeyRef or secretKeyRef must be set"))
+			}
+		}
+	}
+
+	errs = append(errs, metav1validation.ValidateLabels(cr.Spec.CommonLabels, specPath.Child("commonLabels"))...)
+	errs = append(errs, apimachineryvalidation.ValidateAnnotations(cr.Spec.CommonAnnotations, specPath.Child("commonAnnotations"))...)
+
+	for i, overlay := range cr.Spec.Overlays {
+		overlayPath := specPath.Child("overlays").Index(i)
+		if _, err := path.Match(overlay.Target.Name, ""); err != nil {
+			errs = append(errs, field.Invalid(overlayPath.Child("target", "name"), overlay.Target.Name, err.Error()))
+		}
+		switch {
+		case overlay.StrategicMergePatch != "" && overlay.JSONPatch != "":
+			errs = append(errs, field.Forbidden(overlayPath.Child("jsonPatch"), "cannot be set along with strategicMergePatch"))
+		case overlay.StrategicMergePatch == "" && overlay.JSONPatch == "":
+			errs = append(errs, field.Required(overlayPath, "either strategicMergePatch or jsonPatch must be set"))
+		default:
+			if _, err := getOverlayPatch(overlay); err != nil {
+				patchPath, patch := overlayPath.Child("jsonPatch"), overlay.JSONPatch
+				if overlay.StrategicMergePatch != "" {
+					patchPath, patch = overlayPath.Child("strategicMergePatch"), overlay.StrategicMergePatch
+				}
+				errs = append(errs, field.Invalid(patchPath, patch, err.Error()))
+			}
+		}
+	}
+
+	serverNames := map[string]bool{}
+	for i, server := range objects.Servers {
+		serverPath := objectsPath.Child("servers").Index(i)
+		if server.Name != "" {
+			if serverNames[server.Name] {
+				errs = append(errs, field.Duplicate(serverPath.Child("name"), server.Name))
+			}
+			serverNames[server.Name] = true
+		}
+		errs = append(errs, shared.ValidateHostname(serverPath.Child("routeHostname"), server.RouteHostname)...)
+		errs = append(errs, validatePodExtensions(serverPath, server.KieAppObject)...)
+		if server.Database != nil {
+			errs = append(errs, validateProbes(serverPath.Child("database", "probes"), server.Database.Probes)...)
+		}
+		if server.Jms != nil {
+			errs = append(errs, validateJmsSSL(serverPath.Child("jms"), server.Jms)...)
+		}
+		if IsAutoscaled(server) {
+			errs = append(errs, validateAutoscaling(serverPath.Child("autoscaling"), server.Autoscaling, cr.Spec.Environment)...)
+		}
+	}
+	return errs
+}
+
+// validateExposedHostnames requires the exposure domain when Ingresses or HTTPRoutes expose components without a
+// routeHostname, as nothing assigns them a hostname the way the OpenShift router does for Routes
+func validateExposedHostnames(specPath *field.Path, cr *api.KieApp) field.ErrorList {
+	exposureType := api.RouteExposure
+	if cr.Spec.Exposure != nil && cr.Spec.Exposure.Type != "" {
+		exposureType = cr.Spec.Exposure.Type
+	} else if cr.Spec.Platform == api.KubernetesPlatform {
+		exposureType = api.IngressExposure
+	}
+	if exposureType == api.RouteExposure || (cr.Spec.Exposure != nil && cr.Spec.Exposure.Domain != "") {
+		return nil
+	}
+	if cr.Spec.Environment == api.RhpamTrial || cr.Spec.Environment == api.RhdmTrial {
+		// the additional http routes of the trial environments never have a routeHostname
+		return field.ErrorList{field.Required(specPath.Child("exposure", "domain"),
+			fmt.Sprintf("must be set to generate the hostnames of the http routes of the %s environment exposed with an %s",
+				cr.Spec.Environment, exposureType))}
+	}
+	objects := cr.Spec.Objects
+	objectsPath := specPath.Child("objects")
+	var missing []string
+	if cr.Spec.Environment != api.RhpamStandaloneDashbuilder {
+		// same rule as GetEnvironment for omitting the console
+		omitConsole := cr.Spec.Environment == api.RhdmProductionImmutable ||
+			(cr.Spec.Environment == api.RhpamProductionImmutable && objects.Console == nil)
+		if !omitConsole && (objects.Console == nil || objects.Console.RouteHostname == "") {
+			missing = append(missing, objectsPath.Child("console").String())
+		}
+		if len(objects.Servers) == 0 {
+			missing = append(missing, objectsPath.Child("servers").String())
+		}
+		for i, server := range objects.Servers {
+			if server.RouteHostname == "" {
+				missing = append(missing, objectsPath.Child("servers").Index(i).String())
+			}
+		}
+	}
+	if (objects.Dashbuilder == nil && cr.Spec.Environment == api.RhpamStandaloneDashbuilder) ||
+		(objects.Dashbuilder != nil && objects.Dashbuilder.RouteHostname == "") {
+		missing = append(missing, objectsPath.Child("dashbuilder").String())
+	}
+	if objects.SmartRouter != nil && objects.SmartRouter.RouteHostname == "" {
+		missing = append(missing, objectsPath.Child("smartRouter").String())
+	}
+	if objects.ProcessMigration != nil && objects.ProcessMigration.RouteHostname == "" {
+		missing = append(missing, objectsPath.Child("processMigration").String())
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return field.ErrorList{field.Required(specPath.Child("exposure", "domain"),
+		fmt.Sprintf("must be set to generate the hostnames of the components exposed with an %s without a routeHostname: %s",
+			exposureType, strings.Join(missing, ", ")))}
+}
+
+// validatePodExtensions checks the sidecars, init containers, volumes and custom probes added to the pods, as their
+// schema is not part of the CRD, along with the keys and values of the labels and annotations of the component
+func validatePodExtensions(objectPath *field.Path, appObject api.KieAppObject) field.ErrorList {
+	errs := metav1validation.ValidateLabels(appObject.PodLabels, objectPath.Child("podLabels"))
+	errs = append(errs, apimachineryvalidation.ValidateAnnotations(appObject.Annotations, objectPath.Child("annotations"))...)
+	containerNames := map[string]bool{}
+	for _, containers := range []struct {
+		name       string
+		containers []corev1.Container
+	}{{"sidecars", appObject.Sidecars}, {"initContainers", appObject.InitContainers}} {
+		for i, container := range containers.containers {
+			containerPath := objectPath.Child(containers.name).Index(i)
+			if container.Name == "" {
+				errs = append(errs, field.Required(containerPath.Child("name"), ""))
+			} else if containerNames[container.Name] {
+				errs = append(errs, field.Duplicate(containerPath.Child("name"), container.Name))
+			}
+			containerNames[container.Name] = true
+			if container.Image == "" {
+				errs = append(errs, field.Required(containerPath.Child("image"), ""))
+			}
+		}
+	}
+	volumeNames := map[string]bool{}
+	for i, volume := range appObject.Volumes {
+		volumePath := objectPath.Child("volumes").Index(i)
+		if volume.Name == "" {
+			errs = append(errs, field.Required(volumePath.Child("name"), ""))
+		} else if volumeNames[volume.Name] {
+			errs = append(errs, field.Duplicate(volumePath.Child("name"), volume.Name))
+		}
+		volumeNames[volume.Name] = true
+	}
+	return append(errs, validateProbes(objectPath.Child("probes"), appObject.Probes)...)
+}
+
+// validateProbes requires a single handler for the custom probes
+func validateProbes(probesPath *field.Path, probes *api.KieAppProbes) field.ErrorList {
+	errs := field.ErrorList{}
+	if probes == nil {
+		return errs
+	}
+	for _, probe := range []struct {
+		name  string
+		probe *api.KieAppProbe
+	}{{"liveness", probes.Liveness}, {"readiness", probes.Readiness}, {"startup", probes.Startup}} {
+		if probe.probe == nil || probe.probe.Custom == nil {
+			continue
+		}
+		handlers := 0
+		for _, set := range []bool{probe.probe.Custom.Exec != nil, probe.probe.Custom.HTTPGet != nil, probe.probe.Custom.TCPSocket != nil} {
+			if set {
+				handlers++
+			}
+		}
+		if handlers != 1 {
+			errs = append(errs, field.Invalid(probesPath.Child(probe.name, "custom"), handlers, "must define exactly one of exec, httpGet or tcpSocket"))
+		}
+	}
+	return errs
+}
+
+// validateAutoscaling refuses autoscaling for the environments that deny scaling the kieservers, and replica limits
+// the HorizontalPodAutoscaler would reject
+func validateAutoscaling(autoscalingPath *field.Path, autoscaling *api.KieServerAutoscaling, environment api.EnvironmentType) field.ErrorList {
+	errs := field.ErrorList{}
+	if IsScaleDenied(environment, serverReplicas) {
+		errs = append(errs, field.Forbidden(autoscalingPath.Child("enabled"),
+			fmt.Sprintf("scaling the kieservers is not allowed for the %s environment", environment)))
+	}
+	if autoscaling.MaxReplicas < 1 {
+		errs = append(errs, field.Required(autoscalingPath.Child("maxReplicas"), "must be at least 1"))
+	} else if autoscaling.MinReplicas != nil && *autoscaling.MinReplicas > autoscaling.MaxReplicas {
+		errs = append(errs, field.Invalid(autoscalingPath.Child("maxReplicas"), autoscaling.MaxReplicas,
+			fmt.Sprintf("cannot be lower than minReplicas %d", *autoscaling.MinReplicas)))
+	}
+	return errs
+}
+
+// validateJmsSSL requires every AMQ SSL field as soon as one of them is set, as SSL is only enabled when all are present
+func validateJmsSSL(jmsPath *field.Path, jms *api.KieAppJmsObject) field.ErrorList {
+	sslFields := []struct {
+		name  string
+		value string
+	}{
+		{"amqSecretName", jms.AMQSecretName},
+		{"amqKeystoreName", jms.AMQKeystoreName},
+		{"amqKeystorePassword", jms.AMQKeystorePassword},
+		{"amqTruststoreName", jms.AMQTruststoreName},
+		{"amqTruststorePassword", jms.AMQTruststorePassword},
+	}
+	var set, missing []string
+	for _, sslField := range sslFields {
+		if sslField.value == "" {
+			missing = append(missing, sslField.name)
+		} else {
+			set = append(set, sslField.name)
+		}
+	}
+	errs := field.ErrorList{}
+	if len(set) == 0 {
+		return errs
+	}
+	for _, name := range missing {
+		errs = append(errs, field.Required(jmsPath.Child(name), fmt.Sprintf("required to enable AMQ SSL, as %v are set", set)))
+	}
+	return errs
+}
@@ -0,0 +1,350 @@
+package defaults
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateKieApp(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Version:     constants.CurrentVersion,
+			Objects: api.KieAppObjects{
+				Console:          &api.ConsoleObject{KieAppObject: api.KieAppObject{RouteHostname: "console.example.com"}},
+				ProcessMigration: &api.ProcessMigrationObject{},
+				Servers: []api.KieServerSet{
+					{Name: "server"},
+					{Name: "other", Jms: &api.KieAppJmsObject{EnableIntegration: true}},
+				},
+			},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Version = "6.4.0"
+	cr.Spec.Environment = api.RhdmAuthoring
+	cr.Spec.Objects.Console.RouteHostname = "Invalid_Host"
+	cr.Spec.Objects.Servers[1].Name = "server"
+	cr.Spec.Objects.Servers[1].Jms.AMQKeystoreName = "broker.ks"
+	minAvailable, maxUnavailable := intstr.FromInt(1), intstr.FromString("50%")
+	cr.Spec.PodDisruptionBudget = &api.KieAppPodDisruptionBudget{MinAvailable: &minAvailable, MaxUnavailable: &maxUnavailable}
+	errs := ValidateKieApp(cr, nil)
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	assert.Contains(t, fields, "spec.version")
+	assert.Contains(t, fields, "spec.objects.processMigration")
+	assert.Contains(t, fields, "spec.objects.console.routeHostname")
+	assert.Contains(t, fields, "spec.objects.servers[1].name")
+	assert.Contains(t, fields, "spec.objects.servers[1].jms.amqTruststorePassword")
+	assert.NotContains(t, fields, "spec.objects.servers[1].jms.amqKeystoreName")
+	assert.Contains(t, fields, "spec.podDisruptionBudget.maxUnavailable")
+}
+
+func TestValidateKieAppDowngrade(t *testing.T) {
+	old := &api.KieApp{}
+	old.Status.Applied.Version = constants.CurrentVersion
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Version:     constants.PriorVersion,
+		},
+	}
+	errs := ValidateKieApp(cr, old)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeForbidden, errs[0].Type)
+	assert.Equal(t, "spec.version", errs[0].Field)
+
+	assert.Empty(t, ValidateKieApp(cr, nil))
+	cr.Spec.Version = constants.CurrentVersion
+	assert.Empty(t, ValidateKieApp(cr, old))
+}
+
+func TestValidateKieAppAutoscaling(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{
+					{Name: "server", Autoscaling: &api.KieServerAutoscaling{Enabled: true, MinReplicas: Pint32(2), MaxReplicas: 5}},
+				},
+			},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Objects.Servers[0].Autoscaling.MaxReplicas = 1
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.objects.servers[0].autoscaling.maxReplicas", errs[0].Field)
+
+	denyServerScale(t, api.RhpamProduction)
+	cr.Spec.Objects.Servers[0].Autoscaling.MaxReplicas = 5
+	errs = ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeForbidden, errs[0].Type)
+	assert.Equal(t, "spec.objects.servers[0].autoscaling.enabled", errs[0].Field)
+
+	cr.Spec.Objects.Servers[0].Autoscaling.Enabled = false
+	assert.Empty(t, ValidateKieApp(cr, nil))
+}
+
+func TestValidateKieAppPodExtensions(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{
+					Sidecars:       []corev1.Container{{Name: "log-shipper", Image: "fluent-bit"}},
+					InitContainers: []corev1.Container{{Name: "fetch-jars", Image: "fetch-jars"}},
+					Volumes:        []corev1.Volume{{Name: "jars"}},
+				}},
+			},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Objects.Console.InitContainers[0].Name = "log-shipper"
+	cr.Spec.Objects.Console.Sidecars[0].Image = ""
+	cr.Spec.Objects.Console.Volumes = append(cr.Spec.Objects.Console.Volumes, corev1.Volume{Name: "jars"})
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "spec.objects.console.sidecars[0].image", errs[0].Field)
+	assert.Equal(t, field.ErrorTypeDuplicate, errs[1].Type)
+	assert.Equal(t, "spec.objects.console.initContainers[0].name", errs[1].Field)
+	assert.Equal(t, "spec.objects.console.volumes[1].name", errs[2].Field)
+}
+
+func TestValidateKieAppOverlays(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Overlays: []api.KieAppOverlay{
+				{Target: api.KieAppOverlayTarget{Name: "*-kieserver*"}, StrategicMergePatch: "metadata:\n  labels:\n    team: kie"},
+				{JSONPatch: `[{"op": "remove", "path": "/spec/triggers"}]`},
+			},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Overlays[0].Target.Name = "[kieserver"
+	cr.Spec.Overlays[0].JSONPatch = `[]`
+	cr.Spec.Overlays[1].JSONPatch = `[{"op": "remove"`
+	cr.Spec.Overlays = append(cr.Spec.Overlays, api.KieAppOverlay{})
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 4)
+	assert.Equal(t, "spec.overlays[0].target.name", errs[0].Field)
+	assert.Equal(t, field.ErrorTypeForbidden, errs[1].Type)
+	assert.Equal(t, "spec.overlays[0].jsonPatch", errs[1].Field)
+	assert.Equal(t, "spec.overlays[1].jsonPatch", errs[2].Field)
+	assert.Equal(t, field.ErrorTypeRequired, errs[3].Type)
+	assert.Equal(t, "spec.overlays[2]", errs[3].Field)
+}
+
+func TestValidateKieAppProbes(t *testing.T) {
+	tcpSocket := corev1.Handler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)}}
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamProduction,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{
+					KieAppObject: api.KieAppObject{Probes: &api.KieAppProbes{
+						Startup: &api.KieAppProbe{Custom: &corev1.Probe{Handler: tcpSocket}},
+					}},
+					Database: &api.DatabaseObject{InternalDatabaseObject: api.InternalDatabaseObject{
+						Type:   api.DatabasePostgreSQL,
+						Probes: &api.KieAppProbes{Liveness: &api.KieAppProbe{Custom: &corev1.Probe{}}},
+					}},
+				}},
+			},
+		},
+	}
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.objects.servers[0].database.probes.liveness.custom", errs[0].Field)
+
+	cr.Spec.Objects.Servers[0].Database.Probes.Liveness.Custom.Handler = tcpSocket
+	cr.Spec.Objects.Servers[0].Probes.Startup.Custom.Exec = &corev1.ExecAction{Command: []string{"true"}}
+	errs = ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.objects.servers[0].probes.startup.custom", errs[0].Field)
+}
+
+func TestValidateKieAppMetadata(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment:       api.RhpamTrial,
+			CommonLabels:      map[string]string{"cost-center": "cc 1234"},
+			CommonAnnotations: map[string]string{"sidecar.istio.io/inject": "true"},
+			Objects: api.KieAppObjects{
+				Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{
+					PodLabels:   map[string]string{"team": "bpm"},
+					Annotations: map[string]string{"-invalid": "true"},
+				}},
+			},
+		},
+	}
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "spec.objects.console.annotations", errs[0].Field)
+	assert.Equal(t, "spec.commonLabels", errs[1].Field)
+}
+
+func TestValidateKieAppRenewBefore(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			TLS:         &api.KieAppTLS{RenewBefore: &metav1.Duration{Duration: 360 * time.Hour}},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.TLS.RenewBefore.Duration = constants.KeystoreValidity * 24 * time.Hour
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.tls.renewBefore", errs[0].Field)
+
+	cr.Spec.TLS.RenewBefore.Duration = -time.Hour
+	assert.Len(t, ValidateKieApp(cr, nil), 1)
+}
+
+func TestValidateKieAppKeySize(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			TLS:         &api.KieAppTLS{KeystoreFormat: api.KeystoreFormatPKCS12, KeyAlgorithm: api.KeyAlgorithmRSA, KeySize: 3072},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.TLS.KeySize = 1024
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.tls.keySize", errs[0].Field)
+
+	cr.Spec.TLS.KeyAlgorithm = api.KeyAlgorithmECDSA
+	cr.Spec.TLS.KeySize = 3072
+	assert.Len(t, ValidateKieApp(cr, nil), 1, "ECDSA keys should only be on the P-256 or P-384 curves")
+	cr.Spec.TLS.KeySize = 384
+	assert.Empty(t, ValidateKieApp(cr, nil))
+}
+
+func TestValidateKieAppTruststoreSources(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Truststore: &api.KieAppTruststore{
+				From: []api.TruststoreSource{
+					{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}, Key: "ca.crt"}},
+					{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}, Key: "ca.crt"}},
+				},
+			},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Truststore.From[0].SecretKeyRef = cr.Spec.Truststore.From[1].SecretKeyRef
+	cr.Spec.Truststore.From[1].SecretKeyRef = nil
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, field.ErrorTypeForbidden, errs[0].Type)
+	assert.Equal(t, "spec.truststore.from[0].secretKeyRef", errs[0].Field)
+	assert.Equal(t, field.ErrorTypeRequired, errs[1].Type)
+	assert.Equal(t, "spec.truststore.from[1]", errs[1].Field)
+}
+
+func TestValidateKieAppExposure(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Type: api.IngressExposure, Domain: "apps.example.com"},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Exposure.Type = api.RouteExposure
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeNotSupported, errs[0].Type)
+	assert.Equal(t, "spec.exposure.type", errs[0].Field)
+
+	cr.Spec.Platform = api.OpenShiftPlatform
+	assert.Empty(t, ValidateKieApp(cr, nil))
+}
+
+func TestValidateKieAppExposedHostnames(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamAuthoring,
+			Platform:    api.OpenShiftPlatform,
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Exposure = &api.KieAppExposure{Type: api.HTTPRouteExposure}
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeRequired, errs[0].Type)
+	assert.Equal(t, "spec.exposure.domain", errs[0].Field)
+	assert.Contains(t, errs[0].Detail, "spec.objects.console, spec.objects.servers")
+
+	cr.Spec.Exposure = nil
+	cr.Spec.Platform = api.KubernetesPlatform
+	cr.Spec.Objects = api.KieAppObjects{
+		Console: &api.ConsoleObject{KieAppObject: api.KieAppObject{RouteHostname: "console.example.com"}},
+		Servers: []api.KieServerSet{
+			{KieAppObject: api.KieAppObject{RouteHostname: "kieserver.example.com"}},
+			{Name: "other"},
+		},
+	}
+	errs = ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.exposure.domain", errs[0].Field)
+	assert.True(t, strings.HasSuffix(errs[0].Detail, ": spec.objects.servers[1]"), errs[0].Detail)
+
+	cr.Spec.Objects.Servers[1].RouteHostname = "other.example.com"
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Environment = api.RhpamTrial
+	errs = ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "spec.exposure.domain", errs[0].Field)
+	assert.Contains(t, errs[0].Detail, "http routes of the rhpam-trial environment")
+
+	cr.Spec.Objects = api.KieAppObjects{}
+	cr.Spec.Exposure = &api.KieAppExposure{Domain: "apps.example.com"}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+}
+
+func TestValidateKieAppRouterNamespaceSelector(t *testing.T) {
+	cr := &api.KieApp{
+		Spec: api.KieAppSpec{
+			Environment:   api.RhpamTrial,
+			Platform:      api.OpenShiftPlatform,
+			Exposure:      &api.KieAppExposure{Domain: "apps.example.com"},
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true},
+		},
+	}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+
+	cr.Spec.Platform = api.KubernetesPlatform
+	errs := ValidateKieApp(cr, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeRequired, errs[0].Type)
+	assert.Equal(t, "spec.networkPolicy.routerNamespaceSelector", errs[0].Field)
+
+	cr.Spec.NetworkPolicy.RouterNamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}}
+	assert.Empty(t, ValidateKieApp(cr, nil))
+}
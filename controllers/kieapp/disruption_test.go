@@ -0,0 +1,57 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcilePodDisruptionBudgets(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{KieAppObject: api.KieAppObject{Replicas: defaults.Pint32(2)}}},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	assert.Nil(t, service.List(context.TODO(), pdbs, client.InNamespace(name.Namespace)))
+	assert.Len(t, pdbs.Items, 1)
+	assert.Equal(t, "test-kieserver", pdbs.Items[0].Name)
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The PodDisruptionBudget should not be updated when unchanged")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Objects.Servers[0].Replicas = defaults.Pint32(1)
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	pdbs = &policyv1.PodDisruptionBudgetList{}
+	assert.Nil(t, service.List(context.TODO(), pdbs, client.InNamespace(name.Namespace)))
+	assert.Empty(t, pdbs.Items)
+}
@@ -0,0 +1,111 @@
+package kieapp
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the events emitted by the controller, the failures use the api.ReasonType of the failed condition
+const (
+	CreatedEventReason             = "Created"
+	UpdatedEventReason             = "Updated"
+	DeletedEventReason             = "Deleted"
+	KeystoreGeneratedEventReason   = "KeystoreGenerated"
+	TruststoreGeneratedEventReason = "TruststoreGenerated"
+	CAGeneratedEventReason         = "CAGenerated"
+	ImageStreamTagEventReason      = "ImageStreamTagCreated"
+	UpgradeEventReason             = "Upgrade"
+	UpgradeAvailableEventReason    = "UpgradeAvailable"
+	ConfigMapBackupEventReason     = "ConfigMapBackup"
+)
+
+// eventDeduplicationWindow is how long an identical event is not emitted again on the same object
+const eventDeduplicationWindow = 10 * time.Minute
+
+// emittedEvents remembers when events were last emitted, so repeated reconciles do not emit them again
+type emittedEvents struct {
+	mutex sync.Mutex
+	last  map[string]time.Time
+	// the value last reported by the events emitted only when it changes, by object and reason
+	values map[string]string
+}
+
+// shouldEmit returns false when the same event was emitted on the object within the deduplication window
+func (events *emittedEvents) shouldEmit(uid types.UID, eventType, reason, message string, now time.Time) bool {
+	events.mutex.Lock()
+	defer events.mutex.Unlock()
+	if events.last == nil {
+		events.last = map[string]time.Time{}
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s", uid, eventType, reason, message)
+	if emitted, found := events.last[key]; found && now.Sub(emitted) < eventDeduplicationWindow {
+		return false
+	}
+	// the events emitted outside the window are pruned whenever one is added, so the map only holds recent ones
+	for emittedKey, emitted := range events.last {
+		if now.Sub(emitted) >= eventDeduplicationWindow {
+			delete(events.last, emittedKey)
+		}
+	}
+	events.last[key] = now
+	return true
+}
+
+// shouldEmitChange returns false when an event of the same reason was already emitted on the object for the value
+func (events *emittedEvents) shouldEmitChange(uid types.UID, reason, value string) bool {
+	events.mutex.Lock()
+	defer events.mutex.Unlock()
+	if events.values == nil {
+		events.values = map[string]string{}
+	}
+	key := fmt.Sprintf("%s/%s", uid, reason)
+	if last, found := events.values[key]; found && last == value {
+		return false
+	}
+	events.values[key] = value
+	return true
+}
+
+// forget drops the events emitted on the object, once it is deleted
+func (events *emittedEvents) forget(uid types.UID) {
+	events.mutex.Lock()
+	defer events.mutex.Unlock()
+	prefix := string(uid) + "/"
+	for key := range events.last {
+		if strings.HasPrefix(key, prefix) {
+			delete(events.last, key)
+		}
+	}
+	for key := range events.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(events.values, key)
+		}
+	}
+}
+
+// recordEvent emits an event on the object, unless no recorder is configured or the event was recently emitted
+func (reconciler *KieAppReconciler) recordEvent(object client.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if reconciler.Recorder == nil || object == nil || object.GetUID() == "" {
+		return
+	}
+	message := fmt.Sprintf(messageFmt, args...)
+	if reconciler.events.shouldEmit(object.GetUID(), eventType, reason, message, time.Now()) {
+		reconciler.Recorder.Event(object, eventType, reason, message)
+	}
+}
+
+// recordChangeEvent emits an event on the object only when the value it reports changed since the last event of the
+// same reason, however long ago that was
+func (reconciler *KieAppReconciler) recordChangeEvent(object client.Object, eventType, reason, value, messageFmt string, args ...interface{}) {
+	if reconciler.Recorder == nil || object == nil || object.GetUID() == "" {
+		return
+	}
+	if reconciler.events.shouldEmitChange(object.GetUID(), reason, value) {
+		reconciler.Recorder.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+	}
+}
@@ -0,0 +1,117 @@
+package kieapp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func getEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestEmittedEvents(t *testing.T) {
+	events := &emittedEvents{}
+	now := time.Now()
+	assert.True(t, events.shouldEmit("uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service test", now))
+	assert.False(t, events.shouldEmit("uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service test", now.Add(time.Minute)))
+	assert.True(t, events.shouldEmit("other-uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service test", now))
+	assert.True(t, events.shouldEmit("uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service other", now))
+	assert.True(t, events.shouldEmit("uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service test", now.Add(eventDeduplicationWindow)))
+}
+
+func TestEmittedChangeEvents(t *testing.T) {
+	events := &emittedEvents{}
+	assert.True(t, events.shouldEmitChange("uid", UpgradeAvailableEventReason, "7.12.0"))
+	assert.False(t, events.shouldEmitChange("uid", UpgradeAvailableEventReason, "7.12.0"))
+	assert.True(t, events.shouldEmitChange("other-uid", UpgradeAvailableEventReason, "7.12.0"))
+	assert.True(t, events.shouldEmitChange("uid", UpgradeAvailableEventReason, "7.12.1"))
+	assert.False(t, events.shouldEmitChange("uid", UpgradeAvailableEventReason, "7.12.1"))
+}
+
+func TestPruneEmittedEvents(t *testing.T) {
+	events := &emittedEvents{}
+	now := time.Now()
+	assert.True(t, events.shouldEmit("uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service test", now))
+	assert.True(t, events.shouldEmit("other-uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service test", now.Add(time.Minute)))
+	assert.True(t, events.shouldEmit("other-uid", corev1.EventTypeNormal, CreatedEventReason, "Created Service other", now.Add(eventDeduplicationWindow)))
+	assert.Len(t, events.last, 2, "The expired event should be pruned")
+
+	assert.True(t, events.shouldEmitChange("uid", UpgradeAvailableEventReason, "7.12.0"))
+	assert.True(t, events.shouldEmitChange("other-uid", UpgradeAvailableEventReason, "7.12.0"))
+	events.forget("other-uid")
+	assert.Empty(t, events.last)
+	assert.Len(t, events.values, 1)
+	assert.True(t, events.shouldEmitChange("other-uid", UpgradeAvailableEventReason, "7.12.0"))
+}
+
+func TestRecordUpgradeAvailable(t *testing.T) {
+	cr := &api.KieApp{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid"}}
+	cr.Status.Applied.Version = constants.PriorVersion
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &KieAppReconciler{Recorder: recorder}
+	for i := 0; i < 3; i++ {
+		reconciler.recordUpgrade(cr, constants.PriorVersion)
+	}
+	assert.Equal(t, []string{"Normal UpgradeAvailable Version " + constants.CurrentVersion + " is available, automatic upgrades are disabled"}, getEvents(recorder))
+}
+
+func TestReconcileEvents(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	var created []string
+	for _, event := range getEvents(recorder) {
+		if strings.HasPrefix(event, "Normal Created Created Deployment ") {
+			created = append(created, event)
+		}
+	}
+	assert.Len(t, created, 1)
+	assert.Contains(t, created[0], "test-rhpamcentr")
+
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "A steady-state reconcile should not emit events")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Version = "6.4.0"
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	for i := 0; i < 2; i++ {
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+		assert.NotNil(t, err)
+	}
+	events := getEvents(recorder)
+	assert.Len(t, events, 1, "The configuration error should be emitted once")
+	assert.True(t, strings.HasPrefix(events[0], "Warning "+string(api.ConfigurationErrorReason)))
+}
@@ -0,0 +1,196 @@
+package kieapp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	oappsv1 "github.com/openshift/api/apps/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/status"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// addFinalizer makes sure the KieApp cannot be removed before its resources are cleaned up
+func (reconciler *KieAppReconciler) addFinalizer(ctx context.Context, instance *api.KieApp) error {
+	if controllerutil.ContainsFinalizer(instance, constants.KieAppFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopy())
+	controllerutil.AddFinalizer(instance, constants.KieAppFinalizer)
+	return reconciler.Service.Patch(ctx, instance, patch)
+}
+
+// finalize cleans up the resources created for a KieApp being deleted, then releases it
+func (reconciler *KieAppReconciler) finalize(ctx context.Context, instance *api.KieApp) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, constants.KieAppFinalizer) {
+		return reconcile.Result{}, nil
+	}
+	log := log.With("kind", instance.Kind, "name", instance.Name, "namespace", instance.Namespace)
+	steps := []struct {
+		message string
+		run     func(context.Context, *api.KieApp) error
+	}{
+		{"Releasing the retained resources", reconciler.releaseRetainedResources},
+		{"Removing the KIE server ConfigMaps", reconciler.removeKieServerConfigMaps},
+		{"Removing the ImageStreamTags", reconciler.removeImageStreamTags},
+		{"Removing the resources owned by the KieApp", reconciler.removeOwnedResources},
+	}
+	for _, step := range steps {
+		if status.SetDeleting(instance, step.message) {
+			if err := reconciler.writeStatus(ctx, instance); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		log.Info(step.message)
+		if err := step.run(ctx, instance); err != nil {
+			reconciler.setFailedStatus(instance, api.DeletionFailedReason, err)
+			return reconcile.Result{}, err
+		}
+	}
+	patch := client.MergeFrom(instance.DeepCopy())
+	controllerutil.RemoveFinalizer(instance, constants.KieAppFinalizer)
+	if err := reconciler.Service.Patch(ctx, instance, patch); err != nil {
+		return reconcile.Result{}, err
+	}
+	trackedApps.untrack(client.ObjectKeyFromObject(instance))
+	reconciler.events.forget(instance.GetUID())
+	return reconcile.Result{}, nil
+}
+
+func getDeletionPolicy(instance *api.KieApp) api.KieAppDeletionPolicy {
+	policy := api.KieAppDeletionPolicy{
+		PersistentVolumeClaims: api.DeleteDeletionPolicy,
+		Databases:              api.DeleteDeletionPolicy,
+	}
+	if instance.Spec.DeletionPolicy != nil {
+		if instance.Spec.DeletionPolicy.PersistentVolumeClaims != "" {
+			policy.PersistentVolumeClaims = instance.Spec.DeletionPolicy.PersistentVolumeClaims
+		}
+		if instance.Spec.DeletionPolicy.Databases != "" {
+			policy.Databases = instance.Spec.DeletionPolicy.Databases
+		}
+	}
+	return policy
+}
+
+// releaseRetainedResources removes the KieApp owner reference from the resources the deletion policy retains,
+// so they are neither deleted by the operator nor garbage collected
+func (reconciler *KieAppReconciler) releaseRetainedResources(ctx context.Context, instance *api.KieApp) error {
+	policy := getDeletionPolicy(instance)
+	if policy.PersistentVolumeClaims != api.RetainDeletionPolicy && policy.Databases != api.RetainDeletionPolicy {
+		return nil
+	}
+	databases := map[string]bool{}
+	if policy.Databases == api.RetainDeletionPolicy {
+		// the environment is built from a copy, to leave the status of the KieApp free of resolved credentials
+		env, err := defaults.GetEnvironment(instance.DeepCopy(), reconciler.Service)
+		if err != nil {
+			return fmt.Errorf("unable to find the databases to retain: %v", err)
+		}
+		// the credentials Secret holds the database passwords the operator generated
+		databases[defaults.GetCredentialsSecretName(instance)] = true
+		for _, database := range env.Databases {
+			for _, dc := range database.DeploymentConfigs {
+				databases[dc.Name] = true
+			}
+			for _, statefulSet := range database.StatefulSets {
+				databases[statefulSet.Name] = true
+			}
+			for _, service := range database.Services {
+				databases[service.Name] = true
+			}
+			for _, pvc := range database.PersistentVolumeClaims {
+				databases[pvc.Name] = true
+			}
+		}
+	}
+	deployed, err := reconciler.getDeployedResources(instance)
+	if err != nil {
+		return err
+	}
+	for resourceType, objects := range deployed {
+		retainPVCs := resourceType == reflect.TypeOf(corev1.PersistentVolumeClaim{}) && policy.PersistentVolumeClaims == api.RetainDeletionPolicy
+		for _, object := range objects {
+			if !retainPVCs && !databases[object.GetName()] {
+				continue
+			}
+			var ownerRefs []metav1.OwnerReference
+			for _, ownerRef := range object.GetOwnerReferences() {
+				if ownerRef.UID != instance.UID {
+					ownerRefs = append(ownerRefs, ownerRef)
+				}
+			}
+			object.SetOwnerReferences(ownerRefs)
+			log.Infof("Retaining %v %s", resourceType.Name(), object.GetName())
+			if err := reconciler.Service.Update(ctx, object); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeKieServerConfigMaps deletes the ConfigMaps the KIE servers of the KieApp created to hold their state,
+// including the ones relabeled as DETACHED
+func (reconciler *KieAppReconciler) removeKieServerConfigMaps(ctx context.Context, instance *api.KieApp) error {
+	deployed, err := reconciler.getDeployedResources(instance)
+	if err != nil {
+		return err
+	}
+	owners := map[string]bool{}
+	for _, object := range deployed[reflect.TypeOf(oappsv1.DeploymentConfig{})] {
+		owners["DeploymentConfig/"+object.GetName()] = true
+	}
+	for _, object := range deployed[reflect.TypeOf(appsv1.Deployment{})] {
+		owners["Deployment/"+object.GetName()] = true
+	}
+	cmList := &corev1.ConfigMapList{}
+	if err := reconciler.Service.List(ctx, cmList, client.InNamespace(instance.Namespace), client.HasLabels{constants.KieServerCMLabel}); err != nil {
+		return err
+	}
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		for _, ownerRef := range cm.OwnerReferences {
+			if owners[ownerRef.Kind+"/"+ownerRef.Name] {
+				log.Infof("Removing KIE server ConfigMap %s", cm.Name)
+				if err := reconciler.Service.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// removeImageStreamTags deletes the ImageStreamTags the operator created for the KieApp
+func (reconciler *KieAppReconciler) removeImageStreamTags(ctx context.Context, instance *api.KieApp) error {
+	for _, tagName := range instance.Status.ImageStreamTags {
+		log.Infof("Removing ImageStreamTag %s", tagName)
+		err := reconciler.Service.ImageStreamTags(instance.Namespace).Delete(ctx, tagName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	instance.Status.ImageStreamTags = nil
+	return nil
+}
+
+// removeOwnedResources deletes the resources still owned by the KieApp, along with its ConsoleLink
+func (reconciler *KieAppReconciler) removeOwnedResources(ctx context.Context, instance *api.KieApp) error {
+	deployed, err := reconciler.getDeployedResources(instance)
+	if err != nil {
+		return err
+	}
+	_, err = reconciler.reconcileResources(instance, nil, deployed)
+	return err
+}
@@ -0,0 +1,116 @@
+package kieapp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestFinalizerCleanup(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:    api.RhpamAuthoring,
+			Platform:       api.KubernetesPlatform,
+			Exposure:       &api.KieAppExposure{Domain: "apps.example.com"},
+			DeletionPolicy: &api.KieAppDeletionPolicy{PersistentVolumeClaims: api.RetainDeletionPolicy},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: record.NewFakeRecorder(100)}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Contains(t, cr.Finalizers, constants.KieAppFinalizer)
+	assert.NotEmpty(t, reconciler.events.last)
+	deployed, err := reconciler.getDeployedResources(cr)
+	assert.Nil(t, err)
+	pvcs := deployed[reflect.TypeOf(corev1.PersistentVolumeClaim{})]
+	assert.NotEmpty(t, pvcs)
+	assert.NotEmpty(t, deployed[reflect.TypeOf(appsv1.Deployment{})])
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "kieserver-state",
+			Namespace:       name.Namespace,
+			Labels:          map[string]string{constants.KieServerCMLabel: "DETACHED"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: deployed[reflect.TypeOf(appsv1.Deployment{})][0].GetName()}},
+		},
+	}
+	assert.Nil(t, service.Create(context.TODO(), cm))
+
+	assert.Nil(t, service.Delete(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	assert.True(t, errors.IsNotFound(service.Get(context.TODO(), name, &api.KieApp{})), "KieApp should be released")
+	assert.Empty(t, reconciler.events.last, "The events emitted on the released KieApp should be forgotten")
+	assert.True(t, errors.IsNotFound(service.Get(context.TODO(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})))
+	deploymentList := &appsv1.DeploymentList{}
+	assert.Nil(t, service.List(context.TODO(), deploymentList, client.InNamespace(name.Namespace)))
+	assert.Empty(t, deploymentList.Items)
+	for _, pvc := range pvcs {
+		retained := &corev1.PersistentVolumeClaim{}
+		assert.Nil(t, service.Get(context.TODO(), client.ObjectKeyFromObject(pvc), retained))
+		assert.Empty(t, retained.OwnerReferences)
+	}
+}
+
+func TestFinalizerRetainsDatabaseCredentials(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:    api.RhpamProduction,
+			Platform:       api.KubernetesPlatform,
+			Exposure:       &api.KieAppExposure{Domain: "apps.example.com"},
+			DeletionPolicy: &api.KieAppDeletionPolicy{Databases: api.RetainDeletionPolicy},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	credentials := types.NamespacedName{Name: "test-credentials", Namespace: name.Namespace}
+	secret := &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), credentials, secret))
+	assert.NotEmpty(t, secret.OwnerReferences)
+	password := secret.Data["dbPassword"]
+	assert.NotEmpty(t, password)
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Nil(t, service.Delete(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	assert.True(t, errors.IsNotFound(service.Get(context.TODO(), name, &api.KieApp{})), "KieApp should be released")
+	secret = &corev1.Secret{}
+	assert.Nil(t, service.Get(context.TODO(), credentials, secret))
+	assert.Empty(t, secret.OwnerReferences)
+	assert.Equal(t, password, secret.Data["dbPassword"])
+}
+
+func TestGetDeletionPolicy(t *testing.T) {
+	cr := &api.KieApp{}
+	assert.Equal(t, api.KieAppDeletionPolicy{PersistentVolumeClaims: api.DeleteDeletionPolicy, Databases: api.DeleteDeletionPolicy}, getDeletionPolicy(cr))
+	cr.Spec.DeletionPolicy = &api.KieAppDeletionPolicy{Databases: api.RetainDeletionPolicy}
+	assert.Equal(t, api.KieAppDeletionPolicy{PersistentVolumeClaims: api.DeleteDeletionPolicy, Databases: api.RetainDeletionPolicy}, getDeletionPolicy(cr))
+}
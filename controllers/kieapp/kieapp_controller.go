@@ -17,6 +17,7 @@ limitations under the License.
 package kieapp
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/RHsyseng/operator-utils/pkg/logs"
@@ -31,6 +32,8 @@ import (
 	oimagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
 	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
 	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
 	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/shared"
@@ -38,20 +41,26 @@ import (
 	"github.com/spolti/kie-cloud-operator-new/core/logger"
 	"golang.org/x/mod/semver"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"reflect"
-	"regexp"
 	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 
 	api "github.com/spolti/kie-cloud-operator-new/api/v2"
 )
@@ -64,11 +73,25 @@ type KieAppReconciler struct {
 	Scheme     *runtime.Scheme
 	Service    kubernetes.PlatformService
 	OcpVersion string
+	// Platform detected for the cluster, used when the KieApp does not request one
+	Platform api.PlatformType
+	// Recorder emits the events of the KieApps, none are emitted when not set
+	Recorder record.EventRecorder
+	events   emittedEvents
 }
 
 //+kubebuilder:rbac:groups=app.kiegroup.org,resources=kieapps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=app.kiegroup.org,resources=kieapps/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=app.kiegroup.org,resources=kieapps/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.x-k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -113,11 +136,13 @@ func (reconciler *KieAppReconciler) Reconcile(ctx context.Context, request ctrl.
 				Name:      request.Name,
 				Namespace: request.Namespace,
 			}
+			instance.Status.Applied.Platform = reconciler.getPlatform(instance)
 			deployed, err := reconciler.getDeployedResources(instance)
 			if err != nil {
 				return reconcile.Result{}, err
 			}
 			_, err = reconciler.reconcileResources(instance, nil, deployed)
+			trackedApps.untrack(request.NamespacedName)
 			return reconcile.Result{}, err
 		}
 		// Error reading the object - requeue the request.
@@ -125,12 +150,31 @@ func (reconciler *KieAppReconciler) Reconcile(ctx context.Context, request ctrl.
 		return reconcile.Result{}, err
 	}
 
+	if instance.GetDeletionTimestamp() == nil {
+		if err := reconciler.addFinalizer(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	instance.Status.Applied.Platform = reconciler.getPlatform(instance)
+	if instance.GetDeletionTimestamp() != nil {
+		return reconciler.finalize(ctx, instance)
+	}
+	defer trackedApps.track(instance)
+	if instance.Spec.Paused {
+		log.Info("KieApp is paused, only its status is updated")
+		return reconciler.reconcilePaused(ctx, instance)
+	}
+
 	//Obtain in-memory representation of basic environment being requested:
+	appliedVersion := instance.Status.Applied.Version
+	environmentStart := time.Now()
 	env, err := defaults.GetEnvironment(instance, reconciler.Service)
+	observePhase(environmentPhase, environmentStart, err)
 	if err != nil {
 		reconciler.setFailedStatus(instance, api.ConfigurationErrorReason, err)
 		return reconcile.Result{}, err
 	}
+	reconciler.recordUpgrade(instance, appliedVersion)
 
 	//Verify the external references exist
 	err = reconciler.verifyExternalReferences(instance)
@@ -139,21 +183,12 @@ func (reconciler *KieAppReconciler) Reconcile(ctx context.Context, request ctrl.
 		return reconcile.Result{}, err
 	}
 
-	//Get requested routes based on environment template:
-	requestedRoutes := getRequestedRoutes(env, instance)
-	//Then check if all these routes are already created:
-	reader := read.New(reconciler.Service).WithNamespace(instance.Namespace).WithOwnerObject(instance)
-	deployedRoutes, err := reader.List(&routev1.RouteList{})
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-	delta := compare.DefaultComparator().CompareArrays(deployedRoutes, requestedRoutes)
-
-	//If not all routes are created, then create the missing ones. Other changes can be applied later.
-	if len(delta.Added) > 0 {
-		log.Debug("Will create %d routes that were not found", len(delta.Added))
-		writer := write.New(reconciler.Service).WithOwnerController(instance, reconciler.Service.GetScheme())
-		added, err := writer.AddResources(delta.Added)
+	var deployedRoutes []client.Object
+	if !defaults.IsKubernetes(instance) && defaults.GetExposureType(instance) == api.RouteExposure {
+		routesStart := time.Now()
+		var added bool
+		deployedRoutes, added, err = reconciler.createMissingRoutes(instance, env)
+		observePhase(routesPhase, routesStart, err)
 		if err != nil {
 			return reconcile.Result{}, err
 		} else if added {
@@ -172,12 +207,38 @@ func (reconciler *KieAppReconciler) Reconcile(ctx context.Context, request ctrl.
 	}
 
 	//With route hostnames now available, set remaining environment configuration:
+	keystoreStart := time.Now()
 	env, err = reconciler.setEnvironmentProperties(instance, env, deployedRoutes, caConfigMap)
+	observePhase(keystorePhase, keystoreStart, err)
 	if err != nil {
 		// requeue if secret request throws an error
 		// we shouldn't reconcile the deployment with an incorrect or missing keystore secret
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(500) * time.Millisecond}, err
 	}
+	env = defaults.ReferenceCredentials(env, instance)
+	env = defaults.ConvertRoutes(env, instance)
+	env = defaults.AddMonitoring(env, instance)
+	env = defaults.AddAutoscaling(env, instance)
+	env = defaults.AddPodDisruptionBudgets(env, instance)
+	env = defaults.AddNetworkPolicies(env, instance)
+	env = defaults.SetSecurityContexts(env, instance)
+	if defaults.IsKubernetes(instance) {
+		env = defaults.ConvertToKubernetes(env, instance)
+	}
+	env = defaults.SetCommonMetadata(env, instance)
+	var overlays []api.AppliedOverlay
+	env, overlays, err = defaults.ApplyOverlays(env, instance)
+	if err != nil {
+		reconciler.setFailedStatus(instance, api.ConfigurationErrorReason, err)
+		return reconcile.Result{}, err
+	}
+	instance.Status.Overlays = overlays
+	if defaults.IsRestricted(instance) {
+		if err := defaults.ValidateRestrictedPodSecurity(env); err != nil {
+			reconciler.setFailedStatus(instance, api.ConfigurationErrorReason, err)
+			return reconcile.Result{}, err
+		}
+	}
 	//Create a list of objects that should be deployed
 	requestedResources := reconciler.getKubernetesResources(instance, env)
 	for index := range requestedResources {
@@ -192,15 +253,34 @@ func (reconciler *KieAppReconciler) Reconcile(ctx context.Context, request ctrl.
 		reconciler.setFailedStatus(instance, api.UnknownReason, err)
 		return reconcile.Result{}, err
 	}
-	setDeploymentStatus(instance, deployed[reflect.TypeOf(oappsv1.DeploymentConfig{})])
+	setDeploymentStatus(instance, deployed)
+	keepAutoscaledReplicas(requestedResources, deployed)
+	setSuspendedReplicas(instance, requestedResources, deployed)
 
+	if instance.Spec.RequireApproval {
+		plan := getPlan(instance, getDeltas(requestedResources, deployed), deployed)
+		if plan != nil && instance.GetAnnotations()[api.ApprovedPlanAnnotation] != plan.Hash {
+			return reconciler.awaitApproval(ctx, instance, plan)
+		}
+	}
+	instance.Status.Plan = nil
+	if !instance.Spec.Suspended {
+		// the replicas recorded when the KieApp was suspended are applied along with the other requested resources
+		instance.Status.SuspendedReplicas = nil
+	}
+
+	applyStart := time.Now()
 	hasUpdates, err := reconciler.reconcileResources(instance, requestedResources, deployed)
+	observePhase(applyPhase, applyStart, err)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// Check the KieServer ConfigMaps for necessary changes
-	reconciler.checkKieServerConfigMap(instance, env)
+	// Check the KieServer ConfigMaps for necessary changes, suspended kieservers keep their state. The requested
+	// resources are approved by now, the cleared plan can't tell it anymore.
+	if !instance.Spec.Suspended {
+		reconciler.checkKieServerConfigMap(instance, env)
+	}
 
 	// Fetch the cached KieApp instance
 	cachedInstance := &api.KieApp{}
@@ -218,13 +298,59 @@ func (reconciler *KieAppReconciler) Reconcile(ctx context.Context, request ctrl.
 	}
 
 	// Update CR Status if needed
-	return reconciler.checkStatus(ctx, instance, cachedInstance, hasUpdates)
+	result, err := reconciler.checkStatus(ctx, instance, cachedInstance, hasUpdates)
+	if err == nil && !result.Requeue && !isCertManaged(instance) {
+		// come back when the next generated certificate is due for renewal, cert-manager renews the ones it issues
+		result.RequeueAfter = getNextRenewal(instance)
+	}
+	return result, err
+}
+
+// recordUpgrade emits the upgrade decisions taken while building the environment of the KieApp
+func (reconciler *KieAppReconciler) recordUpgrade(instance *api.KieApp, appliedVersion string) {
+	version := instance.Status.Applied.Version
+	if appliedVersion != "" && appliedVersion != version {
+		reconciler.recordEvent(instance, corev1.EventTypeNormal, UpgradeEventReason, "Upgrading from version %s to %s", appliedVersion, version)
+	} else if version != constants.CurrentVersion && !instance.Status.Applied.Upgrades.Enabled {
+		reconciler.recordChangeEvent(instance, corev1.EventTypeNormal, UpgradeAvailableEventReason, constants.CurrentVersion, "Version %s is available, automatic upgrades are disabled", constants.CurrentVersion)
+	}
+}
+
+// createMissingRoutes creates the requested routes that are not found, so their hostnames can be loaded.
+// Other changes to the routes are applied later, as are the missing routes of a KieApp requiring approval.
+func (reconciler *KieAppReconciler) createMissingRoutes(instance *api.KieApp, env api.Environment) ([]client.Object, bool, error) {
+	//Get requested routes based on environment template:
+	requestedRoutes := getRequestedRoutes(env, instance)
+	//Then check if all these routes are already created:
+	reader := read.New(reconciler.Service).WithNamespace(instance.Namespace).WithOwnerObject(instance)
+	deployedRoutes, err := reader.List(&routev1.RouteList{})
+	if err != nil {
+		return nil, false, err
+	}
+	delta := compare.DefaultComparator().CompareArrays(deployedRoutes, requestedRoutes)
+	if len(delta.Added) == 0 {
+		return deployedRoutes, false, nil
+	}
+	if instance.Spec.RequireApproval {
+		// the routes are created along with the other resources of the approved plan, a follow-up plan then applies
+		// the hostnames they are assigned
+		return deployedRoutes, false, nil
+	}
+	log.Debug("Will create %d routes that were not found", len(delta.Added))
+	writer := write.New(reconciler.Service).WithOwnerController(instance, reconciler.Service.GetScheme())
+	added, err := writer.AddResources(delta.Added)
+	if added {
+		countResourceChanges("Route", createdAction, len(delta.Added))
+	}
+	return deployedRoutes, added, err
 }
 
 func (reconciler *KieAppReconciler) checkStatus(ctx context.Context, instance, cachedInstance *api.KieApp, hasUpdates bool) (reconcile.Result, error) {
 	var requeue bool
 	if hasUpdates {
 		requeue = status.SetProvisioning(instance)
+	} else if instance.Spec.Suspended {
+		requeue = status.SetSuspended(instance)
 	} else {
 		requeue = status.SetDeployed(instance)
 	}
@@ -234,7 +360,7 @@ func (reconciler *KieAppReconciler) checkStatus(ctx context.Context, instance, c
 func (reconciler *KieAppReconciler) updateStatus(ctx context.Context, instance, cachedInstance *api.KieApp, requeue bool) (reconcile.Result, error) {
 	if reconciler.hasStatusChanges(instance, cachedInstance) {
 		if instance.ResourceVersion == cachedInstance.ResourceVersion {
-			if err := reconciler.Service.Status().Update(ctx, instance); err != nil {
+			if err := reconciler.writeStatus(ctx, instance); err != nil {
 				return reconcile.Result{}, err
 			}
 		} else {
@@ -244,35 +370,70 @@ func (reconciler *KieAppReconciler) updateStatus(ctx context.Context, instance,
 	return reconcile.Result{Requeue: requeue}, nil
 }
 
+// writeStatus updates the status of the KieApp, after removing the credentials resolved into its applied spec
+func (reconciler *KieAppReconciler) writeStatus(ctx context.Context, instance *api.KieApp) error {
+	defaults.ClearCredentials(instance)
+	return reconciler.Service.Status().Update(ctx, instance)
+}
+
 func (reconciler *KieAppReconciler) reconcileResources(ownerController metav1.Object, requestedResources []client.Object, deployed map[reflect.Type][]client.Object) (bool, error) {
-	writer := write.New(reconciler.Service).WithOwnerController(ownerController, reconciler.Service.GetScheme())
-	//Compare what's deployed with what should be deployed
-	requested := compare.NewMapBuilder().Add(requestedResources...).ResourceMap()
-	comparator := getComparator()
-	deltas := comparator.Compare(deployed, requested)
+	writer := write.New(reconciler.Service).WithOwnerController(ownerController, reconciler.Service.GetScheme()).
+		WithCustomUpdateHooks(newExternalAnnotationHooks())
 	var hasUpdates bool
-	for resourceType, delta := range deltas {
+	for resourceType, delta := range getDeltas(requestedResources, deployed) {
 		if !delta.HasChanges() {
 			continue
 		}
 		log.Debugf("Will create %d, update %d, and delete %d instances of %v", len(delta.Added), len(delta.Updated), len(delta.Removed), resourceType)
+		owner, _ := ownerController.(client.Object)
 		added, err := writer.AddResources(delta.Added)
 		if err != nil {
+			reconciler.recordEvent(owner, corev1.EventTypeWarning, CreatedEventReason, "Failed to create %v: %v", resourceType.Name(), err)
 			return false, err
 		}
+		if added {
+			countResourceChanges(resourceType.Name(), createdAction, len(delta.Added))
+			reconciler.recordEvent(owner, corev1.EventTypeNormal, CreatedEventReason, "Created %v %s", resourceType.Name(), getNames(delta.Added))
+		}
 		updated, err := writer.UpdateResources(deployed[resourceType], delta.Updated)
 		if err != nil {
+			reconciler.recordEvent(owner, corev1.EventTypeWarning, UpdatedEventReason, "Failed to update %v: %v", resourceType.Name(), err)
 			return false, err
 		}
+		if updated {
+			countResourceChanges(resourceType.Name(), updatedAction, len(delta.Updated))
+			reconciler.recordEvent(owner, corev1.EventTypeNormal, UpdatedEventReason, "Updated %v %s", resourceType.Name(), getNames(delta.Updated))
+		}
 		removed, err := writer.RemoveResources(delta.Removed)
 		if err != nil {
+			reconciler.recordEvent(owner, corev1.EventTypeWarning, DeletedEventReason, "Failed to delete %v: %v", resourceType.Name(), err)
 			return false, err
 		}
+		if removed {
+			countResourceChanges(resourceType.Name(), deletedAction, len(delta.Removed))
+			reconciler.recordEvent(owner, corev1.EventTypeNormal, DeletedEventReason, "Deleted %v %s", resourceType.Name(), getNames(delta.Removed))
+		}
 		hasUpdates = hasUpdates || added || updated || removed
 	}
 	return hasUpdates, nil
 }
 
+func getNames(objects []client.Object) string {
+	names := make([]string, len(objects))
+	for i, object := range objects {
+		names[i] = object.GetName()
+	}
+	return strings.Join(names, ", ")
+}
+
+// getDeltas compares what's deployed with what should be deployed
+func getDeltas(requestedResources []client.Object, deployed map[reflect.Type][]client.Object) map[reflect.Type]compare.ResourceDelta {
+	setManagedAnnotations(requestedResources)
+	requested := compare.NewMapBuilder().Add(requestedResources...).ResourceMap()
+	comparator := getComparator()
+	return comparator.Compare(withoutExternalAnnotations(deployed, requested), requested)
+}
+
 func isNamespaced(resource client.Object) bool {
 	if reflect.TypeOf(resource) == reflect.TypeOf(&consolev1.ConsoleLink{}) {
 		return false
@@ -339,10 +500,69 @@ func getComparator() compare.MapComparator {
 		return equal
 	})
 
+	setSpecComparator(resourceComparator, reflect.TypeOf(networkingv1.Ingress{}), func(object client.Object) interface{} {
+		return object.(*networkingv1.Ingress).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(gatewayv1alpha1.HTTPRoute{}), func(object client.Object) interface{} {
+		return object.(*gatewayv1alpha1.HTTPRoute).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(networkingv1.NetworkPolicy{}), func(object client.Object) interface{} {
+		return object.(*networkingv1.NetworkPolicy).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(policyv1.PodDisruptionBudget{}), func(object client.Object) interface{} {
+		return object.(*policyv1.PodDisruptionBudget).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(autoscalingv2beta2.HorizontalPodAutoscaler{}), func(object client.Object) interface{} {
+		return object.(*autoscalingv2beta2.HorizontalPodAutoscaler).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(certmanagerv1.Certificate{}), func(object client.Object) interface{} {
+		return object.(*certmanagerv1.Certificate).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(monv1.ServiceMonitor{}), func(object client.Object) interface{} {
+		return object.(*monv1.ServiceMonitor).Spec
+	})
+
+	setSpecComparator(resourceComparator, reflect.TypeOf(monv1.PrometheusRule{}), func(object client.Object) interface{} {
+		return object.(*monv1.PrometheusRule).Spec
+	})
+
 	return compare.MapComparator{Comparator: resourceComparator}
 }
 
-func setDeploymentStatus(instance *api.KieApp, resources []client.Object) {
+// setSpecComparator compares the resources of the given type by their name, namespace, labels, annotations and the spec
+// returned by getSpec
+func setSpecComparator(resourceComparator compare.ResourceComparator, resourceType reflect.Type, getSpec func(client.Object) interface{}) {
+	resourceComparator.SetComparator(resourceType, func(deployed client.Object, requested client.Object) bool {
+		var pairs [][2]interface{}
+		pairs = append(pairs, [2]interface{}{deployed.GetName(), requested.GetName()})
+		pairs = append(pairs, [2]interface{}{deployed.GetNamespace(), requested.GetNamespace()})
+		pairs = append(pairs, [2]interface{}{deployed.GetLabels(), requested.GetLabels()})
+		pairs = append(pairs, [2]interface{}{deployed.GetAnnotations(), requested.GetAnnotations()})
+		pairs = append(pairs, [2]interface{}{getSpec(deployed), getSpec(requested)})
+		equal := compare.EqualPairs(pairs)
+		if !equal {
+			log.Infof("Resources are not equal -- deployed %+v -- requested %+v", deployed, requested)
+		}
+		return equal
+	})
+}
+
+func setDeploymentStatus(instance *api.KieApp, deployed map[reflect.Type][]client.Object) {
+	if defaults.IsKubernetes(instance) {
+		var deployments []appsv1.Deployment
+		for _, resource := range deployed[reflect.TypeOf(appsv1.Deployment{})] {
+			deployments = append(deployments, *resource.(*appsv1.Deployment))
+		}
+		instance.Status.Deployments = olm.GetDeploymentStatus(deployments)
+		return
+	}
+	resources := deployed[reflect.TypeOf(oappsv1.DeploymentConfig{})]
 	var dcs []oappsv1.DeploymentConfig
 	for index := range resources {
 		dc := resources[index].(*oappsv1.DeploymentConfig)
@@ -362,6 +582,12 @@ func (reconciler *KieAppReconciler) verifyExternalReferences(cr *api.KieApp) err
 			err = reconciler.verifyExternalReference(cr.GetNamespace(), cr.Status.Applied.Objects.Console.GitHooks.From)
 		}
 	}
+	if err == nil && isCertManaged(cr) {
+		err = reconciler.verifyCertManager(cr.GetNamespace())
+	}
+	if err == nil && isAutoscaled(cr) {
+		err = reconciler.verifyAutoscaling(cr.GetNamespace())
+	}
 	return err
 }
 
@@ -418,7 +644,8 @@ func (reconciler *KieAppReconciler) hasStatusChanges(instance, cachedInstance *a
 
 func (reconciler *KieAppReconciler) setFailedStatus(instance *api.KieApp, reason api.ReasonType, err error) {
 	status.SetFailed(instance, reason, err)
-	if updateError := reconciler.Service.Status().Update(context.TODO(), instance); updateError != nil {
+	reconciler.recordEvent(instance, corev1.EventTypeWarning, string(reason), "%v", err)
+	if updateError := reconciler.writeStatus(context.TODO(), instance); updateError != nil {
 		log.Warn("Unable to update object after receiving failed status. ", err)
 	}
 }
@@ -445,42 +672,9 @@ func (reconciler *KieAppReconciler) createLocalImageTag(tagRefName, imageURL str
 	if len(result) == 1 {
 		result = append(result, "latest")
 	}
-	product := defaults.GetProduct(cr.Status.Applied.Environment)
 	tagName := fmt.Sprintf("%s:%s", result[0], result[1])
-	imageName := tagName
-	major, _, _ := defaults.GetMajorMinorMicro(cr.Status.Applied.Version)
-	regContext := fmt.Sprintf("%s-%s", product, major)
-	if _, _, imageContext := defaults.GetImage(imageURL); imageContext != "" {
-		regContext = imageContext
-	}
-
-	// default registry settings
-	registry := &api.KieAppRegistry{
-		Insecure: logs.GetBoolEnv("INSECURE"),
-	}
-	if cr.Status.Applied.ImageRegistry != nil {
-		registry = cr.Status.Applied.ImageRegistry
-	}
-	if registry.Registry == "" {
-		registry.Registry = logs.GetEnv("REGISTRY", constants.ImageRegistry)
-	}
-	registryAddress := registry.Registry
-	if strings.Contains(result[0], "datagrid") {
-		registryAddress = constants.ImageRegistry
-		regContext = "jboss-datagrid-7"
-	} else if strings.Contains(result[0], "amq-broker-7") {
-		registryAddress = constants.ImageRegistry
-		regContext = "amq-broker-7"
-		if strings.Contains(result[0], "scaledown") {
-			regContext = "amq-broker-7-tech-preview"
-		}
-	} else if result[0] == "postgresql" || result[0] == "mysql" {
-		registryAddress = constants.ImageRegistry
-		regContext = "rhscl"
-		pattern := regexp.MustCompile("[0-9]+")
-		imageName = fmt.Sprintf("%s-%s-rhel7:%s", result[0], strings.Join(pattern.FindAllString(result[1], -1), ""), "latest")
-	}
-	registryURL := fmt.Sprintf("%s/%s/%s", registryAddress, regContext, imageName)
+	registry := defaults.GetImageRegistry(cr)
+	registryURL := defaults.GetRegistryImageURL(tagRefName, imageURL, cr)
 
 	isnew := &oimagev1.ImageStreamTag{
 		ObjectMeta: metav1.ObjectMeta{
@@ -509,8 +703,16 @@ func (reconciler *KieAppReconciler) createLocalImageTag(tagRefName, imageURL str
 	_, err := reconciler.Service.ImageStreamTags(isnew.Namespace).Create(context.TODO(), isnew, metav1.CreateOptions{})
 	if err != nil && !errors.IsAlreadyExists(err) {
 		log.Error("Issue creating object. ", err)
+		reconciler.recordEvent(cr, corev1.EventTypeWarning, ImageStreamTagEventReason, "Failed to create ImageStreamTag %s from %s: %v", tagName, registryURL, err)
 		return err
 	}
+	if err == nil {
+		reconciler.recordEvent(cr, corev1.EventTypeNormal, ImageStreamTagEventReason, "Created ImageStreamTag %s from %s", tagName, registryURL)
+	}
+	if _, found := shared.Find(cr.Status.ImageStreamTags, tagName); !found && err == nil {
+		// tracked so the tag is removed along with the KieApp
+		cr.Status.ImageStreamTags = append(cr.Status.ImageStreamTags, tagName)
+	}
 	return nil
 }
 
@@ -531,32 +733,39 @@ func (reconciler *KieAppReconciler) loadRoutes(requestedRoutes []client.Object)
 }
 
 func (reconciler *KieAppReconciler) setEnvironmentProperties(cr *api.KieApp, env api.Environment, routes []client.Object, caConfigMap *corev1.ConfigMap) (api.Environment, error) {
-	if defaults.IsOcpCA(cr) {
-		secret, err := reconciler.generateTruststoreSecret(
-			cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret,
-			cr,
-			caConfigMap,
-		)
+	var ca *shared.CertificateAuthority
+	// the keystores and truststore mounted by the pods, whose hash triggers their rollout
+	var certificates []corev1.Secret
+	cr.Status.Certificates = nil
+	if !cr.Status.Applied.CommonConfig.DisableSsl && !isCertManaged(cr) {
+		caSecret, err := reconciler.generateCASecret(fmt.Sprintf(constants.CASecret, cr.Status.Applied.CommonConfig.ApplicationName), cr)
 		if err != nil {
 			return api.Environment{}, err
 		}
-		env.Others[0].Secrets = append(env.Others[0].Secrets, secret)
+		// a planned CA holds no certificate yet, the keystores it signs are then planned as well
+		if ca, err = shared.ParseCA(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey]); err != nil && !isPlanPending(cr) {
+			return api.Environment{}, err
+		}
+		env.Others[0].Secrets = append(env.Others[0].Secrets, caSecret)
 	}
-
 	// console keystore generation
 	if !env.Console.Omit {
 		consoleCN := reconciler.setConsoleHost(cr, env, routes)
 		defaults.ConfigureHostname(&env.Console, cr, consoleCN)
 		if cr.Status.Applied.Objects.Console.KeystoreSecret == "" && !cr.Status.Applied.CommonConfig.DisableSsl {
-			secret, err := reconciler.generateKeystoreSecret(
-				fmt.Sprintf(constants.KeystoreSecret, strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "businesscentral"}, "-")),
+			secret, err := reconciler.getKeystoreSecret(
+				strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "businesscentral"}, "-"),
 				consoleCN,
+				&env.Console,
 				cr,
+				ca,
 			)
 			if err != nil {
 				return api.Environment{}, err
+			} else if secret.Name != "" {
+				env.Console.Secrets = append(env.Console.Secrets, secret)
+				certificates = append(certificates, secret)
 			}
-			env.Console.Secrets = append(env.Console.Secrets, secret)
 		}
 	}
 
@@ -565,15 +774,19 @@ func (reconciler *KieAppReconciler) setEnvironmentProperties(cr *api.KieApp, env
 		consoleCN := reconciler.setConsoleHost(cr, env, routes)
 		defaults.ConfigureHostname(&env.Dashbuilder, cr, consoleCN)
 		if cr.Status.Applied.Objects.Dashbuilder.KeystoreSecret == "" {
-			secret, err := reconciler.generateKeystoreSecret(
-				fmt.Sprintf(constants.KeystoreSecret, strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "dashbuilder"}, "-")),
+			secret, err := reconciler.getKeystoreSecret(
+				strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "dashbuilder"}, "-"),
 				consoleCN,
+				&env.Dashbuilder,
 				cr,
+				ca,
 			)
 			if err != nil {
 				return api.Environment{}, err
+			} else if secret.Name != "" {
+				env.Dashbuilder.Secrets = append(env.Dashbuilder.Secrets, secret)
+				certificates = append(certificates, secret)
 			}
-			env.Dashbuilder.Secrets = append(env.Dashbuilder.Secrets, secret)
 		}
 	}
 
@@ -586,7 +799,7 @@ func (reconciler *KieAppReconciler) setEnvironmentProperties(cr *api.KieApp, env
 		for _, rt := range server.Routes {
 			if checkTLS(rt.Spec.TLS) {
 				// use host of first tls route in env template
-				serverCN = reconciler.GetRouteHost(rt, routes)
+				serverCN = reconciler.getExposedHost(cr, rt, routes)
 				break
 			}
 		}
@@ -596,15 +809,13 @@ func (reconciler *KieAppReconciler) setEnvironmentProperties(cr *api.KieApp, env
 		defaults.ConfigureHostname(&server, cr, serverCN)
 		serverSet, kieDeploymentName := defaults.GetServerSet(cr, i)
 		if serverSet.KeystoreSecret == "" && !cr.Status.Applied.CommonConfig.DisableSsl {
-			secret, err := reconciler.generateKeystoreSecret(
-				fmt.Sprintf(constants.KeystoreSecret, kieDeploymentName),
-				serverCN,
-				cr,
-			)
+			secret, err := reconciler.getKeystoreSecret(kieDeploymentName, serverCN, &server, cr, ca)
 			if err != nil {
 				return api.Environment{}, err
+			} else if secret.Name != "" {
+				server.Secrets = append(server.Secrets, secret)
+				certificates = append(certificates, secret)
 			}
-			server.Secrets = append(server.Secrets, secret)
 		}
 		env.Servers[i] = server
 	}
@@ -615,7 +826,7 @@ func (reconciler *KieAppReconciler) setEnvironmentProperties(cr *api.KieApp, env
 		for _, rt := range env.SmartRouter.Routes {
 			if checkTLS(rt.Spec.TLS) {
 				// use host of first tls route in env template
-				smartCN = reconciler.GetRouteHost(rt, routes)
+				smartCN = reconciler.getExposedHost(cr, rt, routes)
 				break
 			}
 		}
@@ -625,18 +836,57 @@ func (reconciler *KieAppReconciler) setEnvironmentProperties(cr *api.KieApp, env
 
 		defaults.ConfigureHostname(&env.SmartRouter, cr, smartCN)
 		if (cr.Status.Applied.Objects.SmartRouter == nil || cr.Status.Applied.Objects.SmartRouter.KeystoreSecret == "") && !cr.Status.Applied.CommonConfig.DisableSsl {
-			secret, err := reconciler.generateKeystoreSecret(
-				fmt.Sprintf(constants.KeystoreSecret, strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "smartrouter"}, "-")),
+			secret, err := reconciler.getKeystoreSecret(
+				strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "smartrouter"}, "-"),
 				smartCN,
+				&env.SmartRouter,
 				cr,
+				ca,
 			)
 			if err != nil {
 				return api.Environment{}, err
+			} else if secret.Name != "" {
+				env.SmartRouter.Secrets = append(env.SmartRouter.Secrets, secret)
+				certificates = append(certificates, secret)
 			}
-			env.SmartRouter.Secrets = append(env.SmartRouter.Secrets, secret)
 		}
 	}
-	return defaults.ConsolidateObjects(env, cr), nil
+
+	if defaults.HasTruststore(cr) {
+		caBundle, err := reconciler.getTruststoreBundle(cr, caConfigMap)
+		if err != nil {
+			reconciler.recordEvent(cr, corev1.EventTypeWarning, TruststoreGeneratedEventReason, "Failed to read the CA bundles of the truststore: %v", err)
+			return api.Environment{}, err
+		}
+		// the components trust each other along with the CA bundles
+		var caCertificates []byte
+		if ca != nil {
+			caCertificates = ca.CertificatePEM()
+		} else if isCertManaged(cr) {
+			var err error
+			if caCertificates, err = reconciler.getIssuerCertificates(cr, env); err != nil {
+				return api.Environment{}, err
+			}
+		}
+		secret, err := reconciler.generateTruststoreSecret(
+			cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret,
+			cr,
+			caBundle,
+			caCertificates,
+		)
+		if err != nil {
+			return api.Environment{}, err
+		} else if secret.Name != "" {
+			env.Others[0].Secrets = append(env.Others[0].Secrets, secret)
+			certificates = append(certificates, secret)
+		} else {
+			// the pods are rolled out with the truststore once a CA bundle is available, the changes of its sources and
+			// of the Certificates issued by cert-manager trigger a reconcile
+			log.Infof("No CA bundle is available yet to generate the truststore of %s", cr.Name)
+			env = defaults.RemoveTruststore(env, cr)
+		}
+	}
+	return setCertificatesHash(defaults.ConsolidateObjects(env, cr), certificates), nil
 }
 
 func (reconciler *KieAppReconciler) setConsoleHost(cr *api.KieApp, env api.Environment, routes []client.Object) (consoleCN string) {
@@ -645,7 +895,7 @@ func (reconciler *KieAppReconciler) setConsoleHost(cr *api.KieApp, env api.Envir
 		for _, rt := range env.Dashbuilder.Routes {
 			if checkTLS(rt.Spec.TLS) {
 				// use host of first tls route in env template
-				consoleCN = reconciler.GetRouteHost(rt, routes)
+				consoleCN = reconciler.getExposedHost(cr, rt, routes)
 				cr.Status.ConsoleHost = fmt.Sprintf("https://%s", consoleCN)
 				log.Debug("Set dashbuilder console host to: ", cr.Status.ConsoleHost)
 				break
@@ -655,7 +905,7 @@ func (reconciler *KieAppReconciler) setConsoleHost(cr *api.KieApp, env api.Envir
 		for _, rt := range env.Console.Routes {
 			if checkTLS(rt.Spec.TLS) {
 				// use host of first tls route in env template
-				consoleCN = reconciler.GetRouteHost(rt, routes)
+				consoleCN = reconciler.getExposedHost(cr, rt, routes)
 				cr.Status.ConsoleHost = fmt.Sprintf("https://%s", consoleCN)
 				log.Debug("Set console host to: ", cr.Status.ConsoleHost)
 				break
@@ -744,20 +994,105 @@ func filterOmittedObjects(objects []api.CustomObject) []api.CustomObject {
 	return objs
 }
 
-func (reconciler *KieAppReconciler) generateKeystoreSecret(secretName, keystoreCN string, cr *api.KieApp) (secret corev1.Secret, err error) {
+// getDNSNames returns the host a component is exposed on, along with the DNS names of its services within the cluster
+func getDNSNames(cr *api.KieApp, object api.CustomObject, host string) []string {
+	var dnsNames []string
+	if len(validation.IsDNS1123Subdomain(host)) == 0 {
+		dnsNames = append(dnsNames, host)
+	}
+	for _, service := range object.Services {
+		dnsNames = append(dnsNames, service.Name)
+		if cr.Namespace != "" {
+			dnsNames = append(dnsNames, service.Name+"."+cr.Namespace, service.Name+"."+cr.Namespace+".svc")
+		}
+	}
+	return dnsNames
+}
+
+// generateCASecret returns the secret holding the CA of the KieApp, generating a new CA when the deployed secret does
+// not hold a valid one or its key no longer has the algorithm and size of the keystore options
+func (reconciler *KieAppReconciler) generateCASecret(secretName string, cr *api.KieApp) (secret corev1.Secret, err error) {
+	existingSecret := corev1.Secret{}
+	err = reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, &existingSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return secret, err
+	}
+	opts := defaults.GetKeystoreOptions(cr)
+	if existingCA, err := shared.ParseCA(existingSecret.Data[corev1.TLSCertKey], existingSecret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		if existingSecret.Name != "" {
+			log.Warnf("Regenerating the CA of secret %s: %v", secretName, err)
+		}
+	} else if !existingCA.HasKeyAlgorithm(opts) {
+		log.Infof("Regenerating the CA of secret %s with a %s key of %d bits", secretName, opts.KeyAlgorithm, opts.KeySize)
+	} else if expiry := existingCA.Certificate.NotAfter; isRenewalDue(cr, expiry) {
+		log.Infof("Renewing the CA of secret %s, expiring on %s", secretName, expiry)
+	} else {
+		setCertificateStatus(cr, secretName, expiry, getRenewalTime(cr, expiry))
+		return existingSecret, nil
+	}
+	if isPlanPending(cr) {
+		return getPlannedSecret(existingSecret, secretName, corev1.SecretTypeTLS, cr, corev1.TLSCertKey, corev1.TLSPrivateKeyKey), nil
+	}
+	ca, err := shared.GenerateCA(secretName, opts)
+	if err != nil {
+		reconciler.recordEvent(cr, corev1.EventTypeWarning, CAGeneratedEventReason, "Failed to generate the CA of secret %s: %v", secretName, err)
+		return secret, err
+	}
+	if existingSecret.Name != "" {
+		reconciler.recordEvent(cr, corev1.EventTypeNormal, CAGeneratedEventReason, "Regenerated the CA of secret %s", secretName)
+	}
+	secret = corev1.Secret{
+		Type: corev1.SecretTypeTLS,
+		ObjectMeta: metav1.ObjectMeta{
+			Name: secretName,
+			Labels: map[string]string{
+				"app":         cr.Status.Applied.CommonConfig.ApplicationName,
+				"application": cr.Status.Applied.CommonConfig.ApplicationName,
+			},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       ca.CertificatePEM(),
+			corev1.TLSPrivateKeyKey: ca.PrivateKeyPEM(),
+		},
+	}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	setCertificateStatus(cr, secretName, ca.Certificate.NotAfter, getRenewalTime(cr, ca.Certificate.NotAfter))
+	return secret, nil
+}
+
+func (reconciler *KieAppReconciler) generateKeystoreSecret(secretName, keystoreCN string, dnsNames []string, cr *api.KieApp, ca *shared.CertificateAuthority) (secret corev1.Secret, err error) {
 	existingSecret := corev1.Secret{}
 	err = reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, &existingSecret)
 	if err != nil && !errors.IsNotFound(err) {
 		return secret, err
 	}
 	keyStorePassword := []byte(cr.Status.Applied.CommonConfig.KeyStorePassword)
-	if ok, _ := shared.IsValidKeyStoreSecret(existingSecret, keystoreCN, keyStorePassword); ok {
+	opts := defaults.GetKeystoreOptions(cr)
+	keystoreName := shared.KeystoreName(opts.Format)
+	// the CA is missing while it is planned
+	valid := false
+	if ca != nil {
+		valid, _ = shared.IsValidKeyStoreSecret(existingSecret, keystoreCN, dnsNames, keyStorePassword, ca, opts)
+	}
+	if valid {
+		if expiry, err := shared.GetKeyStoreExpiry(keyStorePassword, existingSecret.Data[keystoreName], opts.Format); err == nil && isRenewalDue(cr, expiry) {
+			log.Infof("Renewing the keystore of secret %s, expiring on %s", secretName, expiry)
+			valid = false
+		}
+	}
+	if valid {
 		secret = existingSecret
+	} else if isPlanPending(cr) {
+		return getPlannedSecret(existingSecret, secretName, corev1.SecretTypeOpaque, cr, keystoreName), nil
 	} else {
-		keystoreByte, err := shared.GenerateKeystore(keystoreCN, keyStorePassword)
+		keystoreByte, err := shared.GenerateKeystore(keystoreCN, dnsNames, keyStorePassword, ca, opts)
 		if err != nil {
+			reconciler.recordEvent(cr, corev1.EventTypeWarning, KeystoreGeneratedEventReason, "Failed to generate the keystore of secret %s: %v", secretName, err)
 			return secret, err
 		}
+		if existingSecret.Name != "" {
+			reconciler.recordEvent(cr, corev1.EventTypeNormal, KeystoreGeneratedEventReason, "Regenerated the keystore of secret %s for %s", secretName, keystoreCN)
+		}
 		secret = corev1.Secret{
 			Type: corev1.SecretTypeOpaque,
 			ObjectMeta: metav1.ObjectMeta{
@@ -768,31 +1103,109 @@ func (reconciler *KieAppReconciler) generateKeystoreSecret(secretName, keystoreC
 				},
 			},
 			Data: map[string][]byte{
-				constants.KeystoreName: keystoreByte,
+				keystoreName: keystoreByte,
 			},
 		}
 		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
 	}
+	if expiry, err := shared.GetKeyStoreExpiry(keyStorePassword, secret.Data[keystoreName], opts.Format); err == nil {
+		trackedApps.trackKeystore(cr, secretName, expiry)
+		setCertificateStatus(cr, secretName, expiry, getRenewalTime(cr, expiry))
+	}
 
 	return secret, nil
 }
 
-func (reconciler *KieAppReconciler) generateTruststoreSecret(secretName string, cr *api.KieApp, caConfigMap *corev1.ConfigMap) (secret corev1.Secret, err error) {
-	// add truststore to secret if ca bundle exists in configmap
-	if len(caConfigMap.Data) > 0 && caConfigMap.Data[constants.CaBundleKey] != "" {
+// getTruststoreBundle returns the CA bundle injected by Openshift into the configmap, followed by the CA bundles of the
+// truststore sources
+func (reconciler *KieAppReconciler) getTruststoreBundle(cr *api.KieApp, caConfigMap *corev1.ConfigMap) ([]byte, error) {
+	var caBundles [][]byte
+	if caBundle := caConfigMap.Data[constants.CaBundleKey]; caBundle != "" {
+		caBundles = append(caBundles, []byte(caBundle))
+	}
+	if cr.Status.Applied.Truststore == nil {
+		return bytes.Join(caBundles, []byte{'\n'}), nil
+	}
+	for _, source := range cr.Status.Applied.Truststore.From {
+		caBundle, err := reconciler.getTruststoreSource(cr.Namespace, source)
+		if err != nil {
+			return nil, err
+		}
+		if len(caBundle) > 0 {
+			caBundles = append(caBundles, caBundle)
+		}
+	}
+	return bytes.Join(caBundles, []byte{'\n'}), nil
+}
+
+// getTruststoreSource returns the CA bundle held by the key of the configmap or secret, or nothing when an optional
+// one is missing
+func (reconciler *KieAppReconciler) getTruststoreSource(namespace string, source api.TruststoreSource) ([]byte, error) {
+	if ref := source.ConfigMapKeyRef; ref != nil {
+		optional := ref.Optional != nil && *ref.Optional
+		configMap := &corev1.ConfigMap{}
+		if err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, configMap); err != nil {
+			if errors.IsNotFound(err) && optional {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unable to get the configmap %s: %v", ref.Name, err)
+		}
+		if caBundle, ok := configMap.Data[ref.Key]; ok {
+			return []byte(caBundle), nil
+		} else if caBundle, ok := configMap.BinaryData[ref.Key]; ok {
+			return caBundle, nil
+		} else if !optional {
+			return nil, fmt.Errorf("key %s not found in the configmap %s", ref.Key, ref.Name)
+		}
+	} else if ref := source.SecretKeyRef; ref != nil {
+		optional := ref.Optional != nil && *ref.Optional
+		secret := &corev1.Secret{}
+		if err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+			if errors.IsNotFound(err) && optional {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unable to get the secret %s: %v", ref.Name, err)
+		}
+		if caBundle, ok := secret.Data[ref.Key]; ok {
+			return caBundle, nil
+		} else if !optional {
+			return nil, fmt.Errorf("key %s not found in the secret %s", ref.Key, ref.Name)
+		}
+	}
+	return nil, nil
+}
+
+// generateTruststoreSecret returns the secret holding the truststore of the CA bundle, to which the PEM encoded CA
+// certificates are added when set
+func (reconciler *KieAppReconciler) generateTruststoreSecret(secretName string, cr *api.KieApp, caBundle []byte, caCertificates []byte) (secret corev1.Secret, err error) {
+	if len(caBundle) > 0 && len(caCertificates) > 0 {
+		caBundle = bytes.Join([][]byte{caBundle, caCertificates}, []byte{'\n'})
+	} else if len(caCertificates) > 0 {
+		caBundle = caCertificates
+	}
+	// add truststore to secret if a ca bundle is available, Openshift may not have injected its own yet, and plan it
+	// while the CA signing the keystores is only planned
+	caPlanned := isPlanPending(cr) && !cr.Status.Applied.CommonConfig.DisableSsl && !isCertManaged(cr)
+	if len(caBundle) > 0 || caPlanned {
 		existingSecret := corev1.Secret{}
 		err = reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, &existingSecret)
 		if err != nil && !errors.IsNotFound(err) {
 			return secret, err
 		}
-		caBundle := []byte(caConfigMap.Data[constants.CaBundleKey])
-		if ok, _ := shared.IsValidTruststoreSecret(existingSecret, caBundle); ok {
+		format := defaults.GetKeystoreOptions(cr).Format
+		if ok, _ := shared.IsValidTruststoreSecret(existingSecret, caBundle, format); ok {
 			secret = existingSecret
+		} else if isPlanPending(cr) {
+			return getPlannedSecret(existingSecret, secretName, corev1.SecretTypeOpaque, cr, shared.TruststoreName(format)), nil
 		} else {
-			truststoreByte, err := shared.GenerateTruststore(caBundle)
+			truststoreByte, err := shared.GenerateTruststore(caBundle, format)
 			if err != nil {
+				reconciler.recordEvent(cr, corev1.EventTypeWarning, TruststoreGeneratedEventReason, "Failed to generate the truststore of secret %s: %v", secretName, err)
 				return secret, err
 			}
+			if existingSecret.Name != "" {
+				reconciler.recordEvent(cr, corev1.EventTypeNormal, TruststoreGeneratedEventReason, "Regenerated the truststore of secret %s from the CA bundles", secretName)
+			}
 			secret = corev1.Secret{
 				Type: corev1.SecretTypeOpaque,
 				ObjectMeta: metav1.ObjectMeta{
@@ -803,7 +1216,7 @@ func (reconciler *KieAppReconciler) generateTruststoreSecret(secretName string,
 					},
 				},
 				Data: map[string][]byte{
-					constants.TruststoreName: truststoreByte,
+					shared.TruststoreName(format): truststoreByte,
 				},
 			}
 			secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
@@ -843,6 +1256,10 @@ func (reconciler *KieAppReconciler) getCustomObjectResources(object api.CustomOb
 		object.DeploymentConfigs[index].SetGroupVersionKind(oappsv1.GroupVersion.WithKind("DeploymentConfig"))
 		allObjects = append(allObjects, &object.DeploymentConfigs[index])
 	}
+	for index := range object.Deployments {
+		object.Deployments[index].SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		allObjects = append(allObjects, &object.Deployments[index])
+	}
 	for index := range object.Services {
 		object.Services[index].SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
 		allObjects = append(allObjects, &object.Services[index])
@@ -855,6 +1272,38 @@ func (reconciler *KieAppReconciler) getCustomObjectResources(object api.CustomOb
 		object.Routes[index].SetGroupVersionKind(routev1.GroupVersion.WithKind("Route"))
 		allObjects = append(allObjects, &object.Routes[index])
 	}
+	for index := range object.Ingresses {
+		object.Ingresses[index].SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("Ingress"))
+		allObjects = append(allObjects, &object.Ingresses[index])
+	}
+	for index := range object.NetworkPolicies {
+		object.NetworkPolicies[index].SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+		allObjects = append(allObjects, &object.NetworkPolicies[index])
+	}
+	for index := range object.HTTPRoutes {
+		object.HTTPRoutes[index].SetGroupVersionKind(gatewayv1alpha1.SchemeGroupVersion.WithKind("HTTPRoute"))
+		allObjects = append(allObjects, &object.HTTPRoutes[index])
+	}
+	for index := range object.PodDisruptionBudgets {
+		object.PodDisruptionBudgets[index].SetGroupVersionKind(policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+		allObjects = append(allObjects, &object.PodDisruptionBudgets[index])
+	}
+	for index := range object.HorizontalPodAutoscalers {
+		object.HorizontalPodAutoscalers[index].SetGroupVersionKind(autoscalingv2beta2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"))
+		allObjects = append(allObjects, &object.HorizontalPodAutoscalers[index])
+	}
+	for index := range object.ServiceMonitors {
+		object.ServiceMonitors[index].SetGroupVersionKind(monv1.SchemeGroupVersion.WithKind(monv1.ServiceMonitorsKind))
+		allObjects = append(allObjects, &object.ServiceMonitors[index])
+	}
+	for index := range object.PrometheusRules {
+		object.PrometheusRules[index].SetGroupVersionKind(monv1.SchemeGroupVersion.WithKind(monv1.PrometheusRuleKind))
+		allObjects = append(allObjects, &object.PrometheusRules[index])
+	}
+	for index := range object.Certificates {
+		object.Certificates[index].SetGroupVersionKind(certmanagerv1.GroupVersion.WithKind(certmanagerv1.CertificateKind))
+		allObjects = append(allObjects, &object.Certificates[index])
+	}
 	for index := range object.ImageStreams {
 		object.ImageStreams[index].SetGroupVersionKind(oimagev1.GroupVersion.WithKind("ImageStream"))
 		allObjects = append(allObjects, &object.ImageStreams[index])
@@ -876,6 +1325,9 @@ func (reconciler *KieAppReconciler) ensureImageStream(name, namespace, imageURL
 			return cr.Namespace, nil
 		}
 		log.Warnf("ImageStreamTag %s/%s doesn't exist.", namespace, name)
+		if isPlanPending(cr) {
+			return cr.Namespace, nil
+		}
 		err := reconciler.createLocalImageTag(name, imageURL, cr)
 		if err != nil {
 			log.Error(err)
@@ -890,6 +1342,10 @@ func (reconciler *KieAppReconciler) ensureImageStream(name, namespace, imageURL
 		return cr.Namespace, nil
 	} else {
 		log.Warnf("ImageStreamTag %s/%s doesn't exist.", namespace, name)
+		if isPlanPending(cr) {
+			// the local ImageStreamTag is created once the plan is approved
+			return cr.Namespace, nil
+		}
 		err := reconciler.createLocalImageTag(name, imageURL, cr)
 		if err != nil {
 			log.Error(err)
@@ -952,6 +1408,14 @@ func (reconciler *KieAppReconciler) GetRouteHost(route routev1.Route, routes []c
 	return ""
 }
 
+// getExposedHost returns the hostname the route is exposed on, which is only known in advance when Ingresses or HTTPRoutes replace the route
+func (reconciler *KieAppReconciler) getExposedHost(cr *api.KieApp, route routev1.Route, routes []client.Object) string {
+	if defaults.GetExposureType(cr) == api.RouteExposure {
+		return reconciler.GetRouteHost(route, routes)
+	}
+	return defaults.GetExposedHostname(route, cr)
+}
+
 // CreateConfigMaps generates & creates necessary ConfigMaps from embedded files
 func (reconciler *KieAppReconciler) CreateConfigMaps(myDep *appsv1.Deployment) {
 	configMaps := defaults.ConfigMapsFromFile(myDep, myDep.Namespace, reconciler.Service.GetScheme())
@@ -986,7 +1450,10 @@ func (reconciler *KieAppReconciler) CreateConfigMaps(myDep *appsv1.Deployment) {
 							existingBackupCM.Data = existingCM.Data
 						_:
 							reconciler.UpdateObj(existingBackupCM)
+							reconciler.recordEvent(myDep, corev1.EventTypeNormal, ConfigMapBackupEventReason, "Backed up ConfigMap %s to %s", configMap.Name, existingCM.Name)
 						}
+					} else {
+						reconciler.recordEvent(myDep, corev1.EventTypeNormal, ConfigMapBackupEventReason, "Backed up ConfigMap %s to %s", configMap.Name, existingCM.Name)
 					}
 				}
 			}
@@ -1024,17 +1491,16 @@ func (reconciler *KieAppReconciler) checkKieServerConfigMap(instance *api.KieApp
 			for _, sDc := range server.DeploymentConfigs {
 				serverDcList[sDc.Name] = sDc.Spec.Replicas
 			}
+			for _, sDeployment := range server.Deployments {
+				serverDcList[sDeployment.Name] = *sDeployment.Spec.Replicas
+			}
 		}
 		// sort through ConfigMap list, focus on ones owned by kie servers whose replicas setting is zero
 		for _, cm := range cmList.Items {
 			for _, ownerRef := range cm.OwnerReferences {
-				if serverDcList[ownerRef.Name] == 0 && ownerRef.Kind == "DeploymentConfig" && cm.Labels[constants.KieServerCMLabel] != "" && cm.Labels[constants.KieServerCMLabel] != "DETACHED" {
-					dcObj := &oappsv1.DeploymentConfig{}
-					if err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: ownerRef.Name, Namespace: cm.Namespace}, dcObj); err != nil {
-						log.Error(err)
-					}
+				if serverDcList[ownerRef.Name] == 0 && (ownerRef.Kind == "DeploymentConfig" || ownerRef.Kind == "Deployment") && cm.Labels[constants.KieServerCMLabel] != "" && cm.Labels[constants.KieServerCMLabel] != "DETACHED" {
 					// if server DC replicas equal zero, execute DELETE against console
-					if dcObj.Status.AvailableReplicas == 0 {
+					if reconciler.getAvailableReplicas(ownerRef.Kind, types.NamespacedName{Name: ownerRef.Name, Namespace: cm.Namespace}) == 0 {
 						cmObj := &corev1.ConfigMap{}
 						if err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: ownerRef.Name, Namespace: cm.Namespace}, cmObj); err != nil {
 							log.Error(err)
@@ -1051,27 +1517,88 @@ func (reconciler *KieAppReconciler) checkKieServerConfigMap(instance *api.KieApp
 	}
 }
 
+// getAvailableReplicas returns the available replicas of the named DeploymentConfig or Deployment
+func (reconciler *KieAppReconciler) getAvailableReplicas(kind string, name types.NamespacedName) int32 {
+	if kind == "Deployment" {
+		deployment := &appsv1.Deployment{}
+		if err := reconciler.Service.Get(context.TODO(), name, deployment); err != nil {
+			log.Error(err)
+		}
+		return deployment.Status.AvailableReplicas
+	}
+	dcObj := &oappsv1.DeploymentConfig{}
+	if err := reconciler.Service.Get(context.TODO(), name, dcObj); err != nil {
+		log.Error(err)
+	}
+	return dcObj.Status.AvailableReplicas
+}
+
 func (reconciler *KieAppReconciler) getDeployedResources(instance *api.KieApp) (map[reflect.Type][]client.Object, error) {
 	log := log.With("kind", instance.Kind, "name", instance.Name, "namespace", instance.Namespace)
 
 	reader := read.New(reconciler.Service).WithNamespace(instance.Namespace).WithOwnerObject(instance)
-	resourceMap, err := reader.ListAll(
-		&oappsv1.DeploymentConfigList{},
+	listObjects := []client.ObjectList{
 		&corev1.PersistentVolumeClaimList{},
 		&corev1.ServiceAccountList{},
 		&rbacv1.RoleList{},
 		&rbacv1.RoleBindingList{},
 		&corev1.ServiceList{},
 		&appsv1.StatefulSetList{},
-		&routev1.RouteList{},
-		&oimagev1.ImageStreamList{},
-		&buildv1.BuildConfigList{},
 		&corev1.ConfigMapList{},
-	)
+		&networkingv1.IngressList{},
+		&networkingv1.NetworkPolicyList{},
+		&policyv1.PodDisruptionBudgetList{},
+	}
+	if defaults.IsKubernetes(instance) {
+		listObjects = append(listObjects, &appsv1.DeploymentList{})
+	} else {
+		listObjects = append(listObjects,
+			&oappsv1.DeploymentConfigList{},
+			&routev1.RouteList{},
+			&oimagev1.ImageStreamList{},
+			&buildv1.BuildConfigList{},
+		)
+	}
+	resourceMap, err := reader.ListAll(listObjects...)
 	if err != nil {
 		log.Warn("Failed to list deployed objects. ", err)
 		return nil, err
 	}
+	// autoscaling/v2beta2 is no longer served from Kubernetes 1.26, KieApps without autoscaling are still deployed there
+	hpas, err := reader.List(&autoscalingv2beta2.HorizontalPodAutoscalerList{})
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Warn("Failed to list deployed HorizontalPodAutoscalers. ", err)
+		return nil, err
+	}
+	resourceMap[reflect.TypeOf(autoscalingv2beta2.HorizontalPodAutoscaler{})] = hpas
+	// the Gateway API is an optional add-on, only look for HTTPRoutes when its CRDs are installed
+	httpRoutes, err := reader.List(&gatewayv1alpha1.HTTPRouteList{})
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Warn("Failed to list deployed HTTPRoutes. ", err)
+		return nil, err
+	}
+	resourceMap[reflect.TypeOf(gatewayv1alpha1.HTTPRoute{})] = httpRoutes
+	// so is the prometheus-operator, its objects are looked for whenever its CRDs are installed, including once
+	// monitoring is disabled, so the ones no longer generated are removed
+	monitoringLists := map[reflect.Type]client.ObjectList{
+		reflect.TypeOf(monv1.ServiceMonitor{}): &monv1.ServiceMonitorList{},
+		reflect.TypeOf(monv1.PrometheusRule{}): &monv1.PrometheusRuleList{},
+	}
+	for monitoringType, list := range monitoringLists {
+		objects, err := reader.List(list)
+		if err != nil && !meta.IsNoMatchError(err) {
+			log.Warn("Failed to list deployed monitoring objects. ", err)
+			return nil, err
+		}
+		resourceMap[monitoringType] = objects
+	}
+	// and cert-manager
+	certificates, err := reader.List(&certmanagerv1.CertificateList{})
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Warn("Failed to list deployed Certificates. ", err)
+		return nil, err
+	}
+	resourceMap[reflect.TypeOf(certmanagerv1.Certificate{})] = certificates
 
 	//secretList := &corev1.SecretList{}
 	//err = reconciler.Service.List(context.TODO(), listOps, secretList) //TODO: can't list secrets due to bug:
@@ -1079,31 +1606,64 @@ func (reconciler *KieAppReconciler) getDeployedResources(instance *api.KieApp) (
 	// multiple group-version-kinds associated with type *api.SecretList, refusing to guess at one
 	// Will work around by loading known secrets instead
 
-	var secrets []client.Object
-	dcs := resourceMap[reflect.TypeOf(oappsv1.DeploymentConfig{})]
-	for _, res := range dcs {
-		dc := res.(*oappsv1.DeploymentConfig)
-		for _, volume := range dc.Spec.Template.Spec.Volumes {
+	var podSpecs []corev1.PodSpec
+	for _, res := range resourceMap[reflect.TypeOf(oappsv1.DeploymentConfig{})] {
+		if dc := res.(*oappsv1.DeploymentConfig); dc.Spec.Template != nil {
+			podSpecs = append(podSpecs, dc.Spec.Template.Spec)
+		}
+	}
+	for _, res := range resourceMap[reflect.TypeOf(appsv1.Deployment{})] {
+		podSpecs = append(podSpecs, res.(*appsv1.Deployment).Spec.Template.Spec)
+	}
+	var secretNames []string
+	for _, res := range resourceMap[reflect.TypeOf(monv1.ServiceMonitor{})] {
+		for _, endpoint := range res.(*monv1.ServiceMonitor).Spec.Endpoints {
+			if endpoint.BasicAuth != nil {
+				secretNames = append(secretNames, endpoint.BasicAuth.Username.Name, endpoint.BasicAuth.Password.Name)
+			}
+		}
+	}
+	for _, podSpec := range podSpecs {
+		for _, volume := range podSpec.Volumes {
 			if volume.Secret != nil {
-				name := volume.Secret.SecretName
-				secret := &corev1.Secret{}
-				err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: instance.GetNamespace()}, secret)
-				if err != nil && !errors.IsNotFound(err) {
-					log.Warn("Failed to load Secret", err)
-					return nil, err
-				}
-				for _, ownerRef := range secret.GetOwnerReferences() {
-					if ownerRef.UID == instance.UID {
-						secrets = append(secrets, secret)
-						break
+				secretNames = append(secretNames, volume.Secret.SecretName)
+			}
+		}
+		for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+			for _, container := range containers {
+				for _, envVar := range container.Env {
+					if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil {
+						secretNames = append(secretNames, envVar.ValueFrom.SecretKeyRef.Name)
 					}
 				}
 			}
 		}
 	}
+	// the CA is not mounted by the pods, only its certificates are
+	secretNames = append(secretNames, fmt.Sprintf(constants.CASecret, instance.Status.Applied.CommonConfig.ApplicationName))
+	var secrets []client.Object
+	loaded := map[string]bool{}
+	for _, name := range secretNames {
+		if loaded[name] {
+			continue
+		}
+		loaded[name] = true
+		secret := &corev1.Secret{}
+		err := reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: instance.GetNamespace()}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			log.Warn("Failed to load Secret", err)
+			return nil, err
+		}
+		for _, ownerRef := range secret.GetOwnerReferences() {
+			if ownerRef.UID == instance.UID {
+				secrets = append(secrets, secret)
+				break
+			}
+		}
+	}
 	resourceMap[reflect.TypeOf(corev1.Secret{})] = secrets
 
-	if semver.Compare(reconciler.OcpVersion, "v4.2") >= 0 || reconciler.OcpVersion == "" {
+	if !defaults.IsKubernetes(instance) && (semver.Compare(reconciler.OcpVersion, "v4.2") >= 0 || reconciler.OcpVersion == "") {
 		consoleLink := &consolev1.ConsoleLink{}
 		err = reconciler.Service.Get(context.TODO(), types.NamespacedName{Name: getConsoleLinkName(instance)}, consoleLink)
 		if err != nil {
@@ -1136,7 +1696,7 @@ func (reconciler *KieAppReconciler) getCSV(operator *appsv1.Deployment) *operato
 }
 
 func (reconciler *KieAppReconciler) getConsoleLinkResource(cr *api.KieApp) client.Object {
-	if cr.GetDeletionTimestamp() != nil || cr.Status.ConsoleHost == "" || !strings.HasPrefix(cr.Status.ConsoleHost, "https://") ||
+	if defaults.IsKubernetes(cr) || cr.GetDeletionTimestamp() != nil || cr.Status.ConsoleHost == "" || !strings.HasPrefix(cr.Status.ConsoleHost, "https://") ||
 		(reconciler.OcpVersion != "" && semver.Compare(reconciler.OcpVersion, "v4.2") < 0) {
 		return nil
 	}
@@ -1172,7 +1732,5 @@ func getConsoleLinkFriendlyName(cr *api.KieApp) string {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KieAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&api.KieApp{}).
-		Complete(r)
+	return Add(mgr, r, r.getPlatform(&api.KieApp{}))
 }
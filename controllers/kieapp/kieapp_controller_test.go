@@ -62,15 +62,21 @@ func TestGenerateSecret(t *testing.T) {
 
 	consoleSecret := env.Console.Secrets[0]
 	serverSecret := env.Servers[0].Secrets[0]
+	caSecret := getOthersSecret(env, fmt.Sprintf(constants.CASecret, cr.Status.Applied.CommonConfig.ApplicationName))
+	assert.NotEmpty(t, getOthersSecret(env, cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret).Data, "The CA should be trusted as SSL is enabled")
+	ca, err := shared.ParseCA(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+	assert.Nil(t, err)
 	consoleRoute := cr.Status.ConsoleHost
 	secretName := fmt.Sprintf(constants.KeystoreSecret, env.Servers[0].DeploymentConfigs[0].Name)
 	assert.Equal(t, secretName, serverSecret.Name)
 	for _, volume := range env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Volumes {
-		if volume.Secret != nil {
+		if volume.Secret != nil && volume.Name != cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret {
 			assert.Equal(t, secretName, volume.Secret.SecretName)
 		}
 	}
 
+	err = reconciler.Service.Create(context.TODO(), &caSecret)
+	assert.Nil(t, err)
 	err = reconciler.Service.Create(context.TODO(), &consoleSecret)
 	assert.Nil(t, err)
 	err = reconciler.Service.Create(context.TODO(), &serverSecret)
@@ -81,16 +87,17 @@ func TestGenerateSecret(t *testing.T) {
 
 	consoleCN := reconciler.setConsoleHost(cr, env, getRequestedRoutes(env, cr))
 	assert.Equal(t, consoleRoute, "http://"+consoleCN)
-	ok, err := shared.IsValidKeyStoreSecret(corev1.Secret{}, consoleCN, []byte(cr.Status.Applied.CommonConfig.KeyStorePassword))
+	dnsNames := getDNSNames(cr, env.Console, consoleCN)
+	ok, err := shared.IsValidKeyStoreSecret(corev1.Secret{}, consoleCN, dnsNames, []byte(cr.Status.Applied.CommonConfig.KeyStorePassword), ca, shared.KeystoreOptions{})
 	assert.False(t, ok)
 	assert.Nil(t, err)
-	ok, err = shared.IsValidKeyStoreSecret(consoleTestSecret, "blah", []byte(cr.Status.Applied.CommonConfig.KeyStorePassword))
+	ok, err = shared.IsValidKeyStoreSecret(consoleTestSecret, "blah", dnsNames, []byte(cr.Status.Applied.CommonConfig.KeyStorePassword), ca, shared.KeystoreOptions{})
 	assert.False(t, ok)
 	assert.Nil(t, err)
-	ok, err = shared.IsValidKeyStoreSecret(consoleTestSecret, consoleCN, []byte("wrongPwd"))
+	ok, err = shared.IsValidKeyStoreSecret(consoleTestSecret, consoleCN, dnsNames, []byte("wrongPwd"), ca, shared.KeystoreOptions{})
 	assert.False(t, ok)
 	assert.NotNil(t, err)
-	ok, err = shared.IsValidKeyStoreSecret(consoleTestSecret, consoleCN, []byte(cr.Status.Applied.CommonConfig.KeyStorePassword))
+	ok, err = shared.IsValidKeyStoreSecret(consoleTestSecret, consoleCN, dnsNames, []byte(cr.Status.Applied.CommonConfig.KeyStorePassword), ca, shared.KeystoreOptions{})
 	assert.True(t, ok)
 	assert.Nil(t, err)
 	assert.Equal(t, consoleSecret.DeepCopy(), consoleTestSecret.DeepCopy())
@@ -98,7 +105,9 @@ func TestGenerateSecret(t *testing.T) {
 	secret, err := reconciler.generateKeystoreSecret(
 		fmt.Sprintf(constants.KeystoreSecret, strings.Join([]string{cr.Status.Applied.CommonConfig.ApplicationName, "businesscentral"}, "-")),
 		consoleCN,
+		dnsNames,
 		cr,
+		ca,
 	)
 	assert.Nil(t, err)
 	assert.Equal(t, consoleSecret, secret)
@@ -129,6 +138,10 @@ func TestGenerateSecret(t *testing.T) {
 
 	consoleSecret = env.Console.Secrets[0]
 	serverSecret = env.Servers[0].Secrets[0]
+	caSecret = getOthersSecret(env, "changed-ca")
+	assert.NotEmpty(t, caSecret.Data, "The CA is named after the application")
+	err = reconciler.Service.Create(context.TODO(), &caSecret)
+	assert.Nil(t, err)
 	err = reconciler.Service.Create(context.TODO(), &consoleSecret)
 	assert.Nil(t, err)
 	err = reconciler.Service.Create(context.TODO(), &serverSecret)
@@ -141,6 +154,7 @@ func TestGenerateSecret(t *testing.T) {
 	env, err = reconciler.setEnvironmentProperties(cr, env, getRequestedRoutes(env, cr), caConfigMap)
 	assert.Nil(t, err)
 	assertSecret(t, consoleSecret, serverSecret, env, true)
+	assert.Equal(t, caSecret, getOthersSecret(env, "changed-ca"), "The deployed CA should be kept")
 
 	// change keystore password which should change commonname and keystore secret
 	oldPassword := cr.Status.Applied.CommonConfig.KeyStorePassword
@@ -175,6 +189,15 @@ func TestGenerateSecret(t *testing.T) {
 	assertSecret(t, consoleSecret, serverSecret, env, true)
 }
 
+func getOthersSecret(env api.Environment, name string) corev1.Secret {
+	for _, secret := range env.Others[0].Secrets {
+		if secret.Name == name {
+			return secret
+		}
+	}
+	return corev1.Secret{}
+}
+
 func generateSecretCommonAssertions(t *testing.T, env api.Environment) {
 	assert.Len(t, env.Console.Secrets, 1, "One secret should be generated for the trial workbench")
 	assert.Len(t, env.Servers[0].Secrets, 1, "One secret should be generated for each trial kieserver")
@@ -206,23 +229,26 @@ func TestGenerateTruststoreSecret(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotEmpty(t, caBundle)
 
-	caConfigMap = &corev1.ConfigMap{
-		Data: map[string]string{
-			constants.CaBundleKey: string(caBundle),
-		},
-	}
-
 	cr := &api.KieApp{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
 	secret, err := reconciler.generateTruststoreSecret(
 		cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret,
 		cr,
-		caConfigMap,
+		caBundle,
+		nil,
 	)
 	assert.Nil(t, err)
 	assert.Equal(t, cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret, secret.Name)
-	ok, err := shared.IsValidTruststoreSecret(secret, caBundle)
+	ok, err := shared.IsValidTruststoreSecret(secret, caBundle, constants.JKSKeystoreFormat)
 	assert.True(t, ok)
 	assert.Nil(t, err)
+
+	ca, err := shared.GenerateCA("test-ca", shared.KeystoreOptions{})
+	assert.Nil(t, err)
+	secret, err = reconciler.generateTruststoreSecret(secret.Name, cr, caBundle, ca.CertificatePEM())
+	assert.Nil(t, err)
+	ok, err = shared.IsValidTruststoreSecret(secret, append(append(caBundle, '\n'), ca.CertificatePEM()...), constants.JKSKeystoreFormat)
+	assert.True(t, ok, "The CA of the KieApp should be trusted along with the CA bundle")
+	assert.Nil(t, err)
 }
 
 func TestGenerateSecrets(t *testing.T) {
@@ -265,7 +291,7 @@ func TestGenerateSecrets(t *testing.T) {
 		secretName := fmt.Sprintf(constants.KeystoreSecret, server.DeploymentConfigs[0].Name)
 		assert.Equal(t, secretName, server.Secrets[0].Name)
 		for _, volume := range server.DeploymentConfigs[0].Spec.Template.Spec.Volumes {
-			if volume.Secret != nil {
+			if volume.Secret != nil && volume.Name != cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret {
 				assert.Equal(t, secretName, volume.Secret.SecretName)
 			}
 		}
@@ -317,17 +343,17 @@ func TestSpecifySecret(t *testing.T) {
 	assert.Len(t, env.Servers[0].Secrets, 0, "Zero secrets should be generated for the trial kieserver")
 	assert.Len(t, env.SmartRouter.Secrets, 0, "Zero secrets should be generated for the smartrouter")
 	for _, volume := range env.Console.DeploymentConfigs[0].Spec.Template.Spec.Volumes {
-		if volume.Secret != nil {
+		if volume.Secret != nil && volume.Name != cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret {
 			assert.Equal(t, cr.Status.Applied.Objects.Console.KeystoreSecret, volume.Secret.SecretName)
 		}
 	}
 	for _, volume := range env.Servers[0].DeploymentConfigs[0].Spec.Template.Spec.Volumes {
-		if volume.Secret != nil {
+		if volume.Secret != nil && volume.Name != cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret {
 			assert.Equal(t, cr.Status.Applied.Objects.Servers[0].KeystoreSecret, volume.Secret.SecretName)
 		}
 	}
 	for _, volume := range env.SmartRouter.DeploymentConfigs[0].Spec.Template.Spec.Volumes {
-		if volume.Secret != nil {
+		if volume.Secret != nil && volume.Name != cr.Status.Applied.CommonConfig.ApplicationName+constants.TruststoreSecret {
 			assert.Equal(t, cr.Status.Applied.Objects.SmartRouter.KeystoreSecret, volume.Secret.SecretName)
 		}
 	}
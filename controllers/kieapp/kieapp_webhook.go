@@ -0,0 +1,112 @@
+package kieapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// ValidatingWebhookPath is the path the KieApp validating webhook is served on
+	ValidatingWebhookPath = "/validate-app-kiegroup-org-v2-kieapp"
+	// DefaultingWebhookPath is the path the KieApp defaulting webhook is served on
+	DefaultingWebhookPath = "/mutate-app-kiegroup-org-v2-kieapp"
+)
+
+//+kubebuilder:webhook:path=/mutate-app-kiegroup-org-v2-kieapp,mutating=true,failurePolicy=fail,sideEffects=None,groups=app.kiegroup.org,resources=kieapps,verbs=create;update,versions=v2,name=mkieapp.kb.io,admissionReviewVersions={v1,v1beta1}
+//+kubebuilder:webhook:path=/validate-app-kiegroup-org-v2-kieapp,mutating=false,failurePolicy=fail,sideEffects=None,groups=app.kiegroup.org,resources=kieapps,verbs=create;update,versions=v2,name=vkieapp.kb.io,admissionReviewVersions={v1,v1beta1}
+
+// KieAppValidator rejects KieApps the operator is not able to deploy before they are persisted
+type KieAppValidator struct {
+	decoder *admission.Decoder
+	// Platform detected for the cluster, used when the KieApp does not request one
+	Platform api.PlatformType
+}
+
+// SetupWebhookWithManager registers the validating webhook on the manager webhook server
+func (validator *KieAppValidator) SetupWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: validator})
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (validator *KieAppValidator) InjectDecoder(decoder *admission.Decoder) error {
+	validator.decoder = decoder
+	return nil
+}
+
+// Handle validates the KieApp on create and update requests. Updates of KieApps being deleted, and updates leaving the
+// spec unchanged, are not validated so finalizers and metadata can always be updated.
+func (validator *KieAppValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &api.KieApp{}
+	if err := validator.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if cr.DeletionTimestamp != nil {
+		return admission.Allowed("KieApp is being deleted")
+	}
+	var old *api.KieApp
+	if req.Operation == admissionv1.Update {
+		old = &api.KieApp{}
+		if err := validator.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(old.Spec, cr.Spec) {
+			return admission.Allowed("spec unchanged")
+		}
+	}
+	if cr.Spec.Platform == "" {
+		// only the decoded copy is defaulted, so the rules depending on the platform apply to the detected one
+		cr.Spec.Platform = validator.Platform
+	}
+	if errs := defaults.ValidateKieApp(cr, old); len(errs) > 0 {
+		log.Debugf("Rejecting KieApp %s/%s: %v", cr.Namespace, cr.Name, errs.ToAggregate())
+		status := errors.NewInvalid(api.GroupVersion.WithKind("KieApp").GroupKind(), cr.Name, errs).ErrStatus
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &status}}
+	}
+	return admission.Allowed("")
+}
+
+// KieAppDefaulter writes the defaults that hold no credentials into the spec of KieApps, unless they opt out with the
+// api.SkipSpecDefaultsAnnotation annotation
+type KieAppDefaulter struct {
+	decoder *admission.Decoder
+	// Platform detected for the cluster, used when the KieApp does not request one
+	Platform api.PlatformType
+}
+
+// SetupWebhookWithManager registers the defaulting webhook on the manager webhook server
+func (defaulter *KieAppDefaulter) SetupWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(DefaultingWebhookPath, &webhook.Admission{Handler: defaulter})
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (defaulter *KieAppDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	defaulter.decoder = decoder
+	return nil
+}
+
+// Handle patches the KieApp spec with its defaults on create and update requests
+func (defaulter *KieAppDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &api.KieApp{}
+	if err := defaulter.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if cr.GetAnnotations()[api.SkipSpecDefaultsAnnotation] == "true" {
+		return admission.Allowed("spec defaults skipped")
+	}
+	defaults.SetSpecDefaults(cr, defaulter.Platform)
+	marshaled, err := json.Marshal(cr)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
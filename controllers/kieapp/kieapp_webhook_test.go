@@ -0,0 +1,94 @@
+package kieapp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func getAdmissionRequest(t *testing.T, operation admissionv1.Operation, cr, old *api.KieApp) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: operation}}
+	raw, err := json.Marshal(cr)
+	assert.Nil(t, err)
+	req.Object = runtime.RawExtension{Raw: raw}
+	if old != nil {
+		raw, err = json.Marshal(old)
+		assert.Nil(t, err)
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+func TestKieAppValidator(t *testing.T) {
+	decoder, err := admission.NewDecoder(test.MockService().GetScheme())
+	assert.Nil(t, err)
+	validator := &KieAppValidator{}
+	assert.Nil(t, validator.InjectDecoder(decoder))
+
+	cr := &api.KieApp{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.GroupVersion.String(), Kind: "KieApp"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Version:     constants.PriorVersion,
+		},
+	}
+	response := validator.Handle(context.TODO(), getAdmissionRequest(t, admissionv1.Create, cr, nil))
+	assert.True(t, response.Allowed)
+
+	old := cr.DeepCopy()
+	old.Spec.Version = constants.CurrentVersion
+	old.Status.Applied.Version = constants.CurrentVersion
+	cr.Status.Applied.Version = constants.CurrentVersion
+	response = validator.Handle(context.TODO(), getAdmissionRequest(t, admissionv1.Update, cr, old))
+	assert.False(t, response.Allowed)
+	assert.Equal(t, metav1.StatusReasonInvalid, response.Result.Reason)
+	assert.Equal(t, "spec.version", response.Result.Details.Causes[0].Field)
+
+	// metadata updates are allowed even when the persisted spec would no longer be valid
+	old = cr.DeepCopy()
+	cr.SetFinalizers([]string{constants.KieAppFinalizer})
+	response = validator.Handle(context.TODO(), getAdmissionRequest(t, admissionv1.Update, cr, old))
+	assert.True(t, response.Allowed)
+
+	now := metav1.Now()
+	cr.DeletionTimestamp = &now
+	cr.Spec.Environment = api.RhdmTrial
+	response = validator.Handle(context.TODO(), getAdmissionRequest(t, admissionv1.Update, cr, old))
+	assert.True(t, response.Allowed)
+}
+
+func TestKieAppDefaulter(t *testing.T) {
+	decoder, err := admission.NewDecoder(test.MockService().GetScheme())
+	assert.Nil(t, err)
+	defaulter := &KieAppDefaulter{Platform: api.OpenShiftPlatform}
+	assert.Nil(t, defaulter.InjectDecoder(decoder))
+
+	cr := &api.KieApp{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.GroupVersion.String(), Kind: "KieApp"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec:       api.KieAppSpec{Environment: api.RhpamTrial},
+	}
+	response := defaulter.Handle(context.TODO(), getAdmissionRequest(t, admissionv1.Create, cr, nil))
+	assert.True(t, response.Allowed)
+	var paths []string
+	for _, patch := range response.Patches {
+		paths = append(paths, patch.Path)
+	}
+	assert.Contains(t, paths, "/spec/commonConfig/startupStrategy")
+	assert.Contains(t, paths, "/spec/objects/servers")
+
+	cr.SetAnnotations(map[string]string{api.SkipSpecDefaultsAnnotation: "true"})
+	response = defaulter.Handle(context.TODO(), getAdmissionRequest(t, admissionv1.Create, cr, nil))
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+}
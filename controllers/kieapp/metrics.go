@@ -0,0 +1,165 @@
+package kieapp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Phases of the reconciliation measured by the reconcile metrics
+const (
+	environmentPhase = "environment"
+	routesPhase      = "routes"
+	keystorePhase    = "keystore"
+	applyPhase       = "apply"
+)
+
+// Actions counted by the resource changes metric
+const (
+	createdAction = "created"
+	updatedAction = "updated"
+	deletedAction = "deleted"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "kieapp_reconcile_phase_duration_seconds",
+		Help: "Duration of the phases of the KieApp reconciliation",
+	}, []string{"phase"})
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kieapp_reconcile_phase_errors_total",
+		Help: "Number of KieApp reconciliations failing in each phase",
+	}, []string{"phase"})
+	managedApps = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kieapp_managed_apps",
+		Help: "Number of KieApps managed by the operator, by environment, version and phase",
+	}, []string{"environment", "version", "phase"})
+	pendingUpgrades = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kieapp_upgrade_pending",
+		Help: "Set to 1 for the KieApps running an older version than the one the operator provides",
+	}, []string{"namespace", "name", "version", "available_version"})
+	keystoreExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kieapp_keystore_certificate_expiry_timestamp_seconds",
+		Help: "Expiry of the certificates held by the keystores generated for the KieApps, as a unix timestamp",
+	}, []string{"namespace", "name", "secret"})
+	resourceChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kieapp_resource_changes_total",
+		Help: "Number of objects created, updated and deleted by the operator, by kind",
+	}, []string{"kind", "action"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration, reconcileErrors, managedApps, pendingUpgrades, keystoreExpiry, resourceChanges)
+}
+
+// observePhase records the duration of a reconcile phase started at start, and its failure when err is set
+func observePhase(phase string, start time.Time, err error) {
+	reconcileDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+	if err != nil {
+		reconcileErrors.WithLabelValues(phase).Inc()
+	}
+}
+
+// countResourceChanges records the objects of a kind written to the cluster
+func countResourceChanges(kind, action string, count int) {
+	resourceChanges.WithLabelValues(kind, action).Add(float64(count))
+}
+
+// trackedApp is the state of a KieApp exposed through the metrics
+type trackedApp struct {
+	environment string
+	version     string
+	phase       string
+	keystores   []string
+}
+
+func (app *trackedApp) hasPendingUpgrade() bool {
+	return app.version != "" && app.version != constants.CurrentVersion
+}
+
+// appTracker keeps the state of the managed KieApps to compute the metrics aggregated over all of them
+type appTracker struct {
+	mutex sync.Mutex
+	apps  map[types.NamespacedName]*trackedApp
+}
+
+var trackedApps = &appTracker{}
+
+// get returns the state tracked for the KieApp, the caller holds the lock
+func (tracker *appTracker) get(name types.NamespacedName) *trackedApp {
+	if tracker.apps == nil {
+		tracker.apps = map[types.NamespacedName]*trackedApp{}
+	}
+	app, found := tracker.apps[name]
+	if !found {
+		app = &trackedApp{}
+		tracker.apps[name] = app
+	}
+	return app
+}
+
+// track records the environment, version and phase of the KieApp
+func (tracker *appTracker) track(instance *api.KieApp) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	name := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
+	app := tracker.get(name)
+	if app.hasPendingUpgrade() {
+		pendingUpgrades.DeleteLabelValues(name.Namespace, name.Name, app.version, constants.CurrentVersion)
+	}
+	app.environment = string(instance.Spec.Environment)
+	app.version = instance.Status.Applied.Version
+	app.phase = string(instance.Status.Phase)
+	if app.hasPendingUpgrade() {
+		pendingUpgrades.WithLabelValues(name.Namespace, name.Name, app.version, constants.CurrentVersion).Set(1)
+	}
+	tracker.update()
+}
+
+// trackKeystore records the expiry of a keystore generated for the KieApp
+func (tracker *appTracker) trackKeystore(instance *api.KieApp, secretName string, expiry time.Time) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	name := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
+	app := tracker.get(name)
+	keystoreExpiry.WithLabelValues(name.Namespace, name.Name, secretName).Set(float64(expiry.Unix()))
+	for _, keystore := range app.keystores {
+		if keystore == secretName {
+			return
+		}
+	}
+	app.keystores = append(app.keystores, secretName)
+}
+
+// untrack removes a deleted KieApp from the metrics
+func (tracker *appTracker) untrack(name types.NamespacedName) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	app, found := tracker.apps[name]
+	if !found {
+		return
+	}
+	for _, keystore := range app.keystores {
+		keystoreExpiry.DeleteLabelValues(name.Namespace, name.Name, keystore)
+	}
+	if app.hasPendingUpgrade() {
+		pendingUpgrades.DeleteLabelValues(name.Namespace, name.Name, app.version, constants.CurrentVersion)
+	}
+	delete(tracker.apps, name)
+	tracker.update()
+}
+
+// update recomputes the number of KieApps by environment, version and phase
+func (tracker *appTracker) update() {
+	managedApps.Reset()
+	for _, app := range tracker.apps {
+		if app.environment != "" {
+			managedApps.WithLabelValues(app.environment, app.version, app.phase).Inc()
+		}
+	}
+}
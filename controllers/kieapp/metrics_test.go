@@ -0,0 +1,70 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileMetrics(t *testing.T) {
+	name := types.NamespacedName{Name: "metrics", Namespace: "metrics-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "metrics-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	createdDeployments := testutil.ToFloat64(resourceChanges.WithLabelValues("Deployment", createdAction))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	assert.Equal(t, createdDeployments+2, testutil.ToFloat64(resourceChanges.WithLabelValues("Deployment", createdAction)))
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(reconcileDuration), 3, "environment, keystore and apply phases should be observed")
+	trackedApps.mutex.Lock()
+	app := *trackedApps.apps[name]
+	trackedApps.mutex.Unlock()
+	assert.Equal(t, string(api.RhpamTrial), app.environment)
+	assert.Equal(t, constants.CurrentVersion, app.version)
+	assert.Equal(t, string(api.ProvisioningConditionType), app.phase)
+	assert.NotEmpty(t, app.keystores)
+	assert.Less(t, float64(time.Now().Unix()), testutil.ToFloat64(keystoreExpiry.WithLabelValues(name.Namespace, name.Name, app.keystores[0])))
+
+	assert.Nil(t, service.Delete(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	trackedApps.mutex.Lock()
+	assert.NotContains(t, trackedApps.apps, name)
+	trackedApps.mutex.Unlock()
+}
+
+func TestPendingUpgradeMetric(t *testing.T) {
+	name := types.NamespacedName{Name: "upgrade", Namespace: "upgrade-ns"}
+	cr := &api.KieApp{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}
+	cr.Spec.Environment = api.RhpamProduction
+	cr.Status.Applied.Version = constants.PriorVersion
+	cr.Status.Phase = api.DeployedConditionType
+	trackedApps.track(cr)
+	assert.Equal(t, float64(1), testutil.ToFloat64(pendingUpgrades.WithLabelValues(name.Namespace, name.Name, constants.PriorVersion, constants.CurrentVersion)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(managedApps.WithLabelValues(string(api.RhpamProduction), constants.PriorVersion, string(api.DeployedConditionType))))
+
+	cr.Status.Applied.Version = constants.CurrentVersion
+	trackedApps.track(cr)
+	assert.Equal(t, 0, testutil.CollectAndCount(pendingUpgrades))
+
+	trackedApps.untrack(name)
+	assert.Equal(t, float64(0), testutil.ToFloat64(managedApps.WithLabelValues(string(api.RhpamProduction), constants.CurrentVersion, string(api.DeployedConditionType))))
+}
@@ -0,0 +1,67 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileMonitoring(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Monitoring:  &api.KieAppMonitoring{Enabled: true},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	serviceMonitors := &monv1.ServiceMonitorList{}
+	assert.Nil(t, service.List(context.TODO(), serviceMonitors, client.InNamespace(name.Namespace)))
+	var names []string
+	for _, serviceMonitor := range serviceMonitors.Items {
+		names = append(names, serviceMonitor.Name)
+	}
+	assert.ElementsMatch(t, []string{"test-rhpamcentr", "test-kieserver"}, names)
+	prometheusRules := &monv1.PrometheusRuleList{}
+	assert.Nil(t, service.List(context.TODO(), prometheusRules, client.InNamespace(name.Namespace)))
+	assert.Len(t, prometheusRules.Items, 1)
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The monitoring objects should not be updated when unchanged")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Monitoring.Enabled = false
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	serviceMonitors = &monv1.ServiceMonitorList{}
+	assert.Nil(t, service.List(context.TODO(), serviceMonitors, client.InNamespace(name.Namespace)))
+	assert.Empty(t, serviceMonitors.Items)
+	prometheusRules = &monv1.PrometheusRuleList{}
+	assert.Nil(t, service.List(context.TODO(), prometheusRules, client.InNamespace(name.Namespace)))
+	assert.Empty(t, prometheusRules.Items)
+	err = service.Get(context.TODO(), types.NamespacedName{Name: "test-monitoring", Namespace: name.Namespace}, &corev1.Secret{})
+	assert.True(t, errors.IsNotFound(err))
+}
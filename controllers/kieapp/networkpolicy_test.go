@@ -0,0 +1,57 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileNetworkPolicies(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:   api.RhpamTrial,
+			Platform:      api.KubernetesPlatform,
+			Exposure:      &api.KieAppExposure{Domain: "apps.example.com"},
+			NetworkPolicy: &api.KieAppNetworkPolicy{Enabled: true},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	networkPolicies := &networkingv1.NetworkPolicyList{}
+	assert.Nil(t, service.List(context.TODO(), networkPolicies, client.InNamespace(name.Namespace)))
+	var names []string
+	for _, networkPolicy := range networkPolicies.Items {
+		names = append(names, networkPolicy.Name)
+	}
+	assert.ElementsMatch(t, []string{"test-rhpamcentr", "test-kieserver"}, names)
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The NetworkPolicies should not be updated when unchanged")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.NetworkPolicy.Enabled = false
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	networkPolicies = &networkingv1.NetworkPolicyList{}
+	assert.Nil(t, service.List(context.TODO(), networkPolicies, client.InNamespace(name.Namespace)))
+	assert.Empty(t, networkPolicies.Items)
+}
@@ -0,0 +1,57 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileOverlays(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Overlays: []api.KieAppOverlay{{
+				Target:              api.KieAppOverlayTarget{Kind: "Deployment", Component: "servers"},
+				StrategicMergePatch: `{"metadata": {"annotations": {"example.com/owner": "kie"}}}`,
+			}},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}, deployment))
+	assert.Equal(t, "kie", deployment.Annotations["example.com/owner"])
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Equal(t, []api.AppliedOverlay{{Index: 0, Kind: "Deployment", Name: "test-kieserver"}}, cr.Status.Overlays)
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The patched resources should not be updated when unchanged")
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Overlays[0].StrategicMergePatch = `{"spec": {"replicas": "many"}}`
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Deployment test-kieserver")
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Equal(t, api.FailedConditionType, cr.Status.Phase)
+}
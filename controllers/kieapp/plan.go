@@ -0,0 +1,190 @@
+package kieapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// awaitApproval reports the plan in the status of the KieApp, leaving its resources untouched until the plan is approved
+func (reconciler *KieAppReconciler) awaitApproval(ctx context.Context, instance *api.KieApp, plan *api.KieAppPlan) (reconcile.Result, error) {
+	changed := !reflect.DeepEqual(instance.Status.Plan, plan)
+	instance.Status.Plan = plan
+	if status.SetPendingApproval(instance) || changed {
+		log.Infof("KieApp %s/%s waits for the approval of plan %s", instance.Namespace, instance.Name, plan.Hash)
+		if err := reconciler.writeStatus(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// isPlanPending tells whether the KieApp waits for the approval of its plan, in which case the operator writes nothing
+// but the status. The resources it would generate are planned from the deployed ones instead.
+func isPlanPending(instance *api.KieApp) bool {
+	return instance.Spec.RequireApproval && (instance.Status.Plan == nil || instance.GetAnnotations()[api.ApprovedPlanAnnotation] != instance.Status.Plan.Hash)
+}
+
+type plannedChange struct {
+	api.PlannedChange
+	values []interface{}
+	// digest of the changed credentials, whose values are neither reported nor left out of the hash
+	digest []byte
+}
+
+// getPlan summarizes the deltas between the deployed and the requested resources, nil when there is nothing to apply.
+// The hash of the plan covers the updated values, so a plan approved before the spec changed again is not applied.
+// The keystores and certificates the operator generates only once the plan is approved are left out of the hash, as
+// are the passwords generated for credentials the credentials Secret does not hold yet. The changed values of the
+// credentials it holds are covered by a digest instead.
+func getPlan(instance *api.KieApp, deltas map[reflect.Type]compare.ResourceDelta, deployed map[reflect.Type][]client.Object) *api.KieAppPlan {
+	credentialsSecret := defaults.GetCredentialsSecretName(instance)
+	var changes []plannedChange
+	for resourceType, delta := range deltas {
+		kind := resourceType.Name()
+		for _, object := range delta.Added {
+			changes = append(changes, plannedChange{PlannedChange: api.PlannedChange{Action: api.CreatePlannedAction, Kind: kind, Name: object.GetName()}})
+		}
+		for _, object := range delta.Updated {
+			change := plannedChange{PlannedChange: api.PlannedChange{Action: api.UpdatePlannedAction, Kind: kind, Name: object.GetName()}}
+			for _, deployedObject := range deployed[resourceType] {
+				if deployedObject.GetName() == object.GetName() {
+					change.Fields, change.values = getChangedFields(deployedObject, object)
+					if kind == "Secret" && object.GetName() == credentialsSecret {
+						change.digest = getCredentialsDigest(deployedObject.(*corev1.Secret), object.(*corev1.Secret))
+					}
+				}
+			}
+			changes = append(changes, change)
+		}
+		for _, object := range delta.Removed {
+			changes = append(changes, plannedChange{PlannedChange: api.PlannedChange{Action: api.DeletePlannedAction, Kind: kind, Name: object.GetName()}})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	plan := &api.KieAppPlan{}
+	hash := sha256.New()
+	for _, change := range changes {
+		plan.Changes = append(plan.Changes, change.PlannedChange)
+		fmt.Fprintf(hash, "%s %s/%s %v\n", change.Action, change.Kind, change.Name, change.Fields)
+		for i, value := range change.values {
+			if isGeneratedField(change.Kind, change.Fields[i]) {
+				continue
+			}
+			if encoded, err := json.Marshal(value); err == nil {
+				hash.Write(encoded)
+			}
+		}
+		hash.Write(change.digest)
+	}
+	plan.Hash = fmt.Sprintf("%x", hash.Sum(nil))
+	return plan
+}
+
+// getCredentialsDigest returns a digest of the credentials the requested Secret changes among the ones the deployed
+// Secret already holds, nil when none of them changes
+func getCredentialsDigest(deployed, requested *corev1.Secret) []byte {
+	var keys []string
+	for key, value := range requested.Data {
+		if deployedValue, found := deployed.Data[key]; found && !bytes.Equal(deployedValue, value) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	digest := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(digest, "%s=%s\n", key, requested.Data[key])
+	}
+	return digest.Sum(nil)
+}
+
+// isGeneratedField tells whether the value of the field is generated by the operator, differing between the planned
+// and the applied resource
+func isGeneratedField(kind, field string) bool {
+	return (kind == "Secret" && strings.HasPrefix(field, "data.")) || strings.HasSuffix(field, "."+constants.CertificatesHashAnnotation)
+}
+
+// getChangedFields returns the paths of the fields set in the requested resource that differ from the deployed one,
+// along with their requested values. The metadata is limited to labels and annotations.
+func getChangedFields(deployed, requested client.Object) ([]string, []interface{}) {
+	deployedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployed)
+	if err != nil {
+		return nil, nil
+	}
+	requestedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(requested)
+	if err != nil {
+		return nil, nil
+	}
+	for _, ignored := range []string{"apiVersion", "kind", "status"} {
+		delete(deployedMap, ignored)
+		delete(requestedMap, ignored)
+	}
+	for _, field := range []map[string]interface{}{deployedMap, requestedMap} {
+		if metadata, ok := field["metadata"].(map[string]interface{}); ok {
+			field["metadata"] = map[string]interface{}{"labels": metadata["labels"], "annotations": metadata["annotations"]}
+		}
+	}
+	var fields []string
+	var values []interface{}
+	diffFields("", deployedMap, requestedMap, &fields, &values)
+	return fields, values
+}
+
+func diffFields(path string, deployed, requested interface{}, fields *[]string, values *[]interface{}) {
+	if requested == nil || reflect.DeepEqual(deployed, requested) {
+		return
+	}
+	switch requestedValue := requested.(type) {
+	case map[string]interface{}:
+		if deployedValue, ok := deployed.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(requestedValue))
+			for key := range requestedValue {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				diffFields(child, deployedValue[key], requestedValue[key], fields, values)
+			}
+			return
+		}
+	case []interface{}:
+		if deployedValue, ok := deployed.([]interface{}); ok && len(deployedValue) == len(requestedValue) {
+			for i := range requestedValue {
+				diffFields(fmt.Sprintf("%s[%d]", path, i), deployedValue[i], requestedValue[i], fields, values)
+			}
+			return
+		}
+	}
+	*fields = append(*fields, path)
+	*values = append(*values, requested)
+}
@@ -0,0 +1,215 @@
+package kieapp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
+	routev1 "github.com/openshift/api/route/v1"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRequireApproval(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:     api.RhpamTrial,
+			Platform:        api.KubernetesPlatform,
+			Exposure:        &api.KieAppExposure{Domain: "apps.example.com"},
+			RequireApproval: true,
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getDeploymentReplicas(t, service, name.Namespace))
+
+	planned := &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, planned))
+	assert.Equal(t, api.PendingApprovalConditionType, planned.Status.Phase)
+	assert.NotNil(t, planned.Status.Plan)
+	assert.Contains(t, planned.Status.Plan.Changes, api.PlannedChange{Action: api.CreatePlannedAction, Kind: "Deployment", Name: "test-rhpamcentr"})
+	hash := planned.Status.Plan.Hash
+
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	planned = &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, planned))
+	assert.Equal(t, hash, planned.Status.Plan.Hash, "Plan should not change between reconciliations")
+	assert.Contains(t, planned.Status.Plan.Changes, api.PlannedChange{Action: api.CreatePlannedAction, Kind: "Secret", Name: "test-businesscentral-app-secret"})
+	secrets := &corev1.SecretList{}
+	assert.Nil(t, service.List(context.TODO(), secrets, client.InNamespace(name.Namespace)))
+	assert.Empty(t, secrets.Items, "No secret should be generated before the plan is approved")
+
+	planned.SetAnnotations(map[string]string{api.ApprovedPlanAnnotation: hash})
+	assert.Nil(t, service.Update(context.TODO(), planned))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, getDeploymentReplicas(t, service, name.Namespace))
+	applied := &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, applied))
+	assert.Nil(t, applied.Status.Plan)
+}
+
+func TestRequireApprovalOnOpenShift(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:     api.RhpamTrial,
+			RequireApproval: true,
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	for i := 0; i < 2; i++ {
+		result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+		assert.Nil(t, err)
+		assert.False(t, result.Requeue)
+	}
+	planned := &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, planned))
+	assert.Equal(t, api.PendingApprovalConditionType, planned.Status.Phase)
+	assert.Contains(t, planned.Status.Plan.Changes, api.PlannedChange{Action: api.CreatePlannedAction, Kind: "Route", Name: "test-rhpamcentr"})
+
+	routes := &routev1.RouteList{}
+	assert.Nil(t, service.List(context.TODO(), routes, client.InNamespace(name.Namespace)))
+	assert.Empty(t, routes.Items, "No route should be created before the plan is approved")
+	secrets := &corev1.SecretList{}
+	assert.Nil(t, service.List(context.TODO(), secrets, client.InNamespace(name.Namespace)))
+	assert.Empty(t, secrets.Items, "No secret should be generated before the plan is approved")
+
+	planned.SetAnnotations(map[string]string{api.ApprovedPlanAnnotation: planned.Status.Plan.Hash})
+	assert.Nil(t, service.Update(context.TODO(), planned))
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Nil(t, service.List(context.TODO(), routes, client.InNamespace(name.Namespace)))
+	assert.NotEmpty(t, routes.Items, "The routes should be created with the approved plan")
+	assert.Nil(t, service.List(context.TODO(), secrets, client.InNamespace(name.Namespace)))
+	assert.NotEmpty(t, secrets.Items, "The secrets should be generated with the approved plan")
+}
+
+func TestGetPlanIgnoresGeneratedValues(t *testing.T) {
+	deployed := map[reflect.Type][]client.Object{}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-app-secret"}, Data: map[string][]byte{"keystore.jks": []byte("old")}}
+	deployed[reflect.TypeOf(corev1.Secret{})] = []client.Object{secret}
+	planned := secret.DeepCopy()
+	planned.Data["keystore.jks"] = []byte{}
+	generated := secret.DeepCopy()
+	generated.Data["keystore.jks"] = []byte("new")
+	plannedPlan := getPlan(&api.KieApp{ObjectMeta: metav1.ObjectMeta{Name: "test"}}, map[reflect.Type]compare.ResourceDelta{reflect.TypeOf(corev1.Secret{}): {Updated: []client.Object{planned}}}, deployed)
+	generatedPlan := getPlan(&api.KieApp{ObjectMeta: metav1.ObjectMeta{Name: "test"}}, map[reflect.Type]compare.ResourceDelta{reflect.TypeOf(corev1.Secret{}): {Updated: []client.Object{generated}}}, deployed)
+	assert.Equal(t, []string{"data.keystore.jks"}, plannedPlan.Changes[0].Fields)
+	assert.Equal(t, plannedPlan.Hash, generatedPlan.Hash, "The generated keystore should not change the plan")
+}
+
+func TestGetPlanCoversCredentials(t *testing.T) {
+	cr := &api.KieApp{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-credentials"}, Data: map[string][]byte{"adminPassword": []byte("old")}}
+	deployed := map[reflect.Type][]client.Object{reflect.TypeOf(corev1.Secret{}): {secret}}
+	getSecretPlan := func(data map[string][]byte) *api.KieAppPlan {
+		requested := secret.DeepCopy()
+		for key, value := range data {
+			requested.Data[key] = value
+		}
+		return getPlan(cr, map[reflect.Type]compare.ResourceDelta{reflect.TypeOf(corev1.Secret{}): {Updated: []client.Object{requested}}}, deployed)
+	}
+	changed := getSecretPlan(map[string][]byte{"adminPassword": []byte("new")})
+	assert.Equal(t, []string{"data.adminPassword"}, changed.Changes[0].Fields)
+	assert.NotEqual(t, changed.Hash, getSecretPlan(map[string][]byte{"adminPassword": []byte("other")}).Hash,
+		"A plan approved for a password should not apply another one")
+
+	added := getSecretPlan(map[string][]byte{"amqPassword": []byte("generated")})
+	assert.Equal(t, added.Hash, getSecretPlan(map[string][]byte{"amqPassword": []byte("regenerated")}).Hash,
+		"The passwords generated until the plan is approved should not change it")
+}
+
+func TestRequireApprovalDetachesKieServerConfigMaps(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:     api.RhpamAuthoring,
+			Platform:        api.KubernetesPlatform,
+			Exposure:        &api.KieAppExposure{Domain: "apps.example.com"},
+			RequireApproval: true,
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	approve := func() {
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+		assert.Nil(t, err)
+		planned := &api.KieApp{}
+		assert.Nil(t, service.Get(context.TODO(), name, planned))
+		assert.NotNil(t, planned.Status.Plan)
+		planned.SetAnnotations(map[string]string{api.ApprovedPlanAnnotation: planned.Status.Plan.Hash})
+		assert.Nil(t, service.Update(context.TODO(), planned))
+		_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+		assert.Nil(t, err)
+	}
+	approve()
+	assert.Equal(t, int32(1), getDeploymentReplicas(t, service, name.Namespace)["test-kieserver"])
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-kieserver",
+			Namespace:       name.Namespace,
+			Labels:          map[string]string{constants.KieServerCMLabel: "USED"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "test-kieserver"}},
+		},
+	}
+	assert.Nil(t, service.Create(context.TODO(), cm))
+	cr = &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Objects.Servers = []api.KieServerSet{{KieAppObject: api.KieAppObject{Replicas: defaults.Pint32(0)}}}
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	approve()
+	assert.Equal(t, int32(0), getDeploymentReplicas(t, service, name.Namespace)["test-kieserver"])
+	assert.Nil(t, service.Get(context.TODO(), client.ObjectKeyFromObject(cm), cm))
+	assert.Equal(t, "DETACHED", cm.Labels[constants.KieServerCMLabel], "The ConfigMap of the approved scaled down kieserver should be detached")
+}
+
+func TestGetChangedFields(t *testing.T) {
+	deployed := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", ResourceVersion: "1", Labels: map[string]string{"app": "test"}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: defaults.Pint32(1),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "test", Image: "image:1", TerminationMessagePath: "/dev/termination-log"}},
+				},
+			},
+		},
+	}
+	requested := deployed.DeepCopy()
+	requested.ResourceVersion = ""
+	requested.Spec.Template.Spec.Containers[0].TerminationMessagePath = ""
+	fields, values := getChangedFields(deployed, requested)
+	assert.Empty(t, fields)
+	assert.Empty(t, values)
+
+	requested.Labels["app"] = "other"
+	requested.Spec.Replicas = defaults.Pint32(2)
+	requested.Spec.Template.Spec.Containers[0].Image = "image:2"
+	fields, values = getChangedFields(deployed, requested)
+	assert.Equal(t, []string{"metadata.labels.app", "spec.replicas", "spec.template.spec.containers[0].image"}, fields)
+	assert.Equal(t, []interface{}{"other", int64(2), "image:2"}, values)
+}
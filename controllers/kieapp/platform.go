@@ -0,0 +1,51 @@
+package kieapp
+
+import (
+	oappsv1 "github.com/openshift/api/apps/v1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+// DetectPlatform queries the API discovery information to find out whether the cluster serves OpenShift's DeploymentConfigs
+func DetectPlatform(mapper meta.RESTMapper) api.PlatformType {
+	_, err := mapper.RESTMapping(oappsv1.GroupVersion.WithKind("DeploymentConfig").GroupKind(), oappsv1.GroupVersion.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			log.Infof("DeploymentConfig API not found, using the %s platform", api.KubernetesPlatform)
+			return api.KubernetesPlatform
+		}
+		log.Warn("Unable to discover the DeploymentConfig API. ", err)
+	}
+	return api.OpenShiftPlatform
+}
+
+// getPlatform returns the platform requested in the KieApp spec, falling back to the one detected for the cluster
+func (reconciler *KieAppReconciler) getPlatform(cr *api.KieApp) api.PlatformType {
+	if cr.Spec.Platform != "" {
+		return cr.Spec.Platform
+	}
+	if reconciler.Platform != "" {
+		return reconciler.Platform
+	}
+	return api.OpenShiftPlatform
+}
+
+// hasHTTPRoutes returns true when the Gateway API CRDs are installed in the cluster
+func hasHTTPRoutes(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(gatewayv1alpha1.SchemeGroupVersion.WithKind("HTTPRoute").GroupKind(), gatewayv1alpha1.SchemeGroupVersion.Version)
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Warn("Unable to discover the HTTPRoute API. ", err)
+	}
+	return err == nil
+}
+
+// hasCertificates returns true when the cert-manager CRDs are installed in the cluster
+func hasCertificates(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(certmanagerv1.GroupVersion.WithKind(certmanagerv1.CertificateKind).GroupKind(), certmanagerv1.GroupVersion.Version)
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Warn("Unable to discover the Certificate API. ", err)
+	}
+	return err == nil
+}
@@ -0,0 +1,61 @@
+package kieapp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileRestrictedSecurityContexts(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment:           api.RhpamTrial,
+			Platform:              api.KubernetesPlatform,
+			Exposure:              &api.KieAppExposure{Domain: "apps.example.com"},
+			SecurityContextPreset: api.RestrictedSecurityContextPreset,
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{KieAppObject: api.KieAppObject{
+					SecurityContext: &corev1.SecurityContext{Privileged: defaults.Pbool(true)},
+				}}},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "privileged")
+	assert.Empty(t, getDeploymentReplicas(t, service, name.Namespace), "No resource should be applied")
+	events := getEvents(recorder)
+	assert.Len(t, events, 1)
+	assert.True(t, strings.HasPrefix(events[0], "Warning "+string(api.ConfigurationErrorReason)))
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Objects.Servers[0].SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: defaults.Pbool(true)}
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}, deployment))
+	assert.Equal(t, defaults.Pbool(true), deployment.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+	assert.Equal(t, defaults.Pbool(true), deployment.Spec.Template.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem)
+	deployments := &appsv1.DeploymentList{}
+	assert.Nil(t, service.List(context.TODO(), deployments, client.InNamespace(name.Namespace)))
+	assert.Len(t, deployments.Items, 2)
+}
@@ -0,0 +1,501 @@
+package shared
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// The PKCS #12 stores are encoded with the modern parameters of the JDK keytool since Java 8u301 and 11.0.12: the
+// private keys are shrouded with PBES2, PBKDF2-HMAC-SHA256 and AES-256-CBC, and the integrity is protected by a
+// HMAC-SHA256. The certificates are left unencrypted, so that the truststores can be read without their password.
+// See https://tools.ietf.org/html/rfc7292 and https://tools.ietf.org/html/rfc8018
+const (
+	pkcs12Iterations = 2048
+	pkcs12SaltLength = 16
+)
+
+var (
+	oidDataContentType         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS8ShroudedKeyBag     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidCertTypeX509Certificate = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidPBES2                   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2                  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHmacWithSHA256          = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidSHA256                  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidFriendlyName            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidLocalKeyID              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	// Java only loads the certificates flagged with this attribute as trusted certificate entries
+	oidJavaTrustedKeyUsage = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+	oidAnyExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37, 0}
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	AlgorithmIdentifier pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	Prf        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// trustedCertificate is a certificate entry of a PKCS #12 truststore
+type trustedCertificate struct {
+	alias       string
+	certificate []byte
+}
+
+// encodePKCS12Keystore returns a PKCS #12 keystore holding the PKCS #8 private key and its certificate chain, leaf first,
+// under the alias
+func encodePKCS12Keystore(alias string, derPK []byte, chain [][]byte, password []byte) ([]byte, error) {
+	localKeyID := sha1.Sum(chain[0])
+	keyAttributes, err := keyEntryAttributes(alias, localKeyID[:])
+	if err != nil {
+		return nil, err
+	}
+	var certBags []safeBag
+	for i, certificate := range chain {
+		var attributes []pkcs12Attribute
+		if i == 0 {
+			attributes = keyAttributes
+		}
+		bag, err := newCertBag(certificate, attributes)
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+	keyBag, err := newShroudedKeyBag(derPK, password, keyAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return encodePFX(certBags, []safeBag{keyBag}, password)
+}
+
+// encodePKCS12Truststore returns a PKCS #12 truststore holding the certificates as trusted certificate entries
+func encodePKCS12Truststore(certificates []trustedCertificate, password []byte) ([]byte, error) {
+	trustedUsage, err := newAttribute(oidJavaTrustedKeyUsage, oidAnyExtendedKeyUsage)
+	if err != nil {
+		return nil, err
+	}
+	var certBags []safeBag
+	for _, entry := range certificates {
+		friendlyName, err := newFriendlyName(entry.alias)
+		if err != nil {
+			return nil, err
+		}
+		bag, err := newCertBag(entry.certificate, []pkcs12Attribute{friendlyName, trustedUsage})
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+	return encodePFX(certBags, nil, password)
+}
+
+// encodePFX returns the PFX of the certificate and key bags, which are stored in two distinct safe contents. The
+// truststores, holding no key, have a single safe contents.
+func encodePFX(certBags, keyBags []safeBag, password []byte) ([]byte, error) {
+	var authenticatedSafe []contentInfo
+	for _, bags := range [][]safeBag{certBags, keyBags} {
+		if bags == nil {
+			continue
+		}
+		safeContents, err := asn1.Marshal(bags)
+		if err != nil {
+			return nil, err
+		}
+		content, err := newDataContentInfo(safeContents)
+		if err != nil {
+			return nil, err
+		}
+		authenticatedSafe = append(authenticatedSafe, content)
+	}
+	authenticatedSafeBytes, err := asn1.Marshal(authenticatedSafe)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := randomBytes(pkcs12SaltLength)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := computeMac(authenticatedSafeBytes, salt, password, pkcs12Iterations)
+	if err != nil {
+		return nil, err
+	}
+	authSafe, err := newDataContentInfo(authenticatedSafeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pfxPdu{
+		Version:  3,
+		AuthSafe: authSafe,
+		MacData: macData{
+			Mac: digestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+				Digest:    digest,
+			},
+			MacSalt:    salt,
+			Iterations: pkcs12Iterations,
+		},
+	})
+}
+
+// decodePKCS12 returns the safe bags of a PKCS #12 store encoded by encodePFX, once its MAC has been verified with the
+// password. Stores with other MAC algorithms or encrypted safe contents, e.g. written by former versions, are rejected.
+func decodePKCS12(data, password []byte) ([]safeBag, error) {
+	var pfx pfxPdu
+	if rest, err := asn1.Unmarshal(data, &pfx); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, fmt.Errorf("trailing data found after the PKCS #12 store")
+	}
+	if pfx.Version != 3 {
+		return nil, fmt.Errorf("unsupported PKCS #12 version %d", pfx.Version)
+	}
+	authenticatedSafeBytes, err := dataContent(pfx.AuthSafe)
+	if err != nil {
+		return nil, err
+	}
+	if !pfx.MacData.Mac.Algorithm.Algorithm.Equal(oidSHA256) {
+		return nil, fmt.Errorf("unsupported PKCS #12 MAC algorithm %s", pfx.MacData.Mac.Algorithm.Algorithm)
+	}
+	digest, err := computeMac(authenticatedSafeBytes, pfx.MacData.MacSalt, password, pfx.MacData.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(digest, pfx.MacData.Mac.Digest) {
+		return nil, fmt.Errorf("the PKCS #12 MAC does not match, the password is incorrect")
+	}
+	var authenticatedSafe []contentInfo
+	if _, err := asn1.Unmarshal(authenticatedSafeBytes, &authenticatedSafe); err != nil {
+		return nil, err
+	}
+	var bags []safeBag
+	for _, content := range authenticatedSafe {
+		safeContents, err := dataContent(content)
+		if err != nil {
+			return nil, err
+		}
+		var contentBags []safeBag
+		if _, err := asn1.Unmarshal(safeContents, &contentBags); err != nil {
+			return nil, err
+		}
+		bags = append(bags, contentBags...)
+	}
+	return bags, nil
+}
+
+// computeMac returns the HMAC-SHA256 of the data, keyed as defined by https://tools.ietf.org/html/rfc7292#appendix-B
+func computeMac(data, salt, password []byte, iterations int) ([]byte, error) {
+	bmpPassword, err := bmpString(password)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, pkcs12KDF(salt, bmpPassword, iterations, 3, sha256.Size))
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func newDataContentInfo(data []byte) (contentInfo, error) {
+	content, err := asn1.Marshal(data)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{ContentType: oidDataContentType, Content: explicitTag(content)}, nil
+}
+
+// dataContent returns the octets of a content info of the data content type
+func dataContent(content contentInfo) ([]byte, error) {
+	if !content.ContentType.Equal(oidDataContentType) {
+		return nil, fmt.Errorf("unsupported PKCS #12 content type %s", content.ContentType)
+	}
+	var data []byte
+	if _, err := asn1.Unmarshal(content.Content.Bytes, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func newCertBag(certificate []byte, attributes []pkcs12Attribute) (safeBag, error) {
+	bag, err := asn1.Marshal(certBag{ID: oidCertTypeX509Certificate, Data: certificate})
+	if err != nil {
+		return safeBag{}, err
+	}
+	return safeBag{ID: oidCertBag, Value: explicitTag(bag), Attributes: attributes}, nil
+}
+
+// decodeCertBag returns the DER encoded X.509 certificate of a certificate bag
+func decodeCertBag(bag safeBag) ([]byte, error) {
+	var cert certBag
+	if _, err := asn1.Unmarshal(bag.Value.Bytes, &cert); err != nil {
+		return nil, err
+	}
+	if !cert.ID.Equal(oidCertTypeX509Certificate) {
+		return nil, fmt.Errorf("unsupported PKCS #12 certificate type %s", cert.ID)
+	}
+	return cert.Data, nil
+}
+
+func newShroudedKeyBag(derPK, password []byte, attributes []pkcs12Attribute) (safeBag, error) {
+	salt, err := randomBytes(pkcs12SaltLength)
+	if err != nil {
+		return safeBag{}, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return safeBag{}, err
+	}
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:       salt,
+		Iterations: pkcs12Iterations,
+		Prf:        pkix.AlgorithmIdentifier{Algorithm: oidHmacWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return safeBag{}, err
+	}
+	ivParams, err := asn1.Marshal(iv)
+	if err != nil {
+		return safeBag{}, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParams}},
+	})
+	if err != nil {
+		return safeBag{}, err
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(password, salt, pkcs12Iterations, 32, sha256.New))
+	if err != nil {
+		return safeBag{}, err
+	}
+	padding := block.BlockSize() - len(derPK)%block.BlockSize()
+	encrypted := append(append([]byte{}, derPK...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+	bag, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		AlgorithmIdentifier: pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData:       encrypted,
+	})
+	if err != nil {
+		return safeBag{}, err
+	}
+	return safeBag{ID: oidPKCS8ShroudedKeyBag, Value: explicitTag(bag), Attributes: attributes}, nil
+}
+
+// decryptShroudedKeyBag returns the PKCS #8 private key of a key bag shrouded by newShroudedKeyBag
+func decryptShroudedKeyBag(bag safeBag, password []byte) ([]byte, error) {
+	var keyInfo encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(bag.Value.Bytes, &keyInfo); err != nil {
+		return nil, err
+	}
+	if !keyInfo.AlgorithmIdentifier.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported PKCS #12 key encryption %s", keyInfo.AlgorithmIdentifier.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(keyInfo.AlgorithmIdentifier.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, err
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) || !kdfParams.Prf.Algorithm.Equal(oidHmacWithSHA256) ||
+		!params.EncryptionScheme.Algorithm.Equal(oidAES256CBC) {
+		return nil, fmt.Errorf("unsupported PKCS #12 PBES2 parameters")
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(password, kdfParams.Salt, kdfParams.Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	encrypted := keyInfo.EncryptedData
+	if len(encrypted) == 0 || len(encrypted)%block.BlockSize() != 0 || len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("the PKCS #12 private key is not a multiple of the block size")
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, encrypted)
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > block.BlockSize() ||
+		!bytes.Equal(decrypted[len(decrypted)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, fmt.Errorf("the PKCS #12 private key could not be decrypted")
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// keyEntryAttributes returns the attributes pairing a private key with the leaf certificate of its chain
+func keyEntryAttributes(alias string, localKeyID []byte) ([]pkcs12Attribute, error) {
+	friendlyName, err := newFriendlyName(alias)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := newAttribute(oidLocalKeyID, localKeyID)
+	if err != nil {
+		return nil, err
+	}
+	return []pkcs12Attribute{friendlyName, keyID}, nil
+}
+
+func newFriendlyName(alias string) (pkcs12Attribute, error) {
+	name, err := bmpString([]byte(alias))
+	if err != nil {
+		return pkcs12Attribute{}, err
+	}
+	// the BMPString of the attribute is not NULL terminated
+	return newAttribute(oidFriendlyName, asn1.RawValue{Tag: asn1.TagBMPString, Bytes: name[:len(name)-2]})
+}
+
+// friendlyName returns the alias of a bag, empty when it has none
+func friendlyName(bag safeBag) (string, error) {
+	for _, attribute := range bag.Attributes {
+		if !attribute.ID.Equal(oidFriendlyName) {
+			continue
+		}
+		var name asn1.RawValue
+		if _, err := asn1.Unmarshal(attribute.Value.Bytes, &name); err != nil {
+			return "", err
+		}
+		if name.Tag != asn1.TagBMPString || len(name.Bytes)%2 != 0 {
+			return "", fmt.Errorf("the PKCS #12 friendly name is not a BMPString")
+		}
+		units := make([]uint16, 0, len(name.Bytes)/2)
+		for i := 0; i < len(name.Bytes); i += 2 {
+			units = append(units, uint16(name.Bytes[i])<<8|uint16(name.Bytes[i+1]))
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	return "", nil
+}
+
+func newAttribute(id asn1.ObjectIdentifier, value interface{}) (pkcs12Attribute, error) {
+	der, err := asn1.Marshal(value)
+	if err != nil {
+		return pkcs12Attribute{}, err
+	}
+	return pkcs12Attribute{ID: id, Value: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: der}}, nil
+}
+
+// explicitTag wraps the DER encoding in the [0] EXPLICIT tag, which encoding/asn1 does not add to raw values
+func explicitTag(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+func randomBytes(length int) ([]byte, error) {
+	random := make([]byte, length)
+	if _, err := crand.Read(random); err != nil {
+		return nil, err
+	}
+	return random, nil
+}
+
+// bmpString returns the NULL terminated UCS-2 encoding of the password, as expected by the PKCS #12 key derivation
+func bmpString(password []byte) ([]byte, error) {
+	encoded := make([]byte, 0, 2*len(password)+2)
+	for _, r := range string(password) {
+		if r1, _ := utf16.EncodeRune(r); r1 != 0xfffd {
+			return nil, fmt.Errorf("the password contains characters that cannot be encoded in a PKCS #12 store")
+		}
+		encoded = append(encoded, byte(r>>8), byte(r))
+	}
+	return append(encoded, 0, 0), nil
+}
+
+// pkcs12KDF derives size bytes of MAC key (id 3) from the password with SHA-256, as defined by
+// https://tools.ietf.org/html/rfc7292#appendix-B.2
+func pkcs12KDF(salt, password []byte, iterations int, id byte, size int) []byte {
+	const v = sha256.BlockSize
+	diversifier := bytes.Repeat([]byte{id}, v)
+	input := append(fillBlocks(salt, v), fillBlocks(password, v)...)
+	var output []byte
+	for len(output) < size {
+		digest := sha256.Sum256(append(diversifier, input...))
+		for i := 1; i < iterations; i++ {
+			digest = sha256.Sum256(digest[:])
+		}
+		output = append(output, digest[:]...)
+		if len(output) >= size {
+			break
+		}
+		increment := new(big.Int).SetBytes(fillBlocks(digest[:], v))
+		increment.Add(increment, big.NewInt(1))
+		for j := 0; j < len(input); j += v {
+			block := new(big.Int).SetBytes(input[j : j+v])
+			sum := block.Add(block, increment).Bytes()
+			if len(sum) > v {
+				sum = sum[len(sum)-v:]
+			}
+			copy(input[j:j+v], make([]byte, v-len(sum)))
+			copy(input[j+v-len(sum):j+v], sum)
+		}
+	}
+	return output[:size]
+}
+
+// fillBlocks concatenates copies of the pattern up to the next multiple of the block size
+func fillBlocks(pattern []byte, blockSize int) []byte {
+	if len(pattern) == 0 {
+		return nil
+	}
+	length := blockSize * ((len(pattern) + blockSize - 1) / blockSize)
+	return bytes.Repeat(pattern, (length+len(pattern)-1)/len(pattern))[:length]
+}
@@ -2,10 +2,12 @@ package shared
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/md5"
 	crand "crypto/rand"
 	"crypto/rsa"
-	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/hex"
@@ -16,7 +18,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"math/big"
 	"math/rand"
-	"reflect"
 	"strings"
 	"time"
 
@@ -27,23 +28,227 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-// GenerateKeystore returns a Java Keystore with a self-signed certificate
-func GenerateKeystore(commonName string, password []byte) ([]byte, error) {
-	var b bytes.Buffer
-	certificate, derPK, err := genCert(commonName)
+// CertificateAuthority signs the certificates of the keystores generated for the components of a KieApp
+type CertificateAuthority struct {
+	Certificate *x509.Certificate
+	PrivateKey  crypto.Signer
+}
+
+// GenerateCA returns a new self-signed certificate authority with a private key of the algorithm and size of the options
+func GenerateCA(commonName string, opts KeystoreOptions) (*CertificateAuthority, error) {
+	serialNumber, err := genSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	priv, err := genKey(opts)
+	if err != nil {
+		log.Error("create key failed. ", err)
+		return nil, err
+	}
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(constants.CAValidity, 0, 0),
+		SerialNumber:          serialNumber,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, priv.Public(), priv)
+	if err != nil {
+		log.Error("create cert failed. ", err)
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateAuthority{Certificate: cert, PrivateKey: priv}, nil
+}
+
+// ParseCA returns the certificate authority held by a PEM encoded certificate and private key. An error is returned
+// when the certificate is not a CA, has expired or does not match the key.
+func ParseCA(certPEM, keyPEM []byte) (*CertificateAuthority, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %s is not a CA", cert.Subject.CommonName)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, fmt.Errorf("certificate %s expired on %s", cert.Subject.CommonName, cert.NotAfter)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	derPK, err := toPKCS8(keyBlock)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(derPK)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok || !publicKeyEqual(signer.Public(), cert.PublicKey) {
+		return nil, fmt.Errorf("private key does not match certificate %s", cert.Subject.CommonName)
+	}
+	return &CertificateAuthority{Certificate: cert, PrivateKey: signer}, nil
+}
+
+// publicKeyEqual tells whether the RSA or ECDSA public keys are the same
+func publicKeyEqual(key, other crypto.PublicKey) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k.Equal(other)
+	case *ecdsa.PublicKey:
+		return k.Equal(other)
+	}
+	return false
+}
+
+// HasKeyAlgorithm tells whether the private key of the CA has the algorithm and size of the options
+func (ca *CertificateAuthority) HasKeyAlgorithm(opts KeystoreOptions) bool {
+	return hasKeyAlgorithm(ca.Certificate, opts)
+}
+
+// CertificatePEM returns the PEM encoded certificate of the CA
+func (ca *CertificateAuthority) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate.Raw})
+}
+
+// PrivateKeyPEM returns the PEM encoded PKCS #8 private key of the CA
+func (ca *CertificateAuthority) PrivateKeyPEM() []byte {
+	derPK, err := x509.MarshalPKCS8PrivateKey(ca.PrivateKey)
+	if err != nil {
+		log.Error("Marshal to PKCS8 key failed. ", err)
+		return nil
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derPK})
+}
+
+// KeystoreOptions defines the format of the generated keystores and truststores, and the algorithm and size in bits of
+// the private keys of the keystores. The zero value stands for JKS stores with 2048 bits RSA keys.
+type KeystoreOptions struct {
+	Format       string
+	KeyAlgorithm string
+	KeySize      int
+}
+
+// GetKeyAlgorithm returns the algorithm and size of the private keys, defaulted when not set
+func (opts KeystoreOptions) GetKeyAlgorithm() (string, int) {
+	algorithm, size := opts.KeyAlgorithm, opts.KeySize
+	if algorithm != constants.ECDSAKeyAlgorithm {
+		algorithm = constants.RSAKeyAlgorithm
+	}
+	if size == 0 {
+		size = constants.DefaultRSAKeySize
+		if algorithm == constants.ECDSAKeyAlgorithm {
+			size = constants.DefaultECDSAKeySize
+		}
+	}
+	return algorithm, size
+}
+
+// KeystoreName returns the key of the keystore in the secrets holding keystores of the format
+func KeystoreName(format string) string {
+	if format == constants.PKCS12KeystoreFormat {
+		return constants.PKCS12KeystoreName
+	}
+	return constants.KeystoreName
+}
+
+// TruststoreName returns the key of the truststore in the secrets holding truststores of the format
+func TruststoreName(format string) string {
+	if format == constants.PKCS12KeystoreFormat {
+		return constants.PKCS12TruststoreName
+	}
+	return constants.TruststoreName
+}
+
+// KeystoreType returns the Java KeyStore type of the format
+func KeystoreType(format string) string {
+	if format == constants.PKCS12KeystoreFormat {
+		return "PKCS12"
+	}
+	return "JKS"
+}
+
+// GenerateKeystore returns a keystore of the format of the options with a certificate for the common name and DNS names,
+// signed by the CA
+func GenerateKeystore(commonName string, dnsNames []string, password []byte, ca *CertificateAuthority, opts KeystoreOptions) ([]byte, error) {
+	certificate, derPK, err := genCert(commonName, dnsNames, ca, opts)
 	if err != nil {
 		return []byte{}, err
 	}
+	return storeKeystore(derPK, [][]byte{certificate, ca.Certificate.Raw}, password, opts.Format)
+}
+
+// ConvertToKeystore returns a keystore of the format holding the PEM encoded certificate chain and private key, e.g. the
+// ones of a secret issued by cert-manager
+func ConvertToKeystore(certPEM, keyPEM, password []byte, format string) ([]byte, error) {
+	var chain [][]byte
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			chain = append(chain, block.Bytes)
+		}
+	}
+	if len(chain) == 0 {
+		return []byte{}, fmt.Errorf("no PEM encoded certificate found")
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return []byte{}, fmt.Errorf("no PEM encoded private key found")
+	}
+	derPK, err := toPKCS8(keyBlock)
+	if err != nil {
+		return []byte{}, err
+	}
+	return storeKeystore(derPK, chain, password, format)
+}
+
+// toPKCS8 returns the PKCS #8 encoding of a PKCS #1, SEC 1 or PKCS #8 private key, the one held by Java Keystores
+func toPKCS8(block *pem.Block) ([]byte, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKCS8PrivateKey(key)
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKCS8PrivateKey(key)
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		return nil, err
+	}
+	return block.Bytes, nil
+}
+
+func storeKeystore(derPK []byte, chain [][]byte, password []byte, format string) ([]byte, error) {
+	if format == constants.PKCS12KeystoreFormat {
+		return encodePKCS12Keystore(constants.KeystoreAlias, derPK, chain, password)
+	}
+	var b bytes.Buffer
+	certificateChain := make([]keystore.Certificate, 0, len(chain))
+	for _, certificate := range chain {
+		certificateChain = append(certificateChain, keystore.Certificate{Type: "X509", Content: certificate})
+	}
 	keyStore := keystore.New(keystore.WithOrderedAliases())
 	pkeIn := keystore.PrivateKeyEntry{
-		CreationTime: time.Now(),
-		PrivateKey:   derPK,
-		CertificateChain: []keystore.Certificate{
-			{
-				Type:    "X509",
-				Content: certificate,
-			},
-		},
+		CreationTime:     time.Now(),
+		PrivateKey:       derPK,
+		CertificateChain: certificateChain,
 	}
 	if err := keyStore.SetPrivateKeyEntry(constants.KeystoreAlias, pkeIn, password); err != nil {
 		return []byte{}, err
@@ -54,49 +259,148 @@ func GenerateKeystore(commonName string, password []byte) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
-func IsValidKeyStoreSecret(secret corev1.Secret, keystoreCN string, keyStorePassword []byte) (bool, error) {
-	if secret.Data[constants.KeystoreName] != nil {
-		return IsValidKeyStore(keystoreCN, keyStorePassword, secret.Data[constants.KeystoreName])
+// loadCertificateChain returns the certificate chain of the private key entry of a keystore of the format, leaf first
+func loadCertificateChain(keyStorePassword, keyStoreData []byte, format string) ([][]byte, error) {
+	var chain [][]byte
+	if format == constants.PKCS12KeystoreFormat {
+		bags, err := decodePKCS12(keyStoreData, keyStorePassword)
+		if err != nil {
+			return nil, err
+		}
+		privateKey := false
+		for _, bag := range bags {
+			switch {
+			case bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+				derPK, err := decryptShroudedKeyBag(bag, keyStorePassword)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := x509.ParsePKCS8PrivateKey(derPK); err != nil {
+					return nil, err
+				}
+				privateKey = true
+			case bag.ID.Equal(oidCertBag):
+				certificate, err := decodeCertBag(bag)
+				if err != nil {
+					return nil, err
+				}
+				chain = append(chain, certificate)
+			}
+		}
+		if !privateKey {
+			return nil, fmt.Errorf("no private key found in the keystore")
+		}
+		return chain, nil
+	}
+	keyStore := keystore.New(keystore.WithOrderedAliases())
+	if err := keyStore.Load(bytes.NewReader(keyStoreData), keyStorePassword); err != nil {
+		return nil, err
+	}
+	pke, err := keyStore.GetPrivateKeyEntry(constants.KeystoreAlias, keyStorePassword)
+	if err != nil {
+		return nil, err
+	}
+	for _, certEntry := range pke.CertificateChain {
+		chain = append(chain, certEntry.Content)
+	}
+	return chain, nil
+}
+
+func IsValidKeyStoreSecret(secret corev1.Secret, keystoreCN string, dnsNames []string, keyStorePassword []byte, ca *CertificateAuthority, opts KeystoreOptions) (bool, error) {
+	if keyStoreData := secret.Data[KeystoreName(opts.Format)]; keyStoreData != nil {
+		return IsValidKeyStore(keystoreCN, dnsNames, keyStorePassword, keyStoreData, ca, opts)
 	}
 	return false, nil
 }
 
-func IsValidKeyStore(keystoreCN string, keyStorePassword, keyStoreData []byte) (bool, error) {
-	keyStore := keystore.New(keystore.WithOrderedAliases())
-	// FIX err == nil or something else!
-	if err := keyStore.Load(bytes.NewReader(keyStoreData), keyStorePassword); err != nil {
+// IsValidKeyStore returns true when the keystore has the format of the options, and its certificate has the common name
+// and DNS names, a key of the algorithm and size of the options, and was signed by the CA
+func IsValidKeyStore(keystoreCN string, dnsNames []string, keyStorePassword, keyStoreData []byte, ca *CertificateAuthority, opts KeystoreOptions) (bool, error) {
+	chain, err := loadCertificateChain(keyStorePassword, keyStoreData, opts.Format)
+	if err != nil {
 		return false, err
 	}
-	if ok := keyStore.IsPrivateKeyEntry(constants.KeystoreAlias); !ok {
+	if len(chain) == 0 {
 		return false, nil
 	}
-	pke, err := keyStore.GetPrivateKeyEntry(constants.KeystoreAlias, keyStorePassword)
+	cert, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return false, err
+	}
+	if cert.Subject.CommonName != keystoreCN || cert.CheckSignatureFrom(ca.Certificate) != nil || !hasKeyAlgorithm(cert, opts) {
+		return false, nil
+	}
+	for _, dnsName := range dnsNames {
+		if _, found := Find(cert.DNSNames, dnsName); !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// hasKeyAlgorithm tells whether the public key of the certificate has the algorithm and size of the options
+func hasKeyAlgorithm(cert *x509.Certificate, opts KeystoreOptions) bool {
+	algorithm, size := opts.GetKeyAlgorithm()
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return algorithm == constants.RSAKeyAlgorithm && key.N.BitLen() == size
+	case *ecdsa.PublicKey:
+		return algorithm == constants.ECDSAKeyAlgorithm && key.Curve.Params().BitSize == size
+	}
+	return false
+}
+
+// IsConvertedKeyStore tells whether the keystore of the format holds the leaf certificate of the PEM encoded chain, i.e.
+// whether it was converted from the current version of the chain by ConvertToKeystore
+func IsConvertedKeyStore(certPEM, keyStorePassword, keyStoreData []byte, format string) (bool, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false, fmt.Errorf("no PEM encoded certificate found")
+	}
+	chain, err := loadCertificateChain(keyStorePassword, keyStoreData, format)
 	if err != nil {
 		return false, err
 	}
-	return commonNameExists(keystoreCN, pke.CertificateChain)
+	return len(chain) > 0 && bytes.Equal(chain[0], block.Bytes), nil
 }
 
-func commonNameExists(keystoreCN string, certChain []keystore.Certificate) (bool, error) {
-	for _, certEntry := range certChain {
-		cert, err := x509.ParseCertificate(certEntry.Content)
+// GetKeyStoreExpiry returns the earliest expiry of the certificate chain held by a keystore of the format
+func GetKeyStoreExpiry(keyStorePassword, keyStoreData []byte, format string) (time.Time, error) {
+	chain, err := loadCertificateChain(keyStorePassword, keyStoreData, format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var expiry time.Time
+	for _, certificate := range chain {
+		cert, err := x509.ParseCertificate(certificate)
 		if err != nil {
-			return false, err
+			return time.Time{}, err
 		}
-		if cert.Subject.CommonName == keystoreCN {
-			return true, nil
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
 		}
 	}
-	return false, nil
+	return expiry, nil
 }
 
-// GenerateTruststore returns a Java Truststore with a Trusted CA bundle
-func GenerateTruststore(caBundle []byte) ([]byte, error) {
+// GenerateTruststore returns a truststore of the format with a Trusted CA bundle
+func GenerateTruststore(caBundle []byte, format string) ([]byte, error) {
 	var b bytes.Buffer
 	trustStore, err := createTruststoreObject(caBundle)
 	if err != nil {
 		return []byte{}, err
 	}
+	if format == constants.PKCS12KeystoreFormat {
+		var certificates []trustedCertificate
+		for _, alias := range trustStore.Aliases() {
+			certEntry, err := trustStore.GetTrustedCertificateEntry(alias)
+			if err != nil {
+				return []byte{}, err
+			}
+			certificates = append(certificates, trustedCertificate{alias: alias, certificate: certEntry.Certificate.Content})
+		}
+		return encodePKCS12Truststore(certificates, []byte(constants.TruststorePwd))
+	}
 	if err := trustStore.Store(&b, []byte(constants.TruststorePwd)); err != nil {
 		return []byte{}, err
 	}
@@ -140,83 +444,100 @@ func appendCertsFromPEM(pemCerts []byte, s *keystore.KeyStore) (ok bool, err err
 	return true, nil
 }
 
-func IsValidTruststoreSecret(secret corev1.Secret, caBundle []byte) (bool, error) {
-	if secret.Data[constants.TruststoreName] != nil {
-		return IsValidTruststore(caBundle, secret.Data[constants.TruststoreName])
+func IsValidTruststoreSecret(secret corev1.Secret, caBundle []byte, format string) (bool, error) {
+	if trustStoreData := secret.Data[TruststoreName(format)]; trustStoreData != nil {
+		return IsValidTruststore(caBundle, trustStoreData, format)
 	}
 	return false, nil
 }
 
-func IsValidTruststore(caBundle, keyStoreData []byte) (bool, error) {
-	existingtTrustStore := keystore.New(keystore.WithOrderedAliases())
-	if err := existingtTrustStore.Load(bytes.NewReader(keyStoreData), []byte(constants.TruststorePwd)); err != nil {
+func IsValidTruststore(caBundle, keyStoreData []byte, format string) (bool, error) {
+	existingCertificates, err := loadTrustedCertificates(keyStoreData, format)
+	if err != nil {
 		return false, err
 	}
 	trustStore, err := createTruststoreObject(caBundle)
 	if err != nil {
 		return false, err
 	}
-	existingtTrustAliases := existingtTrustStore.Aliases()
 	trustAliases := trustStore.Aliases()
-	if len(trustAliases) != len(existingtTrustAliases) {
+	if len(trustAliases) != len(existingCertificates) {
 		return false, nil
 	}
-	for _, alias := range existingtTrustAliases {
-		existingCertEntry, err := existingtTrustStore.GetTrustedCertificateEntry(alias)
-		if err != nil {
-			return false, err
-		}
+	for _, alias := range trustAliases {
 		trustCertEntry, err := trustStore.GetTrustedCertificateEntry(alias)
 		if err != nil {
 			return false, err
 		}
-		if !reflect.DeepEqual(existingCertEntry.Certificate, trustCertEntry.Certificate) {
+		if !bytes.Equal(existingCertificates[alias], trustCertEntry.Certificate.Content) {
 			return false, nil
 		}
 	}
 	return true, nil
 }
 
-// ????????????????
-// any way to use openshift's CA for signing instead ??
-func genCert(commonName string) (cert []byte, derPK []byte, err error) {
-	sAndI := pkix.Name{
-		CommonName: commonName,
-		//OrganizationalUnit: []string{"Engineering"},
-		//Organization:       []string{"RedHat"},
-		//Locality:           []string{"Raleigh"},
-		//Province:           []string{"NC"},
-		//Country:            []string{"US"},
+// loadTrustedCertificates returns the certificates of a truststore of the format by alias
+func loadTrustedCertificates(keyStoreData []byte, format string) (map[string][]byte, error) {
+	certificates := map[string][]byte{}
+	if format == constants.PKCS12KeystoreFormat {
+		bags, err := decodePKCS12(keyStoreData, []byte(constants.TruststorePwd))
+		if err != nil {
+			return nil, err
+		}
+		for _, bag := range bags {
+			if !bag.ID.Equal(oidCertBag) {
+				continue
+			}
+			alias, err := friendlyName(bag)
+			if err != nil {
+				return nil, err
+			}
+			if certificates[alias], err = decodeCertBag(bag); err != nil {
+				return nil, err
+			}
+		}
+		return certificates, nil
+	}
+	trustStore := keystore.New(keystore.WithOrderedAliases())
+	if err := trustStore.Load(bytes.NewReader(keyStoreData), []byte(constants.TruststorePwd)); err != nil {
+		return nil, err
 	}
+	for _, alias := range trustStore.Aliases() {
+		certEntry, err := trustStore.GetTrustedCertificateEntry(alias)
+		if err != nil {
+			return nil, err
+		}
+		certificates[alias] = certEntry.Certificate.Content
+	}
+	return certificates, nil
+}
 
-	serialNumber, err := crand.Int(crand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+// genCert returns a server and client certificate for the common name and DNS names, signed by the CA, along with its
+// private key of the algorithm and size of the options
+func genCert(commonName string, dnsNames []string, ca *CertificateAuthority, opts KeystoreOptions) (cert []byte, derPK []byte, err error) {
+	serialNumber, err := genSerialNumber()
 	if err != nil {
-		log.Error("Error getting serial number. ", err)
 		return nil, nil, err
 	}
 
-	ca := &x509.Certificate{
-		Subject:            sAndI,
-		Issuer:             sAndI,
-		SignatureAlgorithm: x509.SHA256WithRSA,
-		PublicKeyAlgorithm: x509.ECDSA,
-		NotBefore:          time.Now(),
-		NotAfter:           time.Now().AddDate(10, 0, 0),
-		SerialNumber:       serialNumber,
-		SubjectKeyId:       sha256.New().Sum(nil),
-		IsCA:               true,
-		// BasicConstraintsValid: true,
-		// ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		// KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(0, 0, constants.KeystoreValidity),
+		SerialNumber:          serialNumber,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 	}
 
-	priv, err := rsa.GenerateKey(crand.Reader, 2048)
+	priv, err := genKey(opts)
 	if err != nil {
 		log.Error("create key failed. ", err)
 		return nil, nil, err
 	}
 
-	cert, err = x509.CreateCertificate(crand.Reader, ca, ca, &priv.PublicKey, priv)
+	cert, err = x509.CreateCertificate(crand.Reader, template, ca.Certificate, priv.Public(), ca.PrivateKey)
 	if err != nil {
 		log.Error("create cert failed. ", err)
 		return nil, nil, err
@@ -231,6 +552,28 @@ func genCert(commonName string) (cert []byte, derPK []byte, err error) {
 	return cert, derPK, nil
 }
 
+func genKey(opts KeystoreOptions) (crypto.Signer, error) {
+	algorithm, size := opts.GetKeyAlgorithm()
+	if algorithm == constants.ECDSAKeyAlgorithm {
+		switch size {
+		case 256:
+			return ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+		case 384:
+			return ecdsa.GenerateKey(elliptic.P384(), crand.Reader)
+		}
+		return nil, fmt.Errorf("unsupported ECDSA key size %d", size)
+	}
+	return rsa.GenerateKey(crand.Reader, size)
+}
+
+func genSerialNumber() (*big.Int, error) {
+	serialNumber, err := crand.Int(crand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		log.Error("Error getting serial number. ", err)
+	}
+	return serialNumber, err
+}
+
 // GeneratePassword returns an alphanumeric password of the length provided
 func GeneratePassword(length int) []byte {
 	rand.Seed(time.Now().UnixNano())
@@ -317,8 +660,11 @@ func Find(slice []string, val string) (int, bool) {
 // ValidateRouteHostname validates the hostname provided by the user
 // see: https://github.com/openshift/router/blob/release-4.6/pkg/router/controller/unique_host.go#L231
 func ValidateRouteHostname(r string) field.ErrorList {
-	specPath := field.NewPath("spec")
-	hostPath := specPath.Child("host")
+	return ValidateHostname(field.NewPath("spec").Child("host"), r)
+}
+
+// ValidateHostname validates a route hostname set in the given field
+func ValidateHostname(hostPath *field.Path, r string) field.ErrorList {
 	result := field.ErrorList{}
 	if len(r) < 1 {
 		log.Debugf("%s is empty, no custom hostname will be configured", hostPath)
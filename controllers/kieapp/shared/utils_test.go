@@ -2,8 +2,15 @@ package shared
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/pavel-v-chernykh/keystore-go/v4"
 	"github.com/stretchr/testify/assert"
@@ -62,12 +69,196 @@ func TestGenerateKeystore(t *testing.T) {
 	password := GeneratePassword(8)
 	assert.Len(t, password, 8)
 
+	ca, err := GenerateCA("test-ca", KeystoreOptions{})
+	assert.Nil(t, err)
+	commonName := "test-https"
+	dnsNames := []string{commonName, "test-svc.test-ns.svc"}
+	keyBytes, err := GenerateKeystore(commonName, dnsNames, password, ca, KeystoreOptions{})
+	assert.Nil(t, err)
+	ok, err := IsValidKeyStore(commonName, dnsNames, password, keyBytes, ca, KeystoreOptions{})
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	expiry, err := GetKeyStoreExpiry(password, keyBytes, constants.JKSKeystoreFormat)
+	assert.Nil(t, err)
+	assert.True(t, expiry.After(time.Now()))
+
+	keyStore := keystore.New(keystore.WithOrderedAliases())
+	assert.Nil(t, keyStore.Load(bytes.NewReader(keyBytes), password))
+	pke, err := keyStore.GetPrivateKeyEntry(constants.KeystoreAlias, password)
+	assert.Nil(t, err)
+	assert.Len(t, pke.CertificateChain, 2)
+	cert, err := x509.ParseCertificate(pke.CertificateChain[0].Content)
+	assert.Nil(t, err)
+	assert.Equal(t, dnsNames, cert.DNSNames)
+	assert.Contains(t, cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	assert.False(t, cert.IsCA)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "test-svc.test-ns.svc", Roots: roots})
+	assert.Nil(t, err, "The certificate should be trusted for the service name")
+
+	ok, err = IsValidKeyStore(commonName, append(dnsNames, "other-svc"), password, keyBytes, ca, KeystoreOptions{})
+	assert.False(t, ok, "A keystore missing a DNS name should be regenerated")
+	assert.Nil(t, err)
+	otherCA, err := GenerateCA("other-ca", KeystoreOptions{})
+	assert.Nil(t, err)
+	ok, err = IsValidKeyStore(commonName, dnsNames, password, keyBytes, otherCA, KeystoreOptions{})
+	assert.False(t, ok, "A keystore signed by another CA should be regenerated")
+	assert.Nil(t, err)
+}
+
+func TestConvertToKeystore(t *testing.T) {
+	password := GeneratePassword(8)
+	ca, err := GenerateCA("test-ca", KeystoreOptions{})
+	assert.Nil(t, err)
+	certPEM, keyPEM := issueCertificate(t, ca, "test-svc.test-ns.svc")
+	keyBytes, err := ConvertToKeystore(append(certPEM, ca.CertificatePEM()...), keyPEM, password, constants.JKSKeystoreFormat)
+	assert.Nil(t, err)
+
+	keyStore := keystore.New(keystore.WithOrderedAliases())
+	assert.Nil(t, keyStore.Load(bytes.NewReader(keyBytes), password))
+	pke, err := keyStore.GetPrivateKeyEntry(constants.KeystoreAlias, password)
+	assert.Nil(t, err)
+	assert.Len(t, pke.CertificateChain, 2)
+	assert.Equal(t, ca.Certificate.Raw, pke.CertificateChain[1].Content)
+	_, err = x509.ParsePKCS8PrivateKey(pke.PrivateKey)
+	assert.Nil(t, err, "The SEC 1 key should be stored as PKCS #8")
+
+	ok, err := IsConvertedKeyStore(certPEM, password, keyBytes, constants.JKSKeystoreFormat)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	renewedPEM, _ := issueCertificate(t, ca, "test-svc.test-ns.svc")
+	ok, err = IsConvertedKeyStore(renewedPEM, password, keyBytes, constants.JKSKeystoreFormat)
+	assert.False(t, ok, "A keystore converted from a previous certificate should be converted again")
+	assert.Nil(t, err)
+
+	_, err = ConvertToKeystore(nil, keyPEM, password, constants.JKSKeystoreFormat)
+	assert.Error(t, err)
+}
+
+func TestGeneratePKCS12Keystore(t *testing.T) {
+	password := GeneratePassword(8)
+	ca, err := GenerateCA("test-ca", KeystoreOptions{})
+	assert.Nil(t, err)
 	commonName := "test-https"
-	keyBytes, err := GenerateKeystore(commonName, password)
+	dnsNames := []string{commonName, "test-svc.test-ns.svc"}
+	opts := KeystoreOptions{Format: constants.PKCS12KeystoreFormat, KeyAlgorithm: constants.ECDSAKeyAlgorithm}
+	keyBytes, err := GenerateKeystore(commonName, dnsNames, password, ca, opts)
+	assert.Nil(t, err)
+	ok, err := IsValidKeyStore(commonName, dnsNames, password, keyBytes, ca, opts)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	expiry, err := GetKeyStoreExpiry(password, keyBytes, opts.Format)
+	assert.Nil(t, err)
+	assert.True(t, expiry.After(time.Now()))
+
+	bags, err := decodePKCS12(keyBytes, password)
+	assert.Nil(t, err)
+	assert.Len(t, bags, 3)
+	assert.True(t, bags[0].ID.Equal(oidCertBag))
+	alias, err := friendlyName(bags[0])
+	assert.Nil(t, err)
+	assert.Equal(t, constants.KeystoreAlias, alias)
+	certificate, err := decodeCertBag(bags[1])
+	assert.Nil(t, err)
+	assert.Equal(t, ca.Certificate.Raw, certificate)
+	assert.True(t, bags[2].ID.Equal(oidPKCS8ShroudedKeyBag))
+	assert.Equal(t, bags[0].Attributes, bags[2].Attributes, "The key should be paired with the leaf certificate")
+	derPK, err := decryptShroudedKeyBag(bags[2], password)
+	assert.Nil(t, err)
+	key, err := x509.ParsePKCS8PrivateKey(derPK)
+	assert.Nil(t, err)
+	assert.Equal(t, elliptic.P256(), key.(*ecdsa.PrivateKey).Curve)
+	_, err = decodePKCS12(keyBytes, append(password, 'x'))
+	assert.Error(t, err, "The MAC should be checked with the password")
+
+	ok, _ = IsValidKeyStore(commonName, dnsNames, password, keyBytes, ca, KeystoreOptions{Format: opts.Format, KeyAlgorithm: constants.RSAKeyAlgorithm, KeySize: 3072})
+	assert.False(t, ok, "A keystore with a key of another algorithm should be regenerated")
+	ok, _ = IsValidKeyStore(commonName, dnsNames, password, keyBytes, ca, KeystoreOptions{KeyAlgorithm: constants.ECDSAKeyAlgorithm})
+	assert.False(t, ok, "A keystore of another format should be regenerated")
+	ok, err = IsValidKeyStoreSecret(corev1.Secret{Data: map[string][]byte{constants.KeystoreName: keyBytes}}, commonName, dnsNames, password, ca, opts)
+	assert.False(t, ok, "A JKS keystore secret should be migrated")
+	assert.Nil(t, err)
+
+	rsaOpts := KeystoreOptions{Format: constants.PKCS12KeystoreFormat, KeySize: 3072}
+	keyBytes, err = GenerateKeystore(commonName, dnsNames, password, ca, rsaOpts)
 	assert.Nil(t, err)
-	ok, err := IsValidKeyStore(commonName, password, keyBytes)
+	ok, err = IsValidKeyStore(commonName, dnsNames, password, keyBytes, ca, rsaOpts)
 	assert.True(t, ok)
 	assert.Nil(t, err)
+	_, err = GenerateKeystore(commonName, dnsNames, password, ca, KeystoreOptions{KeyAlgorithm: constants.ECDSAKeyAlgorithm, KeySize: 521})
+	assert.Error(t, err)
+}
+
+func TestConvertToPKCS12Keystore(t *testing.T) {
+	password := GeneratePassword(8)
+	ca, err := GenerateCA("test-ca", KeystoreOptions{})
+	assert.Nil(t, err)
+	certPEM, keyPEM := issueCertificate(t, ca, "test-svc.test-ns.svc")
+	keyBytes, err := ConvertToKeystore(append(certPEM, ca.CertificatePEM()...), keyPEM, password, constants.PKCS12KeystoreFormat)
+	assert.Nil(t, err)
+	ok, err := IsConvertedKeyStore(certPEM, password, keyBytes, constants.PKCS12KeystoreFormat)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	ok, _ = IsConvertedKeyStore(certPEM, password, keyBytes, constants.JKSKeystoreFormat)
+	assert.False(t, ok, "A keystore of another format should be converted again")
+}
+
+// issueCertificate returns a PEM encoded certificate and ECDSA key signed by the CA, as issued by cert-manager
+func issueCertificate(t *testing.T, ca *CertificateAuthority, dnsName string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	assert.Nil(t, err)
+	serialNumber, err := genSerialNumber()
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(0, 0, 90),
+	}
+	cert, err := x509.CreateCertificate(crand.Reader, template, ca.Certificate, &key.PublicKey, ca.PrivateKey)
+	assert.Nil(t, err)
+	derKey, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: derKey})
+}
+
+func TestParseCA(t *testing.T) {
+	ca, err := GenerateCA("test-ca", KeystoreOptions{})
+	assert.Nil(t, err)
+	assert.True(t, ca.Certificate.IsCA)
+	assert.NotZero(t, ca.Certificate.KeyUsage&x509.KeyUsageCertSign)
+
+	parsed, err := ParseCA(ca.CertificatePEM(), ca.PrivateKeyPEM())
+	assert.Nil(t, err)
+	assert.Equal(t, ca.Certificate.Raw, parsed.Certificate.Raw)
+	assert.True(t, publicKeyEqual(ca.PrivateKey.Public(), parsed.PrivateKey.Public()))
+	assert.True(t, parsed.HasKeyAlgorithm(KeystoreOptions{}))
+	legacyKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(ca.PrivateKey.(*rsa.PrivateKey))})
+	_, err = ParseCA(ca.CertificatePEM(), legacyKeyPEM)
+	assert.Nil(t, err, "The PKCS #1 key of the former CAs should be parsed")
+
+	ecdsaOpts := KeystoreOptions{KeyAlgorithm: constants.ECDSAKeyAlgorithm, KeySize: 384}
+	ecdsaCA, err := GenerateCA("test-ca", ecdsaOpts)
+	assert.Nil(t, err)
+	parsed, err = ParseCA(ecdsaCA.CertificatePEM(), ecdsaCA.PrivateKeyPEM())
+	assert.Nil(t, err)
+	assert.True(t, parsed.HasKeyAlgorithm(ecdsaOpts))
+	assert.False(t, parsed.HasKeyAlgorithm(KeystoreOptions{}), "A CA with a key of another algorithm should be regenerated")
+	keyBytes, err := GenerateKeystore("test-https", nil, []byte("changeit"), parsed, KeystoreOptions{})
+	assert.Nil(t, err)
+	ok, err := IsValidKeyStore("test-https", nil, []byte("changeit"), keyBytes, parsed, KeystoreOptions{})
+	assert.True(t, ok, "An ECDSA CA should sign RSA keystores")
+	assert.Nil(t, err)
+
+	otherCA, err := GenerateCA("other-ca", KeystoreOptions{})
+	assert.Nil(t, err)
+	_, err = ParseCA(ca.CertificatePEM(), otherCA.PrivateKeyPEM())
+	assert.Error(t, err)
+	_, err = ParseCA(nil, nil)
+	assert.Error(t, err)
 }
 
 func TestGenerateTruststore(t *testing.T) {
@@ -80,7 +271,7 @@ func TestGenerateTruststore(t *testing.T) {
 	certChainLen := 129
 	assert.Len(t, trust1.Aliases(), certChainLen)
 
-	trustBytes, err := GenerateTruststore(caBundle)
+	trustBytes, err := GenerateTruststore(caBundle, constants.JKSKeystoreFormat)
 	assert.Nil(t, err)
 
 	existingtTrustStore := keystore.New(keystore.WithOrderedAliases())
@@ -88,11 +279,37 @@ func TestGenerateTruststore(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, existingtTrustStore.Aliases(), certChainLen)
 
-	ok, err := IsValidTruststore(caBundle, trustBytes)
+	ok, err := IsValidTruststore(caBundle, trustBytes, constants.JKSKeystoreFormat)
 	assert.True(t, ok)
 	assert.Nil(t, err)
 }
 
+func TestGeneratePKCS12Truststore(t *testing.T) {
+	caBundle, err := ioutil.ReadFile("test-" + constants.CaBundleKey)
+	assert.Nil(t, err)
+	trustBytes, err := GenerateTruststore(caBundle, constants.PKCS12KeystoreFormat)
+	assert.Nil(t, err)
+
+	bags, err := decodePKCS12(trustBytes, []byte(constants.TruststorePwd))
+	assert.Nil(t, err)
+	assert.Len(t, bags, certChainLen)
+	trustStore, err := createTruststoreObject(caBundle)
+	assert.Nil(t, err)
+	alias, err := friendlyName(bags[0])
+	assert.Nil(t, err)
+	assert.Equal(t, trustStore.Aliases()[0], alias)
+
+	ok, err := IsValidTruststore(caBundle, trustBytes, constants.PKCS12KeystoreFormat)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	ok, err = IsValidTruststore(caBundle[:len(caBundle)/2], trustBytes, constants.PKCS12KeystoreFormat)
+	assert.False(t, ok)
+	assert.Nil(t, err)
+	ok, err = IsValidTruststoreSecret(corev1.Secret{Data: map[string][]byte{constants.TruststoreName: trustBytes}}, caBundle, constants.PKCS12KeystoreFormat)
+	assert.False(t, ok, "A JKS truststore secret should be migrated")
+	assert.Nil(t, err)
+}
+
 func TestTruststoreInvalid(t *testing.T) {
 	caBundle, err := ioutil.ReadFile("test-" + constants.CaBundleKey)
 	assert.Nil(t, err)
@@ -103,15 +320,15 @@ func TestTruststoreInvalid(t *testing.T) {
 	assert.Len(t, trust1.Aliases(), certChainLen)
 
 	emptyCa := []byte{}
-	trustBytes1, err := GenerateTruststore(caBundle)
+	trustBytes1, err := GenerateTruststore(caBundle, constants.JKSKeystoreFormat)
 	assert.Nil(t, err)
-	ok, err := IsValidTruststore(emptyCa, trustBytes1)
+	ok, err := IsValidTruststore(emptyCa, trustBytes1, constants.JKSKeystoreFormat)
 	assert.False(t, ok)
 	assert.Nil(t, err)
 
-	trustBytes2, err := GenerateTruststore(emptyCa)
+	trustBytes2, err := GenerateTruststore(emptyCa, constants.JKSKeystoreFormat)
 	assert.Nil(t, err)
-	ok, err = IsValidTruststore(caBundle, trustBytes2)
+	ok, err = IsValidTruststore(caBundle, trustBytes2, constants.JKSKeystoreFormat)
 	assert.False(t, ok)
 	assert.Nil(t, err)
 }
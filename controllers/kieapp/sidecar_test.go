@@ -0,0 +1,53 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileSidecars(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{KieAppObject: api.KieAppObject{
+					Sidecars:     []corev1.Container{{Name: "log-shipper", Image: "quay.io/example/fluent-bit:1.9"}},
+					Volumes:      []corev1.Volume{{Name: "logs", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/opt/eap/standalone/log"}},
+				}}},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service, Recorder: recorder}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}, deployment))
+	containers := deployment.Spec.Template.Spec.Containers
+	assert.Len(t, containers, 2)
+	assert.Equal(t, "log-shipper", containers[1].Name)
+	assert.Contains(t, containers[0].VolumeMounts, corev1.VolumeMount{Name: "logs", MountPath: "/opt/eap/standalone/log"})
+
+	getEvents(recorder)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getEvents(recorder), "The sidecar should not be treated as drift")
+}
@@ -40,6 +40,34 @@ func SetDeployed(cr *api.KieApp) bool {
 	return true
 }
 
+// SetPaused - Sets the condition type to Paused if not yet set, returns true when it changed
+func SetPaused(cr *api.KieApp) bool {
+	return setCondition(cr, api.PausedConditionType)
+}
+
+// SetSuspended - Sets the condition type to Suspended once the workloads are scaled to zero, returns true when it changed
+func SetSuspended(cr *api.KieApp) bool {
+	return setCondition(cr, api.SuspendedConditionType)
+}
+
+// SetPendingApproval - Sets the condition type to PendingApproval if not yet set, returns true when it changed
+func SetPendingApproval(cr *api.KieApp) bool {
+	return setCondition(cr, api.PendingApprovalConditionType)
+}
+
+func setCondition(cr *api.KieApp, conditionType api.ConditionType) bool {
+	log := log.With("kind", cr.Kind, "name", cr.Name, "namespace", cr.Namespace)
+	size := len(cr.Status.Conditions)
+	if size > 0 && cr.Status.Conditions[size-1].Type == conditionType &&
+		cr.Status.Conditions[size-1].Version == cr.Status.Applied.Version {
+		log.Debugf("Status: unchanged status [%s].", conditionType)
+		return false
+	}
+	log.Debugf("Status: set %s", conditionType)
+	cr.Status.Conditions = addCondition(cr, api.Condition{Type: conditionType})
+	return true
+}
+
 // SetFailed - Sets the failed condition with the error reason and message
 func SetFailed(cr *api.KieApp, reason api.ReasonType, err error) {
 	log := log.With("kind", cr.Kind, "name", cr.Name, "namespace", cr.Namespace)
@@ -52,6 +80,20 @@ func SetFailed(cr *api.KieApp, reason api.ReasonType, err error) {
 	cr.Status.Conditions = addCondition(cr, condition)
 }
 
+// SetDeleting - Sets the deleting condition with the cleanup step in progress, returns true when it was not yet set
+func SetDeleting(cr *api.KieApp, message string) bool {
+	log := log.With("kind", cr.Kind, "name", cr.Name, "namespace", cr.Namespace)
+	size := len(cr.Status.Conditions)
+	if size > 0 && cr.Status.Conditions[size-1].Type == api.DeletingConditionType &&
+		cr.Status.Conditions[size-1].Message == message {
+		log.Debug("Status: unchanged status [deleting].")
+		return false
+	}
+	log.Debugf("Status: set deleting, %s", message)
+	cr.Status.Conditions = addCondition(cr, api.Condition{Type: api.DeletingConditionType, Message: message})
+	return true
+}
+
 func addCondition(cr *api.KieApp, condition api.Condition) []api.Condition {
 	condition.Status = corev1.ConditionTrue
 	condition.LastTransitionTime = metav1.Now()
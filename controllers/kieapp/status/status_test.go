@@ -63,6 +63,31 @@ func TestSetProvisioningSkipUpdate(t *testing.T) {
 	assert.Equal(t, condition.Type, cr.Status.Phase)
 }
 
+func TestSetDeleting(t *testing.T) {
+	cr := &api.KieApp{}
+	SetDeployed(cr)
+	assert.True(t, SetDeleting(cr, "Removing ImageStreamTags"))
+	assert.False(t, SetDeleting(cr, "Removing ImageStreamTags"))
+	assert.True(t, SetDeleting(cr, "Removing owned resources"))
+
+	assert.Equal(t, 3, len(cr.Status.Conditions))
+	assert.Equal(t, api.DeletingConditionType, cr.Status.Phase)
+	assert.Equal(t, "Removing owned resources", cr.Status.Conditions[2].Message)
+}
+
+func TestSetPausedAndSuspended(t *testing.T) {
+	cr := &api.KieApp{Status: api.KieAppStatus{Applied: api.KieAppSpec{Version: constants.CurrentVersion}}}
+	assert.True(t, SetPaused(cr))
+	assert.False(t, SetPaused(cr))
+	assert.Equal(t, api.PausedConditionType, cr.Status.Phase)
+
+	assert.True(t, SetSuspended(cr))
+	assert.False(t, SetSuspended(cr))
+	assert.Equal(t, 2, len(cr.Status.Conditions))
+	assert.Equal(t, api.SuspendedConditionType, cr.Status.Phase)
+	assert.NotEqual(t, constants.CurrentVersion, cr.Status.Version)
+}
+
 func TestSetProvisioningAndThenDeployed(t *testing.T) {
 	now := metav1.Now()
 	cr := &api.KieApp{Status: api.KieAppStatus{Applied: api.KieAppSpec{Version: constants.PriorVersion}}}
@@ -0,0 +1,91 @@
+package kieapp
+
+import (
+	"context"
+	"reflect"
+
+	oappsv1 "github.com/openshift/api/apps/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/status"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcilePaused refreshes the status of a paused KieApp, leaving its resources untouched
+func (reconciler *KieAppReconciler) reconcilePaused(ctx context.Context, instance *api.KieApp) (reconcile.Result, error) {
+	cachedInstance := instance.DeepCopy()
+	deployed, err := reconciler.getDeployedResources(instance)
+	if err != nil {
+		reconciler.setFailedStatus(instance, api.UnknownReason, err)
+		return reconcile.Result{}, err
+	}
+	setDeploymentStatus(instance, deployed)
+	status.SetPaused(instance)
+	if reconciler.hasStatusChanges(instance, cachedInstance) {
+		if err := reconciler.writeStatus(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// setSuspendedReplicas scales the requested workloads to zero while the KieApp is suspended, recording the replicas
+// they were running with in its status. Resumed workloads are scaled back to the recorded replicas, which are cleared
+// once applied, so autoscaled workloads resume where they were. The others then go back to the replicas of the spec.
+func setSuspendedReplicas(instance *api.KieApp, requestedResources []client.Object, deployed map[reflect.Type][]client.Object) {
+	if !instance.Spec.Suspended {
+		for _, object := range requestedResources {
+			if count, ok := instance.Status.SuspendedReplicas[object.GetName()]; ok {
+				setReplicas(object, count)
+			}
+		}
+		return
+	}
+	if instance.Status.SuspendedReplicas == nil {
+		replicas := map[string]int32{}
+		for _, objects := range deployed {
+			for _, object := range objects {
+				if count, ok := getReplicas(object); ok && count > 0 {
+					replicas[object.GetName()] = count
+				}
+			}
+		}
+		if len(replicas) > 0 {
+			instance.Status.SuspendedReplicas = replicas
+		}
+	}
+	for _, object := range requestedResources {
+		setReplicas(object, 0)
+	}
+}
+
+// getReplicas returns the replicas of a DeploymentConfig, Deployment or StatefulSet, false for any other resource
+func getReplicas(object client.Object) (int32, bool) {
+	switch workload := object.(type) {
+	case *oappsv1.DeploymentConfig:
+		return workload.Spec.Replicas, true
+	case *appsv1.Deployment:
+		if workload.Spec.Replicas != nil {
+			return *workload.Spec.Replicas, true
+		}
+		return 1, true
+	case *appsv1.StatefulSet:
+		if workload.Spec.Replicas != nil {
+			return *workload.Spec.Replicas, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func setReplicas(object client.Object, replicas int32) {
+	switch workload := object.(type) {
+	case *oappsv1.DeploymentConfig:
+		workload.Spec.Replicas = replicas
+	case *appsv1.Deployment:
+		workload.Spec.Replicas = &replicas
+	case *appsv1.StatefulSet:
+		workload.Spec.Replicas = &replicas
+	}
+}
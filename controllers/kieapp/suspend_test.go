@@ -0,0 +1,121 @@
+package kieapp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/defaults"
+	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func getDeploymentReplicas(t *testing.T, service *test.MockPlatformService, namespace string) map[string]int32 {
+	deploymentList := &appsv1.DeploymentList{}
+	assert.Nil(t, service.List(context.TODO(), deploymentList, client.InNamespace(namespace)))
+	replicas := map[string]int32{}
+	for _, deployment := range deploymentList.Items {
+		replicas[deployment.Name] = *deployment.Spec.Replicas
+	}
+	return replicas
+}
+
+func TestSuspendAndResume(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Objects: api.KieAppObjects{
+				Servers: []api.KieServerSet{{Autoscaling: &api.KieServerAutoscaling{Enabled: true, MaxReplicas: 5}}},
+			},
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	// the autoscaler scales the kieserver up
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, service.Get(context.TODO(), types.NamespacedName{Name: "test-kieserver", Namespace: name.Namespace}, deployment))
+	deployment.Spec.Replicas = defaults.Pint32(3)
+	assert.Nil(t, service.Update(context.TODO(), deployment))
+	running := getDeploymentReplicas(t, service, name.Namespace)
+	assert.Equal(t, int32(3), running["test-kieserver"])
+
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	cr.Spec.Suspended = true
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	for deployment, replicas := range getDeploymentReplicas(t, service, name.Namespace) {
+		assert.Equal(t, int32(0), replicas, deployment)
+	}
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Equal(t, running, cr.Status.SuspendedReplicas)
+
+	cr.Spec.Suspended = false
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Equal(t, running, getDeploymentReplicas(t, service, name.Namespace), "The recorded replicas should be restored")
+	resumed := &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, resumed))
+	assert.Empty(t, resumed.Status.SuspendedReplicas)
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Equal(t, running, getDeploymentReplicas(t, service, name.Namespace), "The autoscaled kieserver should keep its replicas")
+
+	// once restored, the workloads no longer autoscaled go back to the replicas of the spec
+	assert.Nil(t, service.Get(context.TODO(), name, resumed))
+	resumed.Spec.Suspended = true
+	assert.Nil(t, service.Update(context.TODO(), resumed))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	resumed = &api.KieApp{}
+	assert.Nil(t, service.Get(context.TODO(), name, resumed))
+	resumed.Spec.Suspended = false
+	resumed.Spec.Objects.Servers = []api.KieServerSet{{KieAppObject: api.KieAppObject{Replicas: defaults.Pint32(2)}}}
+	assert.Nil(t, service.Update(context.TODO(), resumed))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), getDeploymentReplicas(t, service, name.Namespace)["test-kieserver"])
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), getDeploymentReplicas(t, service, name.Namespace)["test-kieserver"])
+}
+
+func TestPaused(t *testing.T) {
+	name := types.NamespacedName{Name: "test", Namespace: "test-ns"}
+	cr := &api.KieApp{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, UID: "test-uid"},
+		Spec: api.KieAppSpec{
+			Environment: api.RhpamTrial,
+			Platform:    api.KubernetesPlatform,
+			Exposure:    &api.KieAppExposure{Domain: "apps.example.com"},
+			Paused:      true,
+		},
+	}
+	service := test.MockService()
+	assert.Nil(t, service.Create(context.TODO(), cr))
+	reconciler := &KieAppReconciler{Client: service.Client, Service: service}
+	_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.Empty(t, getDeploymentReplicas(t, service, name.Namespace))
+	assert.Nil(t, service.Get(context.TODO(), name, cr))
+	assert.Equal(t, api.PausedConditionType, cr.Status.Phase)
+
+	cr.Spec.Paused = false
+	assert.Nil(t, service.Update(context.TODO(), cr))
+	_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: name})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, getDeploymentReplicas(t, service, name.Namespace))
+}
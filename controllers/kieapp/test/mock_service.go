@@ -11,13 +11,19 @@ import (
 	oimagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	imagev1 "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
 	api "github.com/spolti/kie-cloud-operator-new/api/v2"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientv1 "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
 var log = logs.GetLogger("kieapp.test")
@@ -47,6 +53,7 @@ var knownTypes = map[schema.GroupVersion][]runtime.Object{
 		&corev1.PersistentVolumeClaim{},
 		&corev1.ServiceAccount{},
 		&corev1.Secret{},
+		&corev1.SecretList{},
 		&corev1.Service{},
 		&corev1.ServiceList{},
 		&corev1.PersistentVolumeClaimList{},
@@ -61,11 +68,41 @@ var knownTypes = map[schema.GroupVersion][]runtime.Object{
 	appsv1.SchemeGroupVersion: {
 		&appsv1.StatefulSet{},
 		&appsv1.StatefulSetList{},
+		&appsv1.Deployment{},
+		&appsv1.DeploymentList{},
 	},
 	routev1.GroupVersion: {
 		&routev1.Route{},
 		&routev1.RouteList{},
 	},
+	networkingv1.SchemeGroupVersion: {
+		&networkingv1.Ingress{},
+		&networkingv1.IngressList{},
+		&networkingv1.NetworkPolicy{},
+		&networkingv1.NetworkPolicyList{},
+	},
+	autoscalingv2beta2.SchemeGroupVersion: {
+		&autoscalingv2beta2.HorizontalPodAutoscaler{},
+		&autoscalingv2beta2.HorizontalPodAutoscalerList{},
+	},
+	policyv1.SchemeGroupVersion: {
+		&policyv1.PodDisruptionBudget{},
+		&policyv1.PodDisruptionBudgetList{},
+	},
+	gatewayv1alpha1.SchemeGroupVersion: {
+		&gatewayv1alpha1.HTTPRoute{},
+		&gatewayv1alpha1.HTTPRouteList{},
+	},
+	certmanagerv1.GroupVersion: {
+		&certmanagerv1.Certificate{},
+		&certmanagerv1.CertificateList{},
+	},
+	monv1.SchemeGroupVersion: {
+		&monv1.ServiceMonitor{},
+		&monv1.ServiceMonitorList{},
+		&monv1.PrometheusRule{},
+		&monv1.PrometheusRuleList{},
+	},
 	oimagev1.GroupVersion: {
 		&oimagev1.ImageStream{},
 		&oimagev1.ImageStreamList{},
@@ -1,34 +1,43 @@
 package kieapp
 
 import (
+	"context"
+
 	oappsv1 "github.com/openshift/api/apps/v1"
 	buildv1 "github.com/openshift/api/build/v1"
 	oimagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
+	api "github.com/spolti/kie-cloud-operator-new/api/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
-// Add Creates a new controller and starts watching resources
-func Add(mgr manager.Manager, reconciler reconcile.Reconciler) error {
+// Add Creates a new controller and starts watching resources, those only served by OpenShift when the platform is
+func Add(mgr manager.Manager, reconciler reconcile.Reconciler, platform api.PlatformType) error {
 	// Create a new controller
 	c, err := controller.New("kieapp-controller", mgr, controller.Options{Reconciler: reconciler})
 	if err != nil {
 		return err
 	}
 
+	// OpenShift only kinds can't be watched on plain Kubernetes
+	isOpenShift := platform == api.OpenShiftPlatform
+
 	watchObjects := []client.Object{
-		// Watch for changes to primary resource KieApp
+		// Watch for changes to primary resource KieApp, the Deployments it owns are watched along with its other objects
 		&api.KieApp{},
-		&appsv1.Deployment{},
 	}
 	objectHandler := &handler.EnqueueRequestForObject{}
 	for _, watchObject := range watchObjects {
@@ -56,9 +65,11 @@ func Add(mgr manager.Manager, reconciler reconcile.Reconciler) error {
 		&rbacv1.RoleBinding{},
 		&rbacv1.Role{},
 		&corev1.Service{},
-		&routev1.Route{},
 		&corev1.ServiceAccount{},
 	}
+	if isOpenShift {
+		watchOwnedObjects = append(watchOwnedObjects, &routev1.Route{})
+	}
 	ownerHandler = &handler.EnqueueRequestForOwner{
 		OwnerType: &appsv1.Deployment{},
 	}
@@ -70,7 +81,7 @@ func Add(mgr manager.Manager, reconciler reconcile.Reconciler) error {
 	}
 
 	watchOwnedObjects = []client.Object{
-		&oappsv1.DeploymentConfig{},
+		&appsv1.Deployment{},
 		&appsv1.StatefulSet{},
 		&corev1.PersistentVolumeClaim{},
 		&rbacv1.RoleBinding{},
@@ -78,9 +89,22 @@ func Add(mgr manager.Manager, reconciler reconcile.Reconciler) error {
 		&corev1.ServiceAccount{},
 		&corev1.Secret{},
 		&corev1.Service{},
-		&routev1.Route{},
-		&buildv1.BuildConfig{},
-		&oimagev1.ImageStream{},
+		&networkingv1.Ingress{},
+	}
+	if hasHTTPRoutes(mgr.GetRESTMapper()) {
+		watchOwnedObjects = append(watchOwnedObjects, &gatewayv1alpha1.HTTPRoute{})
+	}
+	if hasCertificates(mgr.GetRESTMapper()) {
+		// cert-manager updates the status of the Certificates it renews
+		watchOwnedObjects = append(watchOwnedObjects, &certmanagerv1.Certificate{})
+	}
+	if isOpenShift {
+		watchOwnedObjects = append(watchOwnedObjects,
+			&oappsv1.DeploymentConfig{},
+			&routev1.Route{},
+			&buildv1.BuildConfig{},
+			&oimagev1.ImageStream{},
+		)
 	}
 	ownerHandler = &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -93,12 +117,25 @@ func Add(mgr manager.Manager, reconciler reconcile.Reconciler) error {
 		}
 	}
 
+	// The truststore is regenerated when the CA bundles it is made of change, whether they are owned or not
+	truststoreHandler := handler.EnqueueRequestsFromMapFunc(truststoreSourceRequests(mgr.GetClient()))
+	for _, watchObject := range []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+		err = c.Watch(&source.Kind{Type: watchObject}, truststoreHandler)
+		if err != nil {
+			return err
+		}
+	}
+
 	watchOwnedObjects = []client.Object{
 		&corev1.ConfigMap{},
 	}
+	// the kieserver ConfigMaps are owned by the DeploymentConfigs, or the Deployments replacing them on Kubernetes
 	ownerHandler = &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &oappsv1.DeploymentConfig{},
+		OwnerType:    &appsv1.Deployment{},
+	}
+	if isOpenShift {
+		ownerHandler.OwnerType = &oappsv1.DeploymentConfig{}
 	}
 	for _, watchObject := range watchOwnedObjects {
 		err = c.Watch(&source.Kind{Type: watchObject}, ownerHandler)
@@ -109,3 +146,43 @@ func Add(mgr manager.Manager, reconciler reconcile.Reconciler) error {
 
 	return nil
 }
+
+// truststoreSourceRequests maps a configmap or secret to the KieApps of its namespace whose truststore is generated from it
+func truststoreSourceRequests(c client.Client) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		kieApps := &api.KieAppList{}
+		if err := c.List(context.TODO(), kieApps, client.InNamespace(object.GetNamespace())); err != nil {
+			log.Error("Failed to list the KieApps trusting ", object.GetName(), ". ", err)
+			return nil
+		}
+		_, isConfigMap := object.(*corev1.ConfigMap)
+		var requests []reconcile.Request
+		for index := range kieApps.Items {
+			if isTruststoreSource(&kieApps.Items[index], object.GetName(), isConfigMap) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      kieApps.Items[index].Name,
+					Namespace: kieApps.Items[index].Namespace,
+				}})
+			}
+		}
+		return requests
+	}
+}
+
+// isTruststoreSource returns whether the named configmap or secret holds one of the CA bundles trusted by the KieApp
+func isTruststoreSource(cr *api.KieApp, name string, isConfigMap bool) bool {
+	truststore := cr.Spec.Truststore
+	if truststore == nil {
+		return false
+	}
+	if isConfigMap && truststore.OpenshiftCaBundle && name == cr.Name+"-kieapp-ca-bundle" {
+		return true
+	}
+	for _, source := range truststore.From {
+		if isConfigMap && source.ConfigMapKeyRef != nil && source.ConfigMapKeyRef.Name == name ||
+			!isConfigMap && source.SecretKeyRef != nil && source.SecretKeyRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ go 1.16
 require (
 	github.com/RHsyseng/console-cr-form v0.0.0-00010101000000-000000000000
 	github.com/RHsyseng/operator-utils v0.0.0-00010101000000-000000000000
+	github.com/evanphx/json-patch v4.11.0+incompatible
 	github.com/ghodss/yaml v1.0.0
 	github.com/go-logr/logr v0.4.0
 	github.com/go-openapi/spec v0.19.9
@@ -20,11 +21,12 @@ require (
 	github.com/pavel-v-chernykh/keystore-go/v4 v4.3.0
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring v0.50.0
+	github.com/prometheus/client_golang v1.11.0
 	github.com/prometheus/common v0.26.0
 	github.com/stretchr/testify v1.7.0
 	github.com/tidwall/gjson v1.14.0
 	github.com/tidwall/sjson v1.2.4
-	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2 // indirect
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
 	golang.org/x/mod v0.4.2
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	k8s.io/api v0.21.2
@@ -32,6 +34,7 @@ require (
 	k8s.io/apimachinery v0.21.2
 	k8s.io/client-go v0.21.2
 	sigs.k8s.io/controller-runtime v0.9.1 // update on github.com/RHsyseng/operator-utils and bump ocp to 4.9
+	sigs.k8s.io/gateway-api v0.3.0
 )
 
 replace (
@@ -19,6 +19,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp"
 	"github.com/spolti/kie-cloud-operator-new/controllers/kieapp/constants"
 	"github.com/spolti/kie-cloud-operator-new/core/logger"
@@ -36,7 +37,9 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 
+	certmanagerv1 "github.com/spolti/kie-cloud-operator-new/api/certmanager/v1"
 	appv2 "github.com/spolti/kie-cloud-operator-new/api/v2"
 	//+kubebuilder:scaffold:imports
 )
@@ -48,6 +51,9 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(monv1.AddToScheme(scheme))
+	utilruntime.Must(certmanagerv1.AddToScheme(scheme))
 
 	utilruntime.Must(appv2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -83,13 +89,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	platform := kieapp.DetectPlatform(mgr.GetRESTMapper())
 	if err = (&kieapp.KieAppReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Platform: platform,
+		Recorder: mgr.GetEventRecorderFor("kieapp-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "KieApp")
 		os.Exit(1)
 	}
+	if os.Getenv(constants.EnableWebhooksEnv) != "false" {
+		(&kieapp.KieAppDefaulter{Platform: platform}).SetupWebhookWithManager(mgr)
+		(&kieapp.KieAppValidator{Platform: platform}).SetupWebhookWithManager(mgr)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {